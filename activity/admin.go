@@ -50,6 +50,8 @@ func (ab *Builder) Install(b *presets.Builder) error {
 		return err
 	}
 
+	ab.installDashboardWidgets(b)
+
 	ab.logModelBuilders[b] = lmb
 	return err
 }
@@ -97,31 +99,38 @@ func setupEditing(eb *presets.EditingBuilder) {
 	})
 }
 
+// modelLabelsWithoutListPerm returns the labels of the models which the request is not allowed to list,
+// the activities of them are hidden from it.
+func (ab *Builder) modelLabelsWithoutListPerm(b *presets.Builder, r *http.Request) []string {
+	var modelLabels []string
+	// err = ab.db.Model(&ActivityLog{}).Select("DISTINCT model_label AS model_label").Pluck("model_label", &modelLabels).Error
+	// if err != nil {
+	// 	return nil, err
+	// }
+	for _, m := range ab.models {
+		if m.label != nil {
+			modelLabels = append(modelLabels, m.label())
+		}
+	}
+	signsNoPerm := []string{}
+	modelLabels = lo.Uniq(modelLabels)
+	for _, resourceSign := range modelLabels {
+		if resourceSign == "" || resourceSign == NopModelLabel {
+			continue
+		}
+		if b.GetVerifier().Spawn().SnakeOn(resourceSign).Do(presets.PermList).WithReq(r).IsAllowed() == nil {
+			continue
+		}
+		signsNoPerm = append(signsNoPerm, resourceSign)
+	}
+	return signsNoPerm
+}
+
 func setupListing(b *presets.Builder, lb *presets.ListingBuilder, op *gorm2op.DataOperatorBuilder, ab *Builder) {
 	lb.RelayPagination(gorm2op.KeysetBasedPagination(true)).KeywordSearchOff(true)
 	lb.SearchFunc(func(ctx *web.EventContext, params *presets.SearchParams) (result *presets.SearchResult, err error) {
 		if !ab.skipResPermCheck {
-			var modelLabels []string
-			// err = ab.db.Model(&ActivityLog{}).Select("DISTINCT model_label AS model_label").Pluck("model_label", &modelLabels).Error
-			// if err != nil {
-			// 	return nil, err
-			// }
-			for _, m := range ab.models {
-				if m.label != nil {
-					modelLabels = append(modelLabels, m.label())
-				}
-			}
-			signsNoPerm := []string{}
-			modelLabels = lo.Uniq(modelLabels)
-			for _, resourceSign := range modelLabels {
-				if resourceSign == "" || resourceSign == NopModelLabel {
-					continue
-				}
-				if b.GetVerifier().Spawn().SnakeOn(resourceSign).Do(presets.PermList).WithReq(ctx.R).IsAllowed() == nil {
-					continue
-				}
-				signsNoPerm = append(signsNoPerm, resourceSign)
-			}
+			signsNoPerm := ab.modelLabelsWithoutListPerm(b, ctx.R)
 			if len(signsNoPerm) > 0 {
 				params.SQLConditions = append(params.SQLConditions, &presets.SQLCondition{
 					Query: "model_label NOT IN ?",
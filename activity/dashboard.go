@@ -0,0 +1,134 @@
+package activity
+
+import (
+	"fmt"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/i18n"
+	v "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/samber/lo"
+	h "github.com/theplant/htmlgo"
+)
+
+const (
+	DashboardWidgetRecentActivity = "activity_recent"
+	DashboardWidgetUnreadNotes    = "activity_unread_notes"
+
+	dashboardRecentActivityLimit = 10
+)
+
+func (ab *Builder) installDashboardWidgets(pb *presets.Builder) {
+	d := pb.Dashboard()
+	d.Widget(DashboardWidgetRecentActivity).
+		TitleFunc(func(evCtx *web.EventContext) string {
+			return i18n.MustGetModuleMessages(evCtx.R, I18nActivityKey, Messages_en_US).(*Messages).DashboardRecentActivity
+		}).
+		Icon("mdi-history").
+		ComponentFunc(func(evCtx *web.EventContext) (h.HTMLComponent, error) {
+			return ab.recentActivityWidget(pb, evCtx)
+		})
+	d.Widget(DashboardWidgetUnreadNotes).
+		TitleFunc(func(evCtx *web.EventContext) string {
+			return i18n.MustGetModuleMessages(evCtx.R, I18nActivityKey, Messages_en_US).(*Messages).DashboardUnreadNotes
+		}).
+		Icon("mdi-note-text-outline").
+		Width(4).
+		ComponentFunc(func(evCtx *web.EventContext) (h.HTMLComponent, error) {
+			return ab.unreadNotesWidget(pb, evCtx)
+		})
+}
+
+// recentActivityWidget lists the latest activities of the models which the current user could list.
+func (ab *Builder) recentActivityWidget(pb *presets.Builder, evCtx *web.EventContext) (h.HTMLComponent, error) {
+	msgr := i18n.MustGetModuleMessages(evCtx.R, I18nActivityKey, Messages_en_US).(*Messages)
+	pmsgr := presets.MustGetMessages(evCtx.R)
+
+	db := ab.db.WithContext(evCtx.R.Context()).
+		Where("hidden = ? AND action <> ?", false, ActionLastView)
+	if !ab.skipResPermCheck {
+		if signsNoPerm := ab.modelLabelsWithoutListPerm(pb, evCtx.R); len(signsNoPerm) > 0 {
+			db = db.Where("model_label NOT IN ?", signsNoPerm)
+		}
+	}
+	var logs []*ActivityLog
+	if err := db.
+		Order("created_at DESC").
+		Limit(dashboardRecentActivityLimit).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	if len(logs) == 0 {
+		return h.Div(h.Text(msgr.NoActivitiesYet)).Class("text-medium-emphasis"), nil
+	}
+	if err := ab.supplyUsers(evCtx.R.Context(), logs); err != nil {
+		return nil, err
+	}
+
+	items := lo.Map(logs, func(log *ActivityLog, _ int) h.HTMLComponent {
+		userName := log.User.Name
+		if userName == "" {
+			userName = msgr.UnknownUser
+		}
+		item := v.VListItem(
+			v.VListItemTitle(h.Text(fmt.Sprintf("%s %s %s", userName, getActionLabel(evCtx, log.Action), log.ModelLabel))),
+			v.VListItemSubtitle(h.Text(fmt.Sprintf("%s · %s", log.ModelKeys, pmsgr.HumanizeTime(log.CreatedAt)))),
+		)
+		if log.ModelLink != "" {
+			item.Href(log.ModelLink)
+		}
+		return item
+	})
+	return v.VList(items...).Density(v.DensityCompact), nil
+}
+
+func (ab *Builder) unreadNotesWidget(pb *presets.Builder, evCtx *web.EventContext) (h.HTMLComponent, error) {
+	msgr := i18n.MustGetModuleMessages(evCtx.R, I18nActivityKey, Messages_en_US).(*Messages)
+	ctx := evCtx.R.Context()
+
+	user, err := ab.currentUserFunc(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := getNotesCounts(ab.db.WithContext(ctx), ab.tablePrefix, user.ID, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	var signsNoPerm []string
+	if !ab.skipResPermCheck {
+		signsNoPerm = ab.modelLabelsWithoutListPerm(pb, evCtx.R)
+	}
+	counts = lo.Filter(counts, func(c *NoteCount, _ int) bool {
+		return c.UnreadNotesCount > 0 && !lo.Contains(signsNoPerm, c.ModelLabel)
+	})
+	if len(counts) == 0 {
+		return h.Div(h.Text(msgr.DashboardNoUnreadNotes)).Class("text-medium-emphasis"), nil
+	}
+
+	var links []struct {
+		ModelName string
+		ModelKeys string
+		ModelLink string
+	}
+	if err := ab.db.WithContext(ctx).Model(&ActivityLog{}).
+		Select("model_name, model_keys, MAX(model_link) AS model_link").
+		Where("action = ? AND model_keys IN ?", ActionNote, lo.Map(counts, func(c *NoteCount, _ int) string { return c.ModelKeys })).
+		Group("model_name, model_keys").
+		Scan(&links).Error; err != nil {
+		return nil, err
+	}
+
+	items := lo.Map(counts, func(c *NoteCount, _ int) h.HTMLComponent {
+		item := v.VListItem(
+			v.VListItemTitle(h.Text(fmt.Sprintf("%s %s", c.ModelLabel, c.ModelKeys))),
+			web.Slot(v.VChip(h.Text(fmt.Sprint(c.UnreadNotesCount))).Color(v.ColorError).Size(v.SizeSmall)).Name("append"),
+		)
+		for _, l := range links {
+			if l.ModelName == c.ModelName && l.ModelKeys == c.ModelKeys && l.ModelLink != "" {
+				item.Href(l.ModelLink)
+			}
+		}
+		return item
+	})
+	return v.VList(items...).Density(v.DensityCompact), nil
+}
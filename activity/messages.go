@@ -74,6 +74,10 @@ type Messages struct {
 	ActivityLog  string
 
 	FilterTabsHasUnreadNotes string
+
+	DashboardRecentActivity string
+	DashboardUnreadNotes    string
+	DashboardNoUnreadNotes  string
 }
 
 func (msgr *Messages) LastEditedAt(desc string) string {
@@ -167,6 +171,10 @@ var Messages_en_US = &Messages{
 	ActivityLog:  "Activity Log",
 
 	FilterTabsHasUnreadNotes: "Has Unread Notes",
+
+	DashboardRecentActivity: "Recent Activity",
+	DashboardUnreadNotes:    "Unread Notes",
+	DashboardNoUnreadNotes:  "No unread notes",
 }
 
 var Messages_zh_CN = &Messages{
@@ -238,6 +246,10 @@ var Messages_zh_CN = &Messages{
 	ActivityLog:  "操作日志",
 
 	FilterTabsHasUnreadNotes: "未读备注",
+
+	DashboardRecentActivity: "最近操作",
+	DashboardUnreadNotes:    "未读备注",
+	DashboardNoUnreadNotes:  "没有未读备注",
 }
 
 var Messages_ja_JP = &Messages{
@@ -309,4 +321,8 @@ var Messages_ja_JP = &Messages{
 	ActivityLog:  "作業履歴",
 
 	FilterTabsHasUnreadNotes: "未読ノート",
+
+	DashboardRecentActivity: "最近のアクティビティ",
+	DashboardUnreadNotes:    "未読ノート",
+	DashboardNoUnreadNotes:  "未読ノートはありません",
 }
@@ -206,6 +206,15 @@ func (amb *ModelBuilder) installPresetModelBuilder(mb *presets.ModelBuilder) {
 
 	eb := mb.Editing()
 	eb.WrapSaveFunc(amb.WrapperSaveFunc)
+	eb.ConflictDiffFunc(func(theirs, mine any, _ *web.EventContext) ([]presets.ConflictDiff, error) {
+		diffs, err := amb.Diff(theirs, mine)
+		if err != nil {
+			return nil, err
+		}
+		return lo.Map(diffs, func(d Diff, _ int) presets.ConflictDiff {
+			return presets.ConflictDiff{Field: d.Field, Theirs: d.Old, Mine: d.New}
+		}), nil
+	})
 
 	eb.WrapDeleteFunc(func(in presets.DeleteFunc) presets.DeleteFunc {
 		return func(obj any, id string, ctx *web.EventContext) (err error) {
@@ -0,0 +1,60 @@
+package autocomplete
+
+import (
+	"fmt"
+
+	"github.com/qor5/web/v3"
+	v "github.com/qor5/x/v3/ui/vuetify"
+	h "github.com/theplant/htmlgo"
+)
+
+const infiniteScrollSearchDelay = 300
+
+// InfiniteScroll renders the autocomplete with the records of the model which has the RelayPagination,
+// the next page is loaded when the end of the list is scrolled into view and the keyword is searched on the server.
+// selectedItems are the items of the current value, so that they are displayed before any page is loaded.
+func (b *ModelBuilder) InfiniteScroll(ac *v.VAutocompleteBuilder, selectedItems interface{}, pageSize int) h.HTMLComponent {
+	if selectedItems == nil {
+		selectedItems = []interface{}{}
+	}
+	return web.Scope(
+		ac.NoFilter(true).
+			Attr(":items", "acLocals.items").
+			Attr(":loading", "acLocals.loading").
+			Attr("@update:search", fmt.Sprintf(
+				"acLocals.search = $event; clearTimeout(acLocals.timer); acLocals.timer = setTimeout(() => { %s }, %d)",
+				b.infiniteScrollLoad(pageSize, true), infiniteScrollSearchDelay)).
+			Attr("@update:menu", fmt.Sprintf("$event && !acLocals.loaded && (%s)", b.infiniteScrollLoad(pageSize, true))).
+			Children(
+				web.Slot(
+					h.Div().Attr("v-if", "acLocals.hasNextPage").
+						Attr("v-intersect", fmt.Sprintf("(isIntersecting) => isIntersecting && acLocals.loaded && (%s)", b.infiniteScrollLoad(pageSize, false))),
+				).Name("append-item"),
+			),
+	).VSlot("{ locals: acLocals }").
+		Init(fmt.Sprintf(`{ items: %s, search: "", endCursor: "", hasNextPage: true, loading: false, loaded: false, timer: null, seq: 0 }`, h.JSONString(selectedItems)))
+}
+
+// infiniteScrollLoad fetches the page after the endCursor, or the first page if reset.
+// Each load is numbered, the responses of the loads started before the latest one are dropped,
+// so that a slow response of an older keyword doesn't overwrite the items.
+func (b *ModelBuilder) infiniteScrollLoad(pageSize int, reset bool) string {
+	return fmt.Sprintf(`(() => {
+	const reset = %t
+	if (!reset && (acLocals.loading || !acLocals.hasNextPage)) { return }
+	const url = new URL(%q, window.location.href)
+	url.searchParams.set(%q, %d)
+	if (acLocals.search) { url.searchParams.set(%q, acLocals.search) }
+	if (!reset && acLocals.endCursor) { url.searchParams.set(%q, acLocals.endCursor) }
+	const seq = ++acLocals.seq
+	acLocals.loading = true
+	fetch(url).then((r) => r.json()).then((r) => {
+		if (seq !== acLocals.seq) { return }
+		acLocals.items = reset ? r[%q] : acLocals.items.concat(r[%q])
+		acLocals.endCursor = r[%q] || ""
+		acLocals.hasNextPage = r[%q]
+		acLocals.loaded = true
+	}).finally(() => { if (seq === acLocals.seq) { acLocals.loading = false } })
+})()`, reset, b.JsonHref(), ParamPageSize, pageSize, ParamSearch, ParamAfter,
+		ResponseItems, ResponseItems, ResponseEndCursor, ResponseHasNextPage)
+}
@@ -11,7 +11,9 @@ import (
 	"testing"
 
 	"github.com/qor5/admin/v3/autocomplete"
+	"github.com/qor5/admin/v3/presets/gorm2op"
 	"github.com/qor5/x/v3/gormx"
+	"github.com/theplant/relay"
 
 	"github.com/theplant/gofixtures"
 	"gorm.io/gorm"
@@ -67,6 +69,8 @@ func Handler(db *gorm.DB) http.Handler {
 	b := autocomplete.New().DB(db).Prefix("/complete").AllowCrossOrigin(true)
 	b.Model(&Category{}).Columns("id", "name", "path").OrderBy("id desc")
 	b.Model(&User{}).Columns("id", "name", "age").SQLCondition("name ilike ?")
+	b.Model(&User{}).UriName("relay-users").Columns("id", "name", "age").SQLCondition("name ilike ?").
+		RelayPagination(gorm2op.KeysetBasedPagination(true), relay.Order{Field: "Age", Direction: relay.OrderDirectionDesc})
 	mux.Handle("/complete/", b)
 	return b
 }
@@ -185,3 +189,56 @@ func TestCategoryPage(t *testing.T) {
 		return
 	}
 }
+
+func TestUserRelayPagination(t *testing.T) {
+	handler := Handler(TestDB)
+	dbr, _ := TestDB.DB()
+	t.Log("Test Users Relay Pagination")
+	autocompleteData.TruncatePut(dbr)
+
+	var (
+		names []string
+		after string
+	)
+	for i := 0; i < 3; i++ {
+		var response autocomplete.Response
+		bytes := runTest(t, httptest.NewRequest("GET", "/complete/relay-users?search=k&pageSize=2&after="+after, http.NoBody), handler)
+		if err := json.Unmarshal(bytes.Bytes(), &response); err != nil {
+			t.Fatalf("json unmarshal faield :%v", err)
+			return
+		}
+		if response.Total != 0 {
+			t.Fatalf("except total count skipped but get %v", response.Total)
+			return
+		}
+		for _, item := range response.Data {
+			names = append(names, item["name"].(string))
+		}
+		if !response.HasNextPage {
+			break
+		}
+		after = response.EndCursor
+	}
+	if fmt.Sprint(names) != "[k2 k1]" {
+		t.Fatalf("except get [k2 k1] but get %v", names)
+		return
+	}
+}
+
+func TestUserRelayPaginationInvalidPageSize(t *testing.T) {
+	handler := Handler(TestDB)
+	dbr, _ := TestDB.DB()
+	autocompleteData.TruncatePut(dbr)
+
+	var response autocomplete.Response
+	bytes := runTest(t, httptest.NewRequest("GET", "/complete/relay-users?search=k&pageSize=-1", http.NoBody), handler)
+	if err := json.Unmarshal(bytes.Bytes(), &response); err != nil {
+		t.Fatalf("json unmarshal faield :%v", err)
+		return
+	}
+	// the invalid page size falls back to the default one
+	if len(response.Data) != 2 || response.HasNextPage {
+		t.Fatalf("except get 2 items in one page but get %v", response.Data)
+		return
+	}
+}
@@ -8,7 +8,14 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/qor5/web/v3"
+	"github.com/samber/lo"
+	"github.com/theplant/relay"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/admin/v3/presets/gorm2op"
 )
 
 type (
@@ -21,6 +28,9 @@ type (
 		modelType    reflect.Type
 		paging       bool
 		columns      []string
+
+		relayPagination presets.RelayPagination
+		relayOrderBy    []relay.Order
 	}
 
 	Response struct {
@@ -28,6 +38,10 @@ type (
 		Total   int64                    `json:"total"`
 		Pages   int                      `json:"pages"`
 		Current int64                    `json:"current"`
+
+		// EndCursor and HasNextPage are only returned with the RelayPagination
+		EndCursor   string `json:"endCursor,omitempty"`
+		HasNextPage bool   `json:"hasNextPage"`
 	}
 )
 
@@ -35,10 +49,14 @@ const (
 	ParamPage       = "page"
 	ParamPageSize   = "pageSize"
 	ParamSearch     = "search"
+	ParamAfter      = "after"
 	ResponseItems   = "data"
 	ResponseTotal   = "total"
 	ResponsePages   = "pages"
 	ResponseCurrent = "current"
+
+	ResponseEndCursor   = "endCursor"
+	ResponseHasNextPage = "hasNextPage"
 )
 
 func (b *ModelBuilder) Columns(v ...string) *ModelBuilder {
@@ -66,6 +84,15 @@ func (b *ModelBuilder) Paging(v bool) *ModelBuilder {
 	return b
 }
 
+// RelayPagination pages the records by the cursor of the ParamAfter instead of the page offset,
+// like gorm2op.KeysetBasedPagination(true) which doesn't count the total for large tables.
+// The primary key is appended to orderBy for a stable order, the OrderBy string is ignored.
+func (b *ModelBuilder) RelayPagination(v presets.RelayPagination, orderBy ...relay.Order) *ModelBuilder {
+	b.relayPagination = v
+	b.relayOrderBy = orderBy
+	return b
+}
+
 func (b *ModelBuilder) JsonHref() string {
 	return fmt.Sprintf("%s/%s", b.p.prefix, b.uriName)
 }
@@ -104,6 +131,15 @@ func (b *ModelBuilder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		g = g.Where(b.sQLCondition, fmt.Sprintf("%%%s%%", ctx.Param(ParamSearch)))
 	}
 
+	if b.relayPagination != nil {
+		if err := b.relayPaginate(ctx, &response); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		b.writeJSON(w, response)
+		return
+	}
+
 	if err := g.Count(&response.Total).Error; err != nil {
 		return
 	}
@@ -129,6 +165,10 @@ func (b *ModelBuilder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if err := g.Select(strings.Join(b.columns, ",")).Find(&response.Data).Error; err != nil {
 		return
 	}
+	b.writeJSON(w, response)
+}
+
+func (b *ModelBuilder) writeJSON(w http.ResponseWriter, response Response) {
 	// 将结构体编码为 JSON
 	jsonResponse, err := json.Marshal(response)
 	if err != nil {
@@ -140,3 +180,67 @@ func (b *ModelBuilder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(jsonResponse)
 }
+
+// relayPaginate fetches the page after the cursor with the gorm2op.DataOperatorBuilder like the listings,
+// Total and Current are only set if the RelayPagination counts the total.
+func (b *ModelBuilder) relayPaginate(ctx *web.EventContext, response *Response) error {
+	db := b.p.db
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(b.NewModel()); err != nil {
+		return errors.Wrap(err, "parse model")
+	}
+	if stmt.Schema.PrioritizedPrimaryField == nil {
+		return errors.Errorf("%s has no primary key", stmt.Schema.Name)
+	}
+
+	pageSize := ctx.ParamAsInt(ParamPageSize)
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	pageSize = min(pageSize, presets.PerPageMax)
+	req := &relay.PaginateRequest[any]{
+		First: lo.ToPtr(pageSize),
+		OrderBy: relay.AppendPrimaryOrderBy(b.relayOrderBy, relay.Order{
+			Field:     stmt.Schema.PrioritizedPrimaryField.Name,
+			Direction: relay.OrderDirectionAsc,
+		}),
+	}
+	if after := ctx.Param(ParamAfter); after != "" {
+		req.After = lo.ToPtr(after)
+	}
+	params := &presets.SearchParams{
+		Model:                b.NewModel(),
+		RelayPaginateRequest: req,
+		RelayPagination:      b.relayPagination,
+	}
+	if b.sQLCondition != "" {
+		params.SQLConditions = []*presets.SQLCondition{{
+			Query: b.sQLCondition,
+			Args:  []interface{}{fmt.Sprintf("%%%s%%", ctx.Param(ParamSearch))},
+		}}
+	}
+	result, err := gorm2op.DataOperator(db).Search(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	nodes := reflect.ValueOf(result.Nodes)
+	for i := 0; i < nodes.Len(); i++ {
+		node := nodes.Index(i).Elem()
+		item := map[string]interface{}{}
+		for _, column := range b.columns {
+			if field := stmt.Schema.LookUpField(column); field != nil {
+				item[column], _ = field.ValueOf(ctx.R.Context(), node)
+			}
+		}
+		response.Data = append(response.Data, item)
+	}
+	if result.PageInfo.EndCursor != nil {
+		response.EndCursor = *result.PageInfo.EndCursor
+	}
+	response.HasNextPage = result.PageInfo.HasNextPage
+	if result.TotalCount != nil {
+		response.Total = int64(*result.TotalCount)
+	}
+	return nil
+}
@@ -2,15 +2,23 @@ package main
 
 import (
 	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/qor5/admin/v3/example/admin"
 	"github.com/qor5/admin/v3/publish"
 )
 
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	db := admin.ConnectDB()
 	config := admin.NewConfig(db, false)
 	storage := admin.PublishStorage
-	publish.RunPublisher(context.Background(), db, storage, config.Publisher)
-	select {}
+	if err := publish.NewPublisherScheduler(db, storage, config.Publisher).Run(ctx); err != nil {
+		log.Fatal(err)
+	}
 }
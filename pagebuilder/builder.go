@@ -480,7 +480,7 @@ func (b *Builder) configPublish(r *ModelBuilder) {
 	if publisher != nil {
 		publisher.ContextValueFuncs(r.ContextValueProvider).Activity(b.ab).AfterInstall(func() {
 			r.mb.Editing().SidePanelFunc(nil).ActionsFunc(nil).TabsPanels()
-		})
+		}).VersionField(r.mb.NewModel(), r.containersVersionField())
 	}
 }
 
@@ -1094,6 +1094,9 @@ func (b *ContainerBuilder) Install() {
 				if dbErr = b.builder.updateAllContainersUpdatedTimeFromModel(tx, id); dbErr != nil {
 					return
 				}
+				if dbErr = b.builder.resetContainerPagesReview(tx, b.name, id); dbErr != nil {
+					return
+				}
 
 				return
 			}); err != nil {
@@ -1726,6 +1729,27 @@ func (b *Builder) updateAllContainersUpdatedTimeFromModel(tx *gorm.DB, modelID s
 	return tx.Model(&Container{}).Where("model_id = ? and shared = true ", modelID).Update("updated_at", time.Now()).Error
 }
 
+// resetContainerPagesReview withdraws the reviews of the pages using the container, as their content is changed.
+func (b *Builder) resetContainerPagesReview(tx *gorm.DB, modelName, modelID string) (err error) {
+	if modelID == "" {
+		return
+	}
+	var cons []*Container
+	if err = tx.Where("model_name = ? AND model_id = ?", modelName, modelID).Find(&cons).Error; err != nil {
+		return
+	}
+	for _, con := range cons {
+		m := b.getModelBuilderByName(con.PageModelName)
+		if m == nil {
+			continue
+		}
+		if err = m.resetPageReview(tx, int(con.PageID), con.PageVersion, con.LocaleCode); err != nil {
+			return
+		}
+	}
+	return
+}
+
 func (b *Builder) localizeModel(db *gorm.DB, obj interface{}, fromID, fromLocale string) (err error) {
 	var sharedCon Container
 	if err = db.Where("id = ? AND locale_code = ? AND shared = ?  ",
@@ -3,6 +3,7 @@ package pagebuilder
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -794,6 +795,52 @@ func (b *ModelBuilder) copyContainersToAnotherPage(db *gorm.DB, pageID int, page
 	return
 }
 
+// containersVersionField compares the containers of the versions of the pages by the contents of the models,
+// and merges them by replacing the containers of the draft with the copies of the other version's.
+func (b *ModelBuilder) containersVersionField() *publish.VersionField {
+	return &publish.VersionField{
+		Name: "Containers",
+		ValueFunc: func(_ context.Context, db *gorm.DB, obj any) (string, error) {
+			pageID, pageVersion, locale := b.primaryColumnValuesBySlug(obj.(presets.SlugEncoder).PrimarySlug())
+			var cons []*Container
+			if err := db.Order("display_order ASC").Find(&cons, "page_id = ? AND page_version = ? AND locale_code = ? and page_model_name = ? ", pageID, pageVersion, locale, b.name).Error; err != nil {
+				return "", err
+			}
+			buildeContainer := b.getContainerBuilders()
+			var lines []string
+			for _, c := range cons {
+				if !slices.ContainsFunc(buildeContainer, func(builder *ContainerBuilder) bool {
+					return c.ModelName == builder.name
+				}) {
+					continue
+				}
+				model := b.builder.ContainerByName(c.ModelName).NewModel()
+				if err := db.First(model, "id = ?", c.ModelID).Error; err != nil {
+					return "", err
+				}
+				// the copies of the containers have different ids, only the contents are compared
+				if err := reflectutils.Set(model, "ID", uint(0)); err != nil {
+					return "", err
+				}
+				data, err := json.Marshal(model)
+				if err != nil {
+					return "", err
+				}
+				lines = append(lines, fmt.Sprintf("%s: %s", c.DisplayName, data))
+			}
+			return strings.Join(lines, "\n"), nil
+		},
+		MergeFunc: func(_ context.Context, tx *gorm.DB, from, to any) error {
+			pageID, fromVersion, locale := b.primaryColumnValuesBySlug(from.(presets.SlugEncoder).PrimarySlug())
+			_, toVersion, _ := b.primaryColumnValuesBySlug(to.(presets.SlugEncoder).PrimarySlug())
+			if err := tx.Delete(&Container{}, "page_id = ? AND page_version = ? AND locale_code = ? and page_model_name = ? ", pageID, toVersion, locale, b.name).Error; err != nil {
+				return err
+			}
+			return b.copyContainersToAnotherPage(tx, pageID, fromVersion, locale, pageID, toVersion, locale, b.name, b.name)
+		},
+	}
+}
+
 func (b *ModelBuilder) localizeContainersToAnotherPage(db *gorm.DB, pageID int, pageVersion, locale string, toPageID int, toPageVersion, toPageLocale string) (err error) {
 	var cons []*Container
 	err = db.Order("display_order ASC").
@@ -39,18 +39,18 @@ func (b *ModelBuilder) registerFuncs() {
 
 func (b *ModelBuilder) registerCustomFuncs() {
 	b.editor.RegisterEventFunc(ShowSortedContainerDrawerEvent, b.eventMiddleware(b.showSortedContainerDrawer))
-	b.editor.RegisterEventFunc(AddContainerEvent, b.eventMiddleware(b.addContainer))
+	b.editor.RegisterEventFunc(AddContainerEvent, b.eventMiddleware(b.withPageReviewReset(b.addContainer)))
 	b.editor.RegisterEventFunc(DeleteContainerConfirmationEvent, b.eventMiddleware(b.deleteContainerConfirmation))
-	b.editor.RegisterEventFunc(DeleteContainerEvent, b.eventMiddleware(b.deleteContainer))
-	b.editor.RegisterEventFunc(MoveContainerEvent, b.eventMiddleware(b.moveContainer))
-	b.editor.RegisterEventFunc(MoveUpDownContainerEvent, b.eventMiddleware(b.moveUpDownContainer))
-	b.editor.RegisterEventFunc(ToggleContainerVisibilityEvent, b.eventMiddleware(b.toggleContainerVisibility))
-	b.editor.RegisterEventFunc(RenameContainerEvent, b.eventMiddleware(b.renameContainer))
+	b.editor.RegisterEventFunc(DeleteContainerEvent, b.eventMiddleware(b.withPageReviewReset(b.deleteContainer)))
+	b.editor.RegisterEventFunc(MoveContainerEvent, b.eventMiddleware(b.withPageReviewReset(b.moveContainer)))
+	b.editor.RegisterEventFunc(MoveUpDownContainerEvent, b.eventMiddleware(b.withPageReviewReset(b.moveUpDownContainer)))
+	b.editor.RegisterEventFunc(ToggleContainerVisibilityEvent, b.eventMiddleware(b.withPageReviewReset(b.toggleContainerVisibility)))
+	b.editor.RegisterEventFunc(RenameContainerEvent, b.eventMiddleware(b.withPageReviewReset(b.renameContainer)))
 	b.editor.RegisterEventFunc(ReloadRenderPageOrTemplateEvent, b.reloadRenderPageOrTemplate)
 	b.editor.RegisterEventFunc(ReloadRenderPageOrTemplateBodyEvent, b.reloadRenderPageOrTemplateBody)
-	b.editor.RegisterEventFunc(MarkAsSharedContainerEvent, b.eventMiddleware(b.markAsSharedContainer))
+	b.editor.RegisterEventFunc(MarkAsSharedContainerEvent, b.eventMiddleware(b.withPageReviewReset(b.markAsSharedContainer)))
 	b.editor.RegisterEventFunc(ContainerPreviewEvent, b.eventMiddleware(b.containerPreview))
-	b.editor.RegisterEventFunc(ReplicateContainerEvent, b.eventMiddleware(b.replicateContainer))
+	b.editor.RegisterEventFunc(ReplicateContainerEvent, b.eventMiddleware(b.withPageReviewReset(b.replicateContainer)))
 	b.editor.RegisterEventFunc(EditContainerEvent, b.eventMiddleware(b.editContainer))
 	b.editor.RegisterEventFunc(UpdateContainerEvent, b.eventMiddleware(b.updateContainer))
 	b.editor.RegisterEventFunc(ReloadAddContainersListEvent, b.eventMiddleware(b.reloadAddContainersList))
@@ -127,6 +127,43 @@ func (b *ModelBuilder) defaultWrapEvent(in web.EventFunc) web.EventFunc {
 	}
 }
 
+// withPageReviewReset withdraws the review of the page once the event changed its containers.
+func (b *ModelBuilder) withPageReviewReset(in web.EventFunc) web.EventFunc {
+	return func(ctx *web.EventContext) (r web.EventResponse, err error) {
+		if r, err = in(ctx); err != nil {
+			return
+		}
+		pageID, pageVersion, locale := b.getPrimaryColumnValuesBySlug(ctx)
+		err = b.resetPageReview(b.db, pageID, pageVersion, locale)
+		return
+	}
+}
+
+// resetPageReview withdraws the review of the page like the editing Saver of publish does,
+// for the changes of its containers which are not saved through it.
+func (b *ModelBuilder) resetPageReview(tx *gorm.DB, pageID int, pageVersion, locale string) (err error) {
+	page := b.mb.NewModel()
+	if _, ok := page.(publish.ReviewInterface); !ok || pageID == 0 {
+		return
+	}
+	wh := tx.Where("id = ?", pageID)
+	if pageVersion != "" {
+		wh = wh.Where("version = ?", pageVersion)
+	}
+	if locale != "" {
+		wh = wh.Where("locale_code = ?", locale)
+	}
+	if err = wh.First(page).Error; err != nil {
+		return
+	}
+	if !publish.ResetReview(page) {
+		return
+	}
+	return tx.Model(page).
+		Select("ReviewStatus", "SubmitterID", "ReviewerID", "ReviewerName", "ReviewComment", "ReviewedAt").
+		Updates(page).Error
+}
+
 func (b *ModelBuilder) showSortedContainerDrawer(ctx *web.EventContext) (r web.EventResponse, err error) {
 	var body h.HTMLComponent
 	if body, err = b.renderContainersSortedList(ctx); err != nil {
@@ -0,0 +1,64 @@
+package pagebuilder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/admin/v3/publish"
+)
+
+type versionTestHeading struct {
+	ID   uint
+	Text string
+}
+
+func TestContainersVersionField(t *testing.T) {
+	require.NoError(t, TestDB.AutoMigrate(&Page{}, &Container{}, &versionTestHeading{}))
+	b := New("/", TestDB, presets.New())
+	b.RegisterContainer("VersionTestHeading").Model(&versionTestHeading{})
+	r := b.Model(b.pb.Model(&Page{}))
+	f := r.containersVersionField()
+
+	from := &Page{Model: gorm.Model{ID: 1001}, Title: "from", Version: publish.Version{Version: "v1"}}
+	to := &Page{Model: gorm.Model{ID: 1001}, Title: "to", Version: publish.Version{Version: "v2"}}
+	for _, p := range []*Page{from, to} {
+		require.NoError(t, TestDB.Create(p).Error)
+		heading := &versionTestHeading{Text: p.Title}
+		require.NoError(t, TestDB.Create(heading).Error)
+		require.NoError(t, TestDB.Create(&Container{
+			PageID:        p.ID,
+			PageVersion:   p.Version.Version,
+			PageModelName: r.name,
+			ModelName:     "VersionTestHeading",
+			ModelID:       heading.ID,
+			DisplayName:   "Heading",
+		}).Error)
+	}
+
+	ctx := context.Background()
+	fromValue, err := f.ValueFunc(ctx, TestDB, from)
+	require.NoError(t, err)
+	require.Equal(t, `Heading: {"ID":0,"Text":"from"}`, fromValue)
+	toValue, err := f.ValueFunc(ctx, TestDB, to)
+	require.NoError(t, err)
+	require.NotEqual(t, fromValue, toValue)
+
+	require.NoError(t, TestDB.Transaction(func(tx *gorm.DB) error {
+		return f.MergeFunc(ctx, tx, from, to)
+	}))
+	toValue, err = f.ValueFunc(ctx, TestDB, to)
+	require.NoError(t, err)
+	require.Equal(t, fromValue, toValue)
+
+	// the containers of the draft are replaced by the copies
+	var cons []*Container
+	require.NoError(t, TestDB.Find(&cons, "page_id = ? AND page_version = ?", to.ID, "v2").Error)
+	require.Len(t, cons, 1)
+	var fromCon Container
+	require.NoError(t, TestDB.First(&fromCon, "page_id = ? AND page_version = ?", from.ID, "v1").Error)
+	require.NotEqual(t, fromCon.ModelID, cons[0].ModelID)
+}
@@ -12,6 +12,11 @@ const (
 	DetailingDrawer    = "presets_DetailingDrawer"
 	DeleteConfirmation = "presets_DeleteConfirmation"
 	OpenListingDialog  = "presets_OpenListingDialog"
+	GlobalSearch       = "presets_GlobalSearch"
+	DashboardUpdate    = "presets_DashboardUpdate"
+	SaveDraft          = "presets_SaveDraft"
+	RestoreDraft       = "presets_RestoreDraft"
+	DiscardDraft       = "presets_DiscardDraft"
 
 	// list editor
 	AddRowEvent    = "listEditor_addRowEvent"
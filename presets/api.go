@@ -75,6 +75,12 @@ type (
 		Not       *Filter         `json:"not"`
 		Condition *FieldCondition `json:"condition"`
 	}
+	// KeywordSearch is a keyword search strategy of the DataOperator, like gorm2op.PostgresFullTextSearch,
+	// DataOperators should fail to search with the strategies they don't support.
+	KeywordSearch interface {
+		KeywordSearchName() string
+	}
+
 	SearchParams struct {
 		Model   any
 		PageURL *url.URL
@@ -83,6 +89,16 @@ type (
 		Keyword        string
 		SQLConditions  []*SQLCondition
 		Filter         *Filter
+		// FilterInSQLConditions reports the Filter is already translated into SQLConditions,
+		// like the filter of the listing, so DataOperators should not apply it again
+		FilterInSQLConditions bool
+		// Trashed lists the soft deleted records only, see ModelBuilder.Trash
+		Trashed bool
+
+		// KeywordSearch replaces the default ILIKE search of the Keyword on the KeywordColumns
+		KeywordSearch KeywordSearch
+		// OrderByRelevance orders the results by the relevance of the KeywordSearch before OrderBy
+		OrderByRelevance bool
 
 		Page    int64
 		PerPage int64
@@ -13,6 +13,7 @@ type BulkActionBuilder struct {
 
 	dialogWidth string
 	buttonColor string
+	readOnly    bool
 }
 
 func getBulkAction(actions []*BulkActionBuilder, name string) *BulkActionBuilder {
@@ -64,6 +65,13 @@ func (b *BulkActionBuilder) ButtonColor(v string) (r *BulkActionBuilder) {
 	return b
 }
 
+// ReadOnly shows the component in a dialog which could only be closed, for the actions which only display
+// the selected records, the UpdateFunc is not required then.
+func (b *BulkActionBuilder) ReadOnly(v bool) (r *BulkActionBuilder) {
+	b.readOnly = v
+	return b
+}
+
 func (b *ListingBuilder) BulkAction(name string) (r *BulkActionBuilder) {
 	builder := getBulkAction(b.bulkActions, name)
 	if builder != nil {
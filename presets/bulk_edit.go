@@ -0,0 +1,227 @@
+package presets
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/i18n"
+	"github.com/qor5/x/v3/perm"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/samber/lo"
+	h "github.com/theplant/htmlgo"
+)
+
+const (
+	BulkActionBulkEdit = "BulkEdit"
+
+	paramBulkEditFields  = "presets_bulk_edit_fields"
+	bulkEditDialogWidth  = "800"
+	bulkEditFailuresShow = 20
+)
+
+// TransactionDataOperator is implemented by data operators which could run several saves in a transaction,
+// see gorm2op.DataOperatorBuilder.
+type TransactionDataOperator interface {
+	// Transaction runs f with the context whose fetches and saves are in the transaction,
+	// it is rolled back if f returns an error.
+	Transaction(ctx *web.EventContext, f func(txCtx *web.EventContext) error) (err error)
+}
+
+type BulkEditFailure struct {
+	ID     string
+	Errors []string
+}
+
+type ctxKeyBulkEditFailures struct{}
+
+// BulkEditAction adds the bulk action to change the chosen fields of the selected records to the same values,
+// fields default to the editing fields. Every record goes through the SetterFunc, the ValidateFunc and the SaveFunc
+// of the editing, if any record fails, the failures are listed in the dialog. The records are saved in a transaction
+// if the DataOperator is a TransactionDataOperator, so either all or none of them are changed.
+func (b *ListingBuilder) BulkEditAction(fields ...string) (r *BulkActionBuilder) {
+	mb := b.mb
+	names := func() []string {
+		if len(fields) > 0 {
+			return fields
+		}
+		return mb.editing.shownFieldNames()
+	}
+	return b.BulkAction(BulkActionBulkEdit).
+		DialogWidth(bulkEditDialogWidth).
+		ComponentFunc(func(selectedIds []string, ctx *web.EventContext) h.HTMLComponent {
+			return mb.bulkEditComponent(selectedIds, names(), ctx)
+		}).
+		UpdateFunc(func(selectedIds []string, ctx *web.EventContext, r *web.EventResponse) (err error) {
+			return mb.bulkEdit(selectedIds, names(), ctx, r)
+		})
+}
+
+// formValues returns all the values of the name, the form values posted by plaid are in the MultipartForm.
+func formValues(r *http.Request, name string) []string {
+	_ = r.FormValue(name)
+	if r.MultipartForm != nil {
+		if vs, ok := r.MultipartForm.Value[name]; ok {
+			return vs
+		}
+	}
+	return r.Form[name]
+}
+
+// bulkEditFields returns the names the current user could update in the order of the names.
+func (mb *ModelBuilder) bulkEditFields(names []string, ctx *web.EventContext) []string {
+	return lo.Filter(names, func(name string, _ int) bool {
+		return mb.editing.GetField(name) != nil &&
+			mb.Info().Verifier().Do(PermUpdate).SnakeOn("f_"+name).WithReq(ctx.R).IsAllowed() == nil
+	})
+}
+
+func (mb *ModelBuilder) bulkEditComponent(selectedIds []string, names []string, ctx *web.EventContext) h.HTMLComponent {
+	msgr := mb.mustGetMessages(ctx.R)
+	info := mb.Info()
+	names = mb.bulkEditFields(names, ctx)
+	chosen := lo.Intersect(formValues(ctx.R, paramBulkEditFields), names)
+
+	// keeps the values when the dialog is rendered again with the failures
+	obj := mb.NewModel()
+	if len(chosen) > 0 {
+		_ = mb.editing.FieldsBuilder.Only(lo.ToAnySlice(chosen)...).Unmarshal(obj, info, false, ctx)
+	}
+	vErr, _ := ctx.Flash.(*web.ValidationErrors)
+	if vErr == nil {
+		vErr = &web.ValidationErrors{}
+	}
+
+	items := lo.Map(names, func(name string, _ int) map[string]string {
+		label := i18n.PT(ctx.R, ModelsI18nModuleKey, mb.label, mb.editing.getLabel(mb.editing.GetField(name).NameLabel))
+		return map[string]string{"text": label, "value": name}
+	})
+	var fieldComps []h.HTMLComponent
+	for _, name := range names {
+		fieldComps = append(fieldComps, h.Div(
+			mb.editing.fieldToComponentWithFormValueKey(info, obj, "", ctx, name, true, vErr),
+		).Attr("v-if", fmt.Sprintf("(form[%q] || []).includes(%q)", paramBulkEditFields, name)))
+	}
+
+	return h.Div(
+		h.Div(h.Text(msgr.BulkEditSelectedRecords(len(selectedIds)))).Class("mb-4 text-medium-emphasis"),
+		VAutocomplete().Label(msgr.BulkEditFields).
+			Items(items).ItemTitle("text").ItemValue("value").
+			Multiple(true).Chips(true).ClosableChips(true).
+			Attr(web.VField(paramBulkEditFields, chosen)...),
+		h.Components(fieldComps...),
+		mb.bulkEditFailures(ctx),
+	)
+}
+
+func (mb *ModelBuilder) bulkEditFailures(ctx *web.EventContext) h.HTMLComponent {
+	failures, _ := ctx.ContextValue(ctxKeyBulkEditFailures{}).([]*BulkEditFailure)
+	if len(failures) == 0 {
+		return nil
+	}
+	var items []h.HTMLComponent
+	for _, f := range lo.Slice(failures, 0, bulkEditFailuresShow) {
+		items = append(items, h.Li(h.Text(fmt.Sprintf("%s: %s", f.ID, strings.Join(f.Errors, "; ")))))
+	}
+	if len(failures) > bulkEditFailuresShow {
+		items = append(items, h.Li(h.Text(fmt.Sprintf("...(+%d)", len(failures)-bulkEditFailuresShow))))
+	}
+	return VAlert(h.Ul(items...).Class("pl-4")).Type(TypeError).Variant(VariantTonal).Density(DensityCompact).Class("mt-4")
+}
+
+func (mb *ModelBuilder) bulkEdit(selectedIds []string, names []string, ctx *web.EventContext, r *web.EventResponse) (err error) {
+	msgr := mb.mustGetMessages(ctx.R)
+	chosen := lo.Intersect(formValues(ctx.R, paramBulkEditFields), mb.bulkEditFields(names, ctx))
+	if len(chosen) == 0 {
+		return errors.New(msgr.BulkEditNoFields)
+	}
+	fb := mb.editing.FieldsBuilder.Only(lo.ToAnySlice(chosen)...)
+
+	var (
+		failures []*BulkEditFailure
+		saved    map[string]any
+	)
+	run := func(ctx *web.EventContext) error {
+		failures, saved = nil, map[string]any{}
+		for _, id := range selectedIds {
+			obj, errs := mb.bulkEditRecord(fb, id, ctx)
+			if len(errs) > 0 {
+				failures = append(failures, &BulkEditFailure{ID: id, Errors: errs})
+				continue
+			}
+			saved[id] = obj
+		}
+		if len(failures) > 0 {
+			return errors.New(msgr.BulkEditFailed(len(failures)))
+		}
+		return nil
+	}
+
+	op, inTransaction := mb.p.dataOperator.(TransactionDataOperator)
+	if inTransaction {
+		err = op.Transaction(ctx, run)
+	} else {
+		err = run(ctx)
+	}
+	if len(saved) > 0 && !(inTransaction && err != nil) {
+		ids := lo.Keys(saved)
+		sort.Strings(ids)
+		r.Emit(mb.NotifModelsUpdated(), PayloadModelsUpdated{Ids: ids, Models: saved})
+	}
+	if len(failures) > 0 {
+		ctx.WithContextValue(ctxKeyBulkEditFailures{}, failures)
+		if inTransaction {
+			return errors.New(msgr.BulkEditFailedRolledBack(len(failures)))
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	ShowMessage(r, msgr.SuccessfullyUpdated, "")
+	return nil
+}
+
+func (mb *ModelBuilder) bulkEditRecord(fb *FieldsBuilder, id string, ctx *web.EventContext) (obj any, errs []string) {
+	eb := mb.editing
+	obj, err := eb.Fetcher(mb.NewModel(), id, ctx)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	if mb.Info().Verifier().Do(PermUpdate).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil {
+		return nil, []string{perm.PermissionDenied.Error()}
+	}
+
+	if eb.Setter != nil {
+		eb.Setter(obj, ctx)
+	}
+	vErr := fb.Unmarshal(obj, mb.Info(), false, ctx)
+	if eb.Validator != nil {
+		vErrValidator := eb.Validator(obj, ctx)
+		_ = vErr.Merge(&vErrValidator)
+	}
+	if vErr.HaveErrors() {
+		return nil, validationErrorMessages(&vErr)
+	}
+	if err := eb.Saver(obj, id, ctx); err != nil {
+		return nil, []string{err.Error()}
+	}
+	return obj, nil
+}
+
+// validationErrorMessages flattens the global errors and the field errors ordered by the field names.
+func validationErrorMessages(vErr *web.ValidationErrors) (r []string) {
+	r = append(r, vErr.GetGlobalErrors()...)
+	fieldErrors := vErr.FieldErrors()
+	names := lo.Keys(fieldErrors)
+	sort.Strings(names)
+	for _, name := range names {
+		for _, msg := range fieldErrors[name] {
+			r = append(r, fmt.Sprintf("%s: %s", name, msg))
+		}
+	}
+	return
+}
@@ -0,0 +1,131 @@
+package presets
+
+import (
+	"maps"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bulkEditProduct struct {
+	ID    uint
+	Name  string
+	Price int
+}
+
+type bulkEditDataOperator struct {
+	records map[string]bulkEditProduct
+}
+
+func (op *bulkEditDataOperator) Search(*web.EventContext, *SearchParams) (*SearchResult, error) {
+	return &SearchResult{}, nil
+}
+
+func (op *bulkEditDataOperator) Fetch(_ any, id string, _ *web.EventContext) (any, error) {
+	p, ok := op.records[id]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+	return &p, nil
+}
+
+func (op *bulkEditDataOperator) Save(obj any, id string, _ *web.EventContext) error {
+	op.records[id] = *obj.(*bulkEditProduct)
+	return nil
+}
+
+func (op *bulkEditDataOperator) Delete(_ any, id string, _ *web.EventContext) error {
+	delete(op.records, id)
+	return nil
+}
+
+type bulkEditTxDataOperator struct {
+	bulkEditDataOperator
+}
+
+func (op *bulkEditTxDataOperator) Transaction(ctx *web.EventContext, f func(txCtx *web.EventContext) error) error {
+	snapshot := maps.Clone(op.records)
+	if err := f(ctx); err != nil {
+		op.records = snapshot
+		return err
+	}
+	return nil
+}
+
+func TestBulkEdit(t *testing.T) {
+	records := func() map[string]bulkEditProduct {
+		return map[string]bulkEditProduct{
+			"1": {ID: 1, Name: "a", Price: 1},
+			"2": {ID: 2, Name: "cheap", Price: 2},
+			"3": {ID: 3, Name: "c", Price: 3},
+		}
+	}
+	newCtx := func(form map[string][]string) *web.EventContext {
+		r := httptest.NewRequest("POST", "/products", nil)
+		r.MultipartForm = &multipart.Form{Value: form}
+		return &web.EventContext{R: r}
+	}
+	setup := func(op DataOperator) *ModelBuilder {
+		mb := New().DataOperator(op).Model(&bulkEditProduct{})
+		mb.Editing("Name", "Price").ValidateFunc(func(obj any, ctx *web.EventContext) (err web.ValidationErrors) {
+			if p := obj.(*bulkEditProduct); p.Name == "cheap" && p.Price > 10 {
+				err.FieldError("Price", "too expensive")
+			}
+			return
+		})
+		mb.Listing().BulkEditAction()
+		return mb
+	}
+	ids := []string{"1", "2", "3"}
+
+	t.Run("no fields", func(t *testing.T) {
+		op := &bulkEditDataOperator{records: records()}
+		mb := setup(op)
+		err := mb.bulkEdit(ids, mb.editing.shownFieldNames(), newCtx(map[string][]string{"Price": {"20"}}), &web.EventResponse{})
+		assert.EqualError(t, err, "Please choose the fields to change")
+		assert.Equal(t, records(), op.records)
+	})
+
+	t.Run("saved", func(t *testing.T) {
+		op := &bulkEditDataOperator{records: records()}
+		mb := setup(op)
+		r := &web.EventResponse{}
+		err := mb.bulkEdit([]string{"1", "3"}, mb.editing.shownFieldNames(), newCtx(map[string][]string{
+			paramBulkEditFields: {"Price"},
+			"Price":             {"20"},
+			"Name":              {"ignored"},
+		}), r)
+		require.NoError(t, err)
+		assert.Equal(t, bulkEditProduct{ID: 1, Name: "a", Price: 20}, op.records["1"])
+		assert.Equal(t, bulkEditProduct{ID: 3, Name: "c", Price: 20}, op.records["3"])
+		assert.Contains(t, r.RunScript, `"ids":["1","3"]`)
+	})
+
+	t.Run("partially failed without transaction", func(t *testing.T) {
+		op := &bulkEditDataOperator{records: records()}
+		mb := setup(op)
+		ctx := newCtx(map[string][]string{paramBulkEditFields: {"Price"}, "Price": {"20"}})
+		err := mb.bulkEdit(append(ids, "4"), mb.editing.shownFieldNames(), ctx, &web.EventResponse{})
+		assert.EqualError(t, err, "2 records failed, the others were saved.")
+		assert.Equal(t, 20, op.records["1"].Price)
+		assert.Equal(t, 2, op.records["2"].Price)
+		assert.Equal(t, 20, op.records["3"].Price)
+		assert.Equal(t, []*BulkEditFailure{
+			{ID: "2", Errors: []string{"Price: too expensive"}},
+			{ID: "4", Errors: []string{ErrRecordNotFound.Error()}},
+		}, ctx.ContextValue(ctxKeyBulkEditFailures{}))
+	})
+
+	t.Run("rolled back in transaction", func(t *testing.T) {
+		op := &bulkEditTxDataOperator{bulkEditDataOperator{records: records()}}
+		mb := setup(op)
+		ctx := newCtx(map[string][]string{paramBulkEditFields: {"Price"}, "Price": {"20"}})
+		err := mb.bulkEdit(ids, mb.editing.shownFieldNames(), ctx, &web.EventResponse{})
+		assert.EqualError(t, err, "1 records failed, no records were saved.")
+		assert.Equal(t, records(), op.records)
+	})
+}
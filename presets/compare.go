@@ -0,0 +1,140 @@
+package presets
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/i18n"
+	"github.com/qor5/x/v3/perm"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/sunfmin/reflectutils"
+	h "github.com/theplant/htmlgo"
+)
+
+const (
+	BulkActionCompare = "Compare"
+
+	compareDialogWidth = "1000"
+)
+
+// CompareBulkAction adds the bulk action to compare 2 to maxRecords selected records side by side with the detailing fields.
+// The fields having different values are highlighted, they are found with the ConflictDiffFunc of the editing if it is set,
+// so the models the activity plugin is installed on are compared by its DiffBuilder.
+func (b *ListingBuilder) CompareBulkAction(maxRecords int) (r *BulkActionBuilder) {
+	maxRecords = max(maxRecords, 2)
+	return b.BulkAction(BulkActionCompare).
+		DialogWidth(compareDialogWidth).
+		ComponentFunc(func(selectedIds []string, ctx *web.EventContext) h.HTMLComponent {
+			return b.mb.compareComponent(selectedIds, maxRecords, ctx)
+		}).
+		ReadOnly(true)
+}
+
+func (mb *ModelBuilder) compareComponent(ids []string, maxRecords int, ctx *web.EventContext) h.HTMLComponent {
+	msgr := mb.mustGetMessages(ctx.R)
+	if len(ids) < 2 || len(ids) > maxRecords {
+		return VAlert(h.Text(msgr.CompareRecordsCount(maxRecords))).Type(TypeWarning).Variant(VariantTonal)
+	}
+
+	objs, err := mb.compareRecords(ids, ctx)
+	if err == nil {
+		var diffFields map[string]bool
+		names := mb.detailing.shownFieldNames()
+		if diffFields, err = mb.compareDiffFields(objs, names, ctx); err == nil {
+			return mb.compareTable(objs, ids, names, diffFields, ctx)
+		}
+	}
+	return VAlert(h.Text(err.Error())).Type(TypeError).Variant(VariantTonal)
+}
+
+func (mb *ModelBuilder) compareRecords(ids []string, ctx *web.EventContext) (objs []any, err error) {
+	for _, id := range ids {
+		obj, err := mb.detailing.GetFetchFunc()(mb.NewModel(), id, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if mb.Info().Verifier().Do(PermGet).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil {
+			return nil, perm.PermissionDenied
+		}
+		objs = append(objs, obj)
+	}
+	return
+}
+
+// compareDiffFields compares the other records with the first one, a field is different if itself or any of its nested fields is.
+func (mb *ModelBuilder) compareDiffFields(objs []any, names []string, ctx *web.EventContext) (r map[string]bool, err error) {
+	r = map[string]bool{}
+	for _, obj := range objs[1:] {
+		if diffFunc := mb.editing.conflictDiffFunc; diffFunc != nil {
+			diffs, err := diffFunc(objs[0], obj, ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range diffs {
+				for _, name := range names {
+					if d.Field == name || strings.HasPrefix(d.Field, name+".") {
+						r[name] = true
+					}
+				}
+			}
+			continue
+		}
+
+		for _, name := range names {
+			first, err := reflectutils.Get(objs[0], name)
+			if err != nil {
+				continue
+			}
+			other, err := reflectutils.Get(obj, name)
+			if err != nil {
+				continue
+			}
+			if !reflect.DeepEqual(first, other) {
+				r[name] = true
+			}
+		}
+	}
+	return
+}
+
+func (mb *ModelBuilder) compareTable(objs []any, ids []string, names []string, diffFields map[string]bool, ctx *web.EventContext) h.HTMLComponent {
+	msgr := mb.mustGetMessages(ctx.R)
+	info := mb.Info()
+
+	head := h.Tr(h.Th(msgr.CompareField))
+	for i, obj := range objs {
+		head.AppendChildren(h.Th(getPageTitle(obj, ids[i])))
+	}
+
+	var rows []h.HTMLComponent
+	for _, name := range names {
+		f := mb.detailing.getFieldOrDefault(name)
+		row := h.Tr(h.Td(h.Text(i18n.PT(ctx.R, ModelsI18nModuleKey, mb.label, mb.detailing.getLabel(f.NameLabel)))).
+			Class("font-weight-medium"))
+		for _, obj := range objs {
+			row.AppendChildren(h.Td(
+				web.Scope(
+					mb.detailing.fieldToComponentWithFormValueKey(info, obj, "", ctx, name, true, &web.ValidationErrors{}),
+				).VSlot("{ form }"),
+			))
+		}
+		if diffFields[name] {
+			row.Class("bg-amber-lighten-5")
+		} else {
+			row.Attr("v-show", "!locals.onlyDifferences")
+		}
+		rows = append(rows, row)
+	}
+
+	return web.Scope(
+		VSwitch().Label(fmt.Sprintf("%s (%d)", msgr.CompareOnlyDifferences, len(diffFields))).
+			Attr("v-model", "locals.onlyDifferences").
+			Color(ColorPrimary).HideDetails(true).Density(DensityCompact),
+		VTable(
+			h.Thead(head),
+			h.Tbody(rows...),
+		).Density(DensityCompact),
+	).VSlot("{ locals }").Init(`{ onlyDifferences: false }`)
+}
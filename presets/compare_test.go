@@ -0,0 +1,81 @@
+package presets
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	h "github.com/theplant/htmlgo"
+)
+
+func TestCompareDiffFields(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type Product struct {
+		ID      uint
+		Name    string
+		Price   int
+		Address Address
+	}
+	objs := []any{
+		&Product{ID: 1, Name: "A", Price: 1, Address: Address{City: "X"}},
+		&Product{ID: 2, Name: "A", Price: 2, Address: Address{City: "X"}},
+		&Product{ID: 3, Name: "A", Price: 1, Address: Address{City: "Y"}},
+	}
+	names := []string{"Name", "Price", "Address", "Virtual"}
+	ctx := &web.EventContext{R: httptest.NewRequest("GET", "/", nil)}
+
+	mb := New().Model(&Product{})
+	diffFields, err := mb.compareDiffFields(objs, names, ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"Price": true, "Address": true}, diffFields)
+
+	mb.Editing().ConflictDiffFunc(func(theirs, mine any, _ *web.EventContext) ([]ConflictDiff, error) {
+		if mine.(*Product).ID == 3 {
+			return []ConflictDiff{{Field: "Address.City"}, {Field: "Names"}}, nil
+		}
+		return nil, nil
+	})
+	diffFields, err = mb.compareDiffFields(objs, names, ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"Address": true}, diffFields)
+}
+
+func TestCompareComponentRecordsCount(t *testing.T) {
+	type Product struct {
+		ID uint
+	}
+	mb := New().Model(&Product{})
+	mb.Listing().CompareBulkAction(3)
+	ctx := &web.EventContext{R: httptest.NewRequest("GET", "/", nil)}
+
+	for _, ids := range [][]string{{"1"}, {"1", "2", "3", "4"}} {
+		body := h.MustString(mb.compareComponent(ids, 3, ctx), ctx.R.Context())
+		assert.Contains(t, body, "Select 2 to 3 records to compare.")
+	}
+	assert.NotNil(t, getBulkAction(mb.Listing().bulkActions, BulkActionCompare))
+}
+
+func TestCompareBulkActionReadOnly(t *testing.T) {
+	type Product struct {
+		ID uint
+	}
+	mb := New().Model(&Product{})
+	bulk := mb.Listing().CompareBulkAction(3)
+
+	c := &ListingCompo{lb: mb.Listing(), ID: "products", SelectedIds: []string{"1", "2"}}
+	evCtx := &web.EventContext{R: httptest.NewRequest("POST", "/products", nil), W: httptest.NewRecorder()}
+	evCtx.WithContextValue(ctxKeyListingCompo{}, c)
+	ctx := web.WrapEventContext(evCtx.R.Context(), evCtx)
+
+	body := h.MustString(c.bulkPanel(ctx, bulk, []string{"1"}, []string{"1"}), ctx)
+	assert.Contains(t, body, "Close")
+	assert.NotContains(t, body, "DoBulkAction")
+
+	r, err := c.DoBulkAction(ctx, DoBulkActionRequest{Name: BulkActionCompare})
+	require.NoError(t, err)
+	assert.Contains(t, r.RunScript, "bulk action is read only")
+}
@@ -9,6 +9,10 @@ const (
 	PermCreate          = "presets:create"
 	PermUpdate          = "presets:update"
 	PermDelete          = "presets:delete"
+	PermExport          = "presets:export"
+	PermImport          = "presets:import"
+	PermRestore         = "presets:restore"
+	PermPurge           = "presets:purge"
 	PermActions         = "presets:actions:*"
 	PermDoListingAction = "presets:do_listing_action:*"
 	PermBulkActions     = "presets:bulk_actions:*"
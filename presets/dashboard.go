@@ -0,0 +1,365 @@
+package presets
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/samber/lo"
+	h "github.com/theplant/htmlgo"
+	"go.uber.org/zap"
+
+	"github.com/qor5/admin/v3/presets/actions"
+)
+
+const (
+	paramDashboardOp     = "dashboard_op"
+	paramDashboardWidget = "dashboard_widget"
+
+	dashboardOpAdd       = "add"
+	dashboardOpRemove    = "remove"
+	dashboardOpMoveLeft  = "move_left"
+	dashboardOpMoveRight = "move_right"
+	dashboardOpResize    = "resize"
+
+	DashboardPortalName = "presets_DashboardPortalName"
+
+	permDashboardWidgets = "dashboard_widgets"
+)
+
+// DashboardWidgetWidths are the column widths of the 12 columns grid a widget could be resized to.
+var DashboardWidgetWidths = []int{4, 6, 8, 12}
+
+// DashboardLayout is the widgets a user added to the dashboard, in the order of display.
+type DashboardLayout struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID  string                   `gorm:"uniqueIndex;not null" json:"user_id"`
+	Widgets []*DashboardWidgetLayout `gorm:"serializer:json" json:"widgets"`
+}
+
+type DashboardWidgetLayout struct {
+	Name  string `json:"name"`
+	Width int    `json:"width"`
+}
+
+// DashboardLayoutStore persists the dashboard layouts, see gorm2op.DashboardLayoutStore.
+type DashboardLayoutStore interface {
+	// Get returns nil if the user has not customized the dashboard yet.
+	Get(ctx context.Context, userID string) (*DashboardLayout, error)
+	// Save creates or replaces the layout of layout.UserID.
+	Save(ctx context.Context, layout *DashboardLayout) error
+}
+
+type DashboardBuilder struct {
+	p          *Builder
+	store      DashboardLayoutStore
+	userIDFunc func(r *http.Request) string
+	widgets    []*DashboardWidgetBuilder
+}
+
+type DashboardWidgetBuilder struct {
+	d             *DashboardBuilder
+	name          string
+	titleFunc     func(evCtx *web.EventContext) string
+	icon          string
+	width         int
+	defaultOff    bool
+	componentFunc func(evCtx *web.EventContext) (h.HTMLComponent, error)
+}
+
+// Dashboard returns the dashboard which is the default home page once any widget is registered,
+// plugins register their widgets to it, users could add, remove, reorder and resize the widgets
+// if the Store and the UserIDFunc are set.
+func (b *Builder) Dashboard() (r *DashboardBuilder) {
+	if b.dashboard == nil {
+		b.dashboard = &DashboardBuilder{p: b}
+		b.GetWebBuilder().RegisterEventFunc(actions.DashboardUpdate, b.dashboard.update)
+	}
+	return b.dashboard
+}
+
+func (d *DashboardBuilder) Store(v DashboardLayoutStore) (r *DashboardBuilder) {
+	d.store = v
+	return d
+}
+
+// UserIDFunc returns the owner of the layout, the default layout is shown and can't be customized without a user.
+func (d *DashboardBuilder) UserIDFunc(v func(r *http.Request) string) (r *DashboardBuilder) {
+	d.userIDFunc = v
+	return d
+}
+
+// Widget returns the widget of the name, creates it if not exists. The widget is permission-checked
+// with presets:get on the dashboard_widgets:<name> resource.
+func (d *DashboardBuilder) Widget(name string) (r *DashboardWidgetBuilder) {
+	if w := d.GetWidget(name); w != nil {
+		return w
+	}
+	r = &DashboardWidgetBuilder{d: d, name: name, width: 6}
+	d.widgets = append(d.widgets, r)
+	return
+}
+
+func (d *DashboardBuilder) GetWidget(name string) *DashboardWidgetBuilder {
+	w, _ := lo.Find(d.widgets, func(w *DashboardWidgetBuilder) bool {
+		return w.name == name
+	})
+	return w
+}
+
+func (w *DashboardWidgetBuilder) Title(v string) (r *DashboardWidgetBuilder) {
+	w.titleFunc = func(*web.EventContext) string { return v }
+	return w
+}
+
+// TitleFunc is for the translated titles.
+func (w *DashboardWidgetBuilder) TitleFunc(v func(evCtx *web.EventContext) string) (r *DashboardWidgetBuilder) {
+	w.titleFunc = v
+	return w
+}
+
+func (w *DashboardWidgetBuilder) Icon(v string) (r *DashboardWidgetBuilder) {
+	w.icon = v
+	return w
+}
+
+// Width is the default number of columns of the 12 columns grid, defaults to 6.
+func (w *DashboardWidgetBuilder) Width(v int) (r *DashboardWidgetBuilder) {
+	w.width = v
+	return w
+}
+
+// DefaultOff excludes the widget from the default layout, users could still add it.
+func (w *DashboardWidgetBuilder) DefaultOff(v bool) (r *DashboardWidgetBuilder) {
+	w.defaultOff = v
+	return w
+}
+
+func (w *DashboardWidgetBuilder) ComponentFunc(v func(evCtx *web.EventContext) (h.HTMLComponent, error)) (r *DashboardWidgetBuilder) {
+	w.componentFunc = v
+	return w
+}
+
+func (w *DashboardWidgetBuilder) title(evCtx *web.EventContext) string {
+	if w.titleFunc == nil {
+		return w.name
+	}
+	return w.titleFunc(evCtx)
+}
+
+func (w *DashboardWidgetBuilder) allowed(r *http.Request) bool {
+	return w.d.p.verifier.Spawn().SnakeOn(permDashboardWidgets).SnakeOn(w.name).
+		Do(PermGet).WithReq(r).IsAllowed() == nil
+}
+
+func (d *DashboardBuilder) userID(r *http.Request) string {
+	if d.userIDFunc == nil {
+		return ""
+	}
+	return d.userIDFunc(r)
+}
+
+func (d *DashboardBuilder) editable(r *http.Request) bool {
+	return d.store != nil && d.userID(r) != ""
+}
+
+func (d *DashboardBuilder) defaultLayout() []*DashboardWidgetLayout {
+	var r []*DashboardWidgetLayout
+	for _, w := range d.widgets {
+		if !w.defaultOff {
+			r = append(r, &DashboardWidgetLayout{Name: w.name, Width: w.width})
+		}
+	}
+	return r
+}
+
+// Layout returns the widgets of the user's dashboard, the widgets not registered or not allowed are left out.
+func (d *DashboardBuilder) Layout(r *http.Request) ([]*DashboardWidgetLayout, error) {
+	widgets, err := d.storedLayout(r)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Filter(widgets, func(wl *DashboardWidgetLayout, _ int) bool {
+		return d.visible(r, wl)
+	}), nil
+}
+
+// storedLayout returns all the widgets of the user's dashboard, including the ones the user can't see currently.
+func (d *DashboardBuilder) storedLayout(r *http.Request) ([]*DashboardWidgetLayout, error) {
+	if d.editable(r) {
+		layout, err := d.store.Get(r.Context(), d.userID(r))
+		if err != nil {
+			return nil, err
+		}
+		if layout != nil {
+			return layout.Widgets, nil
+		}
+	}
+	return d.defaultLayout(), nil
+}
+
+func (d *DashboardBuilder) visible(r *http.Request, wl *DashboardWidgetLayout) bool {
+	w := d.GetWidget(wl.Name)
+	return w != nil && w.allowed(r)
+}
+
+func (d *DashboardBuilder) update(evCtx *web.EventContext) (r web.EventResponse, err error) {
+	if !d.editable(evCtx.R) {
+		return r, errors.New("dashboard is not editable")
+	}
+	name := evCtx.R.FormValue(paramDashboardWidget)
+	w := d.GetWidget(name)
+	if w == nil || !w.allowed(evCtx.R) {
+		return r, errors.Errorf("dashboard widget %q not found", name)
+	}
+
+	// the change is merged into the stored layout, so that the widgets the user can't see currently are kept
+	widgets, err := d.storedLayout(evCtx.R)
+	if err != nil {
+		return
+	}
+	i := slices.IndexFunc(widgets, func(wl *DashboardWidgetLayout) bool {
+		return wl.Name == name
+	})
+	// the widgets are moved over the visible neighbors
+	neighbor := func(step int) int {
+		for j := i + step; j >= 0 && j < len(widgets); j += step {
+			if d.visible(evCtx.R, widgets[j]) {
+				return j
+			}
+		}
+		return -1
+	}
+	op := evCtx.R.FormValue(paramDashboardOp)
+	switch {
+	case op == dashboardOpAdd && i < 0:
+		widgets = append(widgets, &DashboardWidgetLayout{Name: name, Width: w.width})
+	case i < 0:
+		return r, errors.Errorf("dashboard widget %q not added", name)
+	case op == dashboardOpRemove:
+		widgets = slices.Delete(widgets, i, i+1)
+	case op == dashboardOpMoveLeft || op == dashboardOpMoveRight:
+		if j := neighbor(lo.Ternary(op == dashboardOpMoveLeft, -1, 1)); j >= 0 {
+			widgets[i], widgets[j] = widgets[j], widgets[i]
+		}
+	case op == dashboardOpResize:
+		j := slices.Index(DashboardWidgetWidths, widgets[i].Width)
+		widgets[i].Width = DashboardWidgetWidths[(j+1)%len(DashboardWidgetWidths)]
+	}
+
+	if err = d.store.Save(evCtx.R.Context(), &DashboardLayout{
+		UserID:  d.userID(evCtx.R),
+		Widgets: widgets,
+	}); err != nil {
+		return
+	}
+	r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+		Name: DashboardPortalName,
+		Body: d.content(evCtx, lo.Filter(widgets, func(wl *DashboardWidgetLayout, _ int) bool {
+			return d.visible(evCtx.R, wl)
+		})),
+	})
+	return
+}
+
+func (d *DashboardBuilder) pageFunc(evCtx *web.EventContext) (r web.PageResponse, err error) {
+	msgr := MustGetMessages(evCtx.R)
+	widgets, err := d.Layout(evCtx.R)
+	if err != nil {
+		return
+	}
+	r.PageTitle = msgr.Dashboard
+	r.Body = VContainer(
+		web.Portal(d.content(evCtx, widgets)).Name(DashboardPortalName),
+	).Fluid(true)
+	return
+}
+
+func dashboardUpdateAction(op, name string) string {
+	return web.Plaid().
+		EventFunc(actions.DashboardUpdate).
+		Query(paramDashboardOp, op).
+		Query(paramDashboardWidget, name).
+		Go()
+}
+
+func (d *DashboardBuilder) content(evCtx *web.EventContext, widgets []*DashboardWidgetLayout) h.HTMLComponent {
+	msgr := MustGetMessages(evCtx.R)
+	editable := d.editable(evCtx.R)
+
+	var toolbar h.HTMLComponent
+	if editable {
+		var items []h.HTMLComponent
+		for _, w := range d.widgets {
+			if !w.allowed(evCtx.R) || lo.ContainsBy(widgets, func(wl *DashboardWidgetLayout) bool { return wl.Name == w.name }) {
+				continue
+			}
+			items = append(items, VListItem(VListItemTitle(h.Text(w.title(evCtx)))).
+				PrependIcon(w.icon).
+				Attr("@click", dashboardUpdateAction(dashboardOpAdd, w.name)))
+		}
+		toolbar = h.Div(
+			VMenu(
+				web.Slot(
+					VBtn(msgr.DashboardAddWidget).Attr("v-bind", "props").
+						Variant(VariantTonal).PrependIcon("mdi-plus").Disabled(len(items) == 0),
+				).Name("activator").Scope("{ props }"),
+				VList(items...).Density(DensityCompact),
+			),
+		).Class("d-flex justify-end mb-4")
+	}
+
+	if len(widgets) == 0 {
+		return h.Div(
+			toolbar,
+			h.Div(h.Text(msgr.DashboardNoWidgets)).Class("text-medium-emphasis text-center pa-8"),
+		)
+	}
+
+	cols := make([]h.HTMLComponent, 0, len(widgets))
+	for i, wl := range widgets {
+		w := d.GetWidget(wl.Name)
+		var body h.HTMLComponent
+		if w.componentFunc != nil {
+			comp, err := w.componentFunc(evCtx)
+			if err != nil {
+				d.p.logger.Warn("dashboard widget", zap.String("widget", w.name), zap.Error(err))
+				comp = h.Div(h.Text(err.Error())).Class("text-error")
+			}
+			body = comp
+		}
+
+		var btns h.HTMLComponent
+		if editable {
+			btn := func(icon, title, op string, disabled bool) h.HTMLComponent {
+				return VBtn("").Icon(icon).Attr("title", title).Variant(VariantText).Size(SizeSmall).
+					Disabled(disabled).Attr("@click", dashboardUpdateAction(op, w.name))
+			}
+			btns = h.Div(
+				btn("mdi-chevron-left", msgr.DashboardMoveLeft, dashboardOpMoveLeft, i == 0),
+				btn("mdi-chevron-right", msgr.DashboardMoveRight, dashboardOpMoveRight, i == len(widgets)-1),
+				btn("mdi-arrow-expand-horizontal", msgr.DashboardResize, dashboardOpResize, false),
+				btn("mdi-close", msgr.DashboardRemove, dashboardOpRemove, false),
+			).Class("d-flex")
+		}
+
+		cols = append(cols, VCol(
+			VCard(
+				VCardItem(
+					VCardTitle(h.Text(w.title(evCtx))),
+					web.Slot(h.If(w.icon != "", VIcon(w.icon))).Name("prepend"),
+					web.Slot(btns).Name("append"),
+				),
+				VCardText(body),
+			).Variant(VariantOutlined).Height("100%"),
+		).Cols(12).Md(wl.Width).Attr("data-widget", w.name))
+	}
+	return h.Div(toolbar, VRow(cols...))
+}
@@ -0,0 +1,87 @@
+package presets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/perm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	h "github.com/theplant/htmlgo"
+)
+
+type testDashboardLayoutStore map[string]*DashboardLayout
+
+func (s testDashboardLayoutStore) Get(_ context.Context, userID string) (*DashboardLayout, error) {
+	return s[userID], nil
+}
+
+func (s testDashboardLayoutStore) Save(_ context.Context, layout *DashboardLayout) error {
+	s[layout.UserID] = layout
+	return nil
+}
+
+func TestDashboard(t *testing.T) {
+	store := testDashboardLayoutStore{}
+	b := New().Permission(perm.New().Policies(
+		perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(perm.Anything).On(perm.Anything),
+		perm.PolicyFor("viewer").WhoAre(perm.Denied).ToDo(PermGet).On("*:dashboard_widgets:secret:*"),
+	).SubjectsFunc(func(r *http.Request) []string {
+		return []string{r.URL.Query().Get("role")}
+	}))
+	d := b.Dashboard().Store(store).UserIDFunc(func(r *http.Request) string {
+		return r.URL.Query().Get("user")
+	})
+	for _, name := range []string{"activity", "jobs", "secret"} {
+		d.Widget(name).Title(name).ComponentFunc(func(*web.EventContext) (h.HTMLComponent, error) {
+			return h.Text(name), nil
+		})
+	}
+	d.Widget("notes").Width(4).DefaultOff(true)
+
+	names := func(widgets []*DashboardWidgetLayout) (r []string) {
+		for _, w := range widgets {
+			r = append(r, w.Name)
+		}
+		return
+	}
+	layout := func(query string) []*DashboardWidgetLayout {
+		widgets, err := d.Layout(httptest.NewRequest("GET", "/?"+query, nil))
+		require.NoError(t, err)
+		return widgets
+	}
+	update := func(query, op, name string) error {
+		form := url.Values{paramDashboardOp: {op}, paramDashboardWidget: {name}}
+		_, err := d.update(&web.EventContext{R: httptest.NewRequest("POST", "/?"+query+"&"+form.Encode(), nil)})
+		return err
+	}
+
+	assert.Equal(t, []string{"activity", "jobs", "secret"}, names(layout("user=1")))
+	assert.Equal(t, []string{"activity", "jobs"}, names(layout("user=1&role=viewer")))
+
+	require.NoError(t, update("user=1", dashboardOpAdd, "notes"))
+	require.NoError(t, update("user=1", dashboardOpMoveLeft, "notes"))
+	require.NoError(t, update("user=1", dashboardOpRemove, "activity"))
+	require.NoError(t, update("user=1", dashboardOpResize, "notes"))
+	assert.Equal(t, []*DashboardWidgetLayout{{Name: "jobs", Width: 6}, {Name: "notes", Width: 6}, {Name: "secret", Width: 6}}, layout("user=1"))
+	assert.Equal(t, []string{"activity", "jobs", "secret"}, names(layout("user=2")), "layouts are per user")
+
+	require.NoError(t, update("user=1", dashboardOpResize, "secret"))
+	require.NoError(t, update("user=1", dashboardOpResize, "secret"))
+	require.NoError(t, update("user=1", dashboardOpResize, "secret"))
+	assert.Equal(t, 4, layout("user=1")[2].Width)
+
+	assert.Error(t, update("user=2&role=viewer", dashboardOpAdd, "secret"))
+	assert.Error(t, update("user=1", dashboardOpRemove, "unknown"))
+	assert.Error(t, update("", dashboardOpRemove, "jobs"), "not editable without user")
+
+	// the widgets the user can't see are kept in the layout and skipped by the moves
+	require.NoError(t, update("user=2&role=viewer", dashboardOpRemove, "activity"))
+	require.NoError(t, update("user=2&role=viewer", dashboardOpMoveRight, "jobs"))
+	assert.Equal(t, []string{"jobs"}, names(layout("user=2&role=viewer")))
+	assert.Equal(t, []string{"jobs", "secret"}, names(layout("user=2")))
+}
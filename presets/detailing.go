@@ -108,7 +108,7 @@ func (b *DetailingBuilder) WrapPageFunc(w func(in web.PageFunc) web.PageFunc) (r
 }
 
 func (b *DetailingBuilder) FetchFunc(v FetchFunc) (r *DetailingBuilder) {
-	b.fetcher = v
+	b.fetcher = b.mb.rowScopedFetchFunc(v)
 	return b
 }
 
@@ -11,6 +11,7 @@ import (
 	. "github.com/qor5/x/v3/ui/vuetify"
 	vx "github.com/qor5/x/v3/ui/vuetifyx"
 	h "github.com/theplant/htmlgo"
+	"go.uber.org/zap"
 
 	"github.com/qor5/admin/v3/presets/actions"
 )
@@ -28,6 +29,9 @@ type EditingBuilder struct {
 	actionsFunc              ObjectComponentFunc
 	editingTitleFunc         EditingTitleComponentFunc
 	idCurrentActiveProcessor IdCurrentActiveProcessor
+	lockField                string
+	conflictDiffFunc         ConflictDiffFunc
+	drafts                   *EditingDraftsBuilder
 	FieldsBuilder
 }
 
@@ -105,7 +109,7 @@ func (b *EditingBuilder) Creating(vs ...interface{}) (r *EditingBuilder) {
 }
 
 func (b *EditingBuilder) FetchFunc(v FetchFunc) (r *EditingBuilder) {
-	b.Fetcher = v
+	b.Fetcher = b.mb.rowScopedFetchFunc(v)
 	return b
 }
 
@@ -115,7 +119,7 @@ func (b *EditingBuilder) WrapFetchFunc(w func(in FetchFunc) FetchFunc) (r *Editi
 }
 
 func (b *EditingBuilder) SaveFunc(v SaveFunc) (r *EditingBuilder) {
-	b.Saver = v
+	b.Saver = b.mb.rowScopedSaveFunc(v)
 	return b
 }
 
@@ -125,7 +129,7 @@ func (b *EditingBuilder) WrapSaveFunc(w func(in SaveFunc) SaveFunc) (r *EditingB
 }
 
 func (b *EditingBuilder) DeleteFunc(v DeleteFunc) (r *EditingBuilder) {
-	b.Deleter = v
+	b.Deleter = b.mb.rowScopedDeleteFunc(v)
 	return b
 }
 
@@ -380,6 +384,17 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 	for _, hf := range b.hiddenFuncs {
 		hiddenComps = append(hiddenComps, hf(obj, ctx))
 	}
+	if b.lockField != "" && id != "" {
+		hiddenComps = append(hiddenComps, b.lockHidden(obj, ctx))
+	}
+
+	// every rendered form has its own undo history
+	historyKey := fmt.Sprint(time.Now().UnixNano())
+	var draftNotice h.HTMLComponent
+	var saveDraftScript string
+	if !autosave {
+		draftNotice, saveDraftScript = b.mb.draftNotice(ctx, id, "", actions.RestoreDraft, actions.DiscardDraft)
+	}
 
 	if id == "" {
 		ctx = ctx.WithContextValue(ctxKeyForceForCreating{}, true)
@@ -387,6 +402,8 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 	formContent := web.Scope(h.Components(
 		VCardText(
 			h.Components(hiddenComps...),
+			draftNotice,
+			b.conflictComponent(ctx),
 			web.Listen(b.mb.NotifModelsValidate(), setFieldErrorsScript),
 			b.ToComponent(b.mb.Info(), obj, ctx),
 		),
@@ -410,6 +427,7 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 				VAppBar(
 					VToolbarTitle("").Class("pl-2").
 						Children(title).ClassIf("pr-5", autosave),
+					h.If(!autosave, formHistoryButtons(msgr, historyKey)),
 					h.If(!autosave, VBtn("").Icon(true).Children(
 						VIcon("mdi-close"),
 					).Attr("@click.stop", closeBtnVarScript)),
@@ -433,7 +451,7 @@ func (b *EditingBuilder) editFormFor(obj interface{}, ctx *web.EventContext) h.H
 			Query(ParamOverlay, ctx.Param(ParamOverlay)).
 			Go()
 	} else {
-		onChangeEvent += setValidateKeysScript +
+		onChangeEvent += formHistoryScript(historyKey) + saveDraftScript + setValidateKeysScript +
 			web.Plaid().URL(ctx.R.URL.Path).
 				BeforeScript(fmt.Sprintf(`dash.__ValidateOperateID=%q;`, operateID)).
 				EventFunc(actions.Validate).
@@ -591,7 +609,26 @@ func (b *EditingBuilder) doUpdate(
 		return created, &vErr
 	}
 
+	if id != "" && b.lockField != "" {
+		conflict, err := b.checkOptimisticLock(id, obj, ctx)
+		if err != nil {
+			b.UpdateOverlayContent(ctx, r, obj, "", err)
+			return created, err
+		}
+		if conflict != nil {
+			return created, b.updateConflict(ctx, r, obj, conflict)
+		}
+	}
+
 	err1 := usingB.Saver(obj, id, ctx)
+	if errors.Is(err1, ErrOptimisticLockConflict) {
+		conflict, err := b.editingConflict(nil, obj, ctx)
+		if err != nil {
+			b.UpdateOverlayContent(ctx, r, obj, "", err)
+			return created, err
+		}
+		return created, b.updateConflict(ctx, r, obj, conflict)
+	}
 	if err1 != nil {
 		var ve *web.ValidationErrors
 		if errors.As(err1, &ve) {
@@ -602,6 +639,14 @@ func (b *EditingBuilder) doUpdate(
 		return created, err1
 	}
 
+	if id != "" && b.lockField != "" {
+		b.refreshLockToken(obj, ctx, r)
+	}
+
+	if err := b.mb.discardDraft(ctx, id, ""); err != nil {
+		b.mb.p.logger.Warn("discard editing draft", zap.Error(err))
+	}
+
 	if id == "" {
 		r.Emit(
 			b.mb.NotifModelsCreated(),
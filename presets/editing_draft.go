@@ -0,0 +1,269 @@
+package presets
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/perm"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	h "github.com/theplant/htmlgo"
+	"go.uber.org/zap"
+
+	"github.com/qor5/admin/v3/presets/actions"
+)
+
+const (
+	paramDraftSection = "presets_draft_section"
+	draftSaveDelay    = 2 * time.Second
+)
+
+// EditingDraftKey identifies the draft of a user, RecordID is empty for creating and
+// Section is the name of the detailing section or empty for the editing form.
+type EditingDraftKey struct {
+	UserID    string `gorm:"uniqueIndex:uix_editing_drafts_key;not null" json:"user_id"`
+	ModelName string `gorm:"uniqueIndex:uix_editing_drafts_key;not null" json:"model_name"`
+	RecordID  string `gorm:"uniqueIndex:uix_editing_drafts_key;not null" json:"record_id"`
+	Section   string `gorm:"uniqueIndex:uix_editing_drafts_key;not null" json:"section"`
+}
+
+// EditingDraft is the in-progress values of an editing form, Form is the url encoded form values.
+type EditingDraft struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	EditingDraftKey `gorm:"embedded"`
+	Form            string `gorm:"not null" json:"form"`
+}
+
+// EditingDraftStore persists the editing drafts, see gorm2op.EditingDraftStore.
+type EditingDraftStore interface {
+	// Get returns nil if there is no draft.
+	Get(ctx context.Context, key EditingDraftKey) (*EditingDraft, error)
+	// Save creates or replaces the draft of the key.
+	Save(ctx context.Context, draft *EditingDraft) error
+	Delete(ctx context.Context, key EditingDraftKey) error
+}
+
+type EditingDraftsBuilder struct {
+	mb         *ModelBuilder
+	store      EditingDraftStore
+	userIDFunc func(r *http.Request) string
+}
+
+type ctxKeyDraftRestored struct{}
+
+// Drafts autosaves the in-progress values of the editing form and the detailing sections, the editor is offered to
+// restore the draft when the form is reopened, it is discarded once the record is saved.
+func (b *EditingBuilder) Drafts(store EditingDraftStore) (r *EditingDraftsBuilder) {
+	mb := b.mb
+	if mb.editing.drafts == nil {
+		mb.editing.drafts = &EditingDraftsBuilder{mb: mb}
+	}
+	r = mb.editing.drafts
+	r.store = store
+	return
+}
+
+func (b *EditingBuilder) GetDrafts() *EditingDraftsBuilder {
+	return b.mb.editing.drafts
+}
+
+// UserIDFunc is required, the drafts are disabled if there is no current user.
+func (b *EditingDraftsBuilder) UserIDFunc(v func(r *http.Request) string) (r *EditingDraftsBuilder) {
+	b.userIDFunc = v
+	return b
+}
+
+// draftsKey returns false if the drafts are not enabled for the request.
+func (mb *ModelBuilder) draftsKey(ctx *web.EventContext, id, section string) (EditingDraftKey, bool) {
+	b := mb.editing.drafts
+	if b == nil || b.store == nil || b.userIDFunc == nil {
+		return EditingDraftKey{}, false
+	}
+	userID := b.userIDFunc(ctx.R)
+	if userID == "" {
+		return EditingDraftKey{}, false
+	}
+	return EditingDraftKey{UserID: userID, ModelName: mb.uriName, RecordID: id, Section: section}, true
+}
+
+func (mb *ModelBuilder) doSaveDraft(ctx *web.EventContext) (r web.EventResponse, err error) {
+	key, ok := mb.draftsKey(ctx, ctx.R.FormValue(ParamID), ctx.R.FormValue(paramDraftSection))
+	if !ok {
+		return r, errors.New("editing drafts are not available")
+	}
+	if ctx.R.MultipartForm == nil {
+		return
+	}
+	if err = mb.draftAllowed(ctx, key.RecordID); err != nil {
+		return
+	}
+	err = mb.editing.drafts.store.Save(ctx.R.Context(), &EditingDraft{
+		EditingDraftKey: key,
+		Form:            url.Values(ctx.R.MultipartForm.Value).Encode(),
+	})
+	return
+}
+
+// draftAllowed only keeps drafts of the records the user may update, or of new ones if the user may create.
+func (mb *ModelBuilder) draftAllowed(ctx *web.EventContext, id string) error {
+	if id == "" {
+		if mb.Info().Verifier().Do(PermCreate).WithReq(ctx.R).IsAllowed() != nil {
+			return perm.PermissionDenied
+		}
+		return nil
+	}
+	if mb.editing.Fetcher == nil {
+		return errors.New("editing drafts are not available")
+	}
+	obj, err := mb.editing.Fetcher(mb.NewModel(), id, ctx)
+	if err != nil {
+		return err
+	}
+	if mb.Info().Verifier().Do(PermUpdate).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil {
+		return perm.PermissionDenied
+	}
+	return nil
+}
+
+func (mb *ModelBuilder) doDiscardDraft(ctx *web.EventContext) (r web.EventResponse, err error) {
+	err = mb.discardDraft(ctx, ctx.R.FormValue(ParamID), ctx.R.FormValue(paramDraftSection))
+	return
+}
+
+func (mb *ModelBuilder) discardDraft(ctx *web.EventContext, id, section string) error {
+	key, ok := mb.draftsKey(ctx, id, section)
+	if !ok {
+		return nil
+	}
+	return mb.editing.drafts.store.Delete(ctx.R.Context(), key)
+}
+
+// loadDraft replaces the submitted form values of the request with the draft.
+func (mb *ModelBuilder) loadDraft(ctx *web.EventContext, id, section string) error {
+	key, ok := mb.draftsKey(ctx, id, section)
+	if !ok {
+		return errors.New("editing drafts are not available")
+	}
+	draft, err := mb.editing.drafts.store.Get(ctx.R.Context(), key)
+	if err != nil {
+		return err
+	}
+	if draft == nil {
+		return ErrRecordNotFound
+	}
+	values, err := url.ParseQuery(draft.Form)
+	if err != nil {
+		return errors.Wrap(err, "parse draft")
+	}
+	ctx.R.MultipartForm = &multipart.Form{Value: values}
+	ctx.WithContextValue(ctxKeyDraftRestored{}, true)
+	return nil
+}
+
+func (b *EditingBuilder) doRestoreDraft(ctx *web.EventContext) (r web.EventResponse, err error) {
+	id := ctx.R.FormValue(ParamID)
+	if err = b.mb.loadDraft(ctx, id, ""); err != nil {
+		return
+	}
+	usingB := b
+	if b.mb.creating != nil && id == "" {
+		usingB = b.mb.creating
+	}
+	obj, vErr := usingB.FetchAndUnmarshal(id, false, ctx)
+	if vErr.HaveGlobalErrors() {
+		return r, &vErr
+	}
+	usingB.UpdateOverlayContent(ctx, &r, obj, "", nil)
+	web.AppendRunScripts(&r, fmt.Sprintf(`if (vars.%s) { vars.%s.editing=true }`, VarsPresetsDataChanged, VarsPresetsDataChanged))
+	return
+}
+
+// draftNotice offers to restore or discard the draft, and the script to autosave the draft on changes.
+func (mb *ModelBuilder) draftNotice(ctx *web.EventContext, id, section, restoreEvent, discardEvent string) (notice h.HTMLComponent, saveScript string) {
+	key, ok := mb.draftsKey(ctx, id, section)
+	if !ok {
+		return nil, ""
+	}
+	// the form changes are debounced again, so that typing doesn't save the draft on every pause
+	saveScript = fmt.Sprintf(`if (!dash.__draftTimers) { dash.__draftTimers = {} }
+clearTimeout(dash.__draftTimers[%[1]q]);
+dash.__draftTimers[%[1]q] = setTimeout(() => { %[2]s }, %[3]d);
+`, id+"/"+section, web.Plaid().URL(ctx.R.URL.Path).
+		EventFunc(actions.SaveDraft).
+		Query(ParamID, id).
+		Query(paramDraftSection, section).
+		Go(), draftSaveDelay.Milliseconds())
+
+	if restored, _ := ctx.R.Context().Value(ctxKeyDraftRestored{}).(bool); restored {
+		return nil, saveScript
+	}
+	draft, err := mb.editing.drafts.store.Get(ctx.R.Context(), key)
+	if err != nil {
+		mb.p.logger.Warn("get editing draft", zap.Error(err))
+		return nil, saveScript
+	}
+	if draft == nil {
+		return nil, saveScript
+	}
+
+	msgr := MustGetMessages(ctx.R)
+	plaid := func(event string) *web.VueEventTagBuilder {
+		return web.Plaid().URL(ctx.R.URL.Path).
+			EventFunc(event).
+			Query(ParamID, id).
+			Query(paramDraftSection, section).
+			Query(ParamOverlay, ctx.R.FormValue(ParamOverlay))
+	}
+	notice = web.Scope(
+		VAlert(
+			h.Text(msgr.EditingDraftFound(msgr.HumanizeTime(draft.UpdatedAt))),
+			web.Slot(
+				VBtn(msgr.EditingDraftDiscard).Variant(VariantText).Size(SizeSmall).
+					Attr("@click", plaid(discardEvent).ThenScript("locals.show = false").Go()),
+				VBtn(msgr.EditingDraftRestore).Variant(VariantTonal).Size(SizeSmall).Class("ml-2").
+					Attr("@click", plaid(restoreEvent).Go()),
+			).Name("append"),
+		).Type("info").Density(DensityCompact).Variant(VariantTonal).Class("mb-4").
+			Attr("v-if", "locals.show"),
+	).VSlot("{ locals }").Init(`{ show: true }`)
+	return notice, saveScript
+}
+
+// formHistoryScript records the form values for undo and redo in the history of the key, it runs on changes of the form.
+func formHistoryScript(key string) string {
+	return fmt.Sprintf(`
+if (!dash.__histories) { dash.__histories = {} }
+if (!dash.__histories[%[1]q]) { dash.__histories[%[1]q] = { stack: [JSON.stringify(oldForm)], index: 0 } }
+{
+    const history = dash.__histories[%[1]q]
+    if (history.applying) {
+        history.applying = false
+    } else {
+        history.stack.splice(history.index + 1)
+        history.stack.push(JSON.stringify(form))
+        history.index = history.stack.length - 1
+    }
+}
+`, key)
+}
+
+// formHistoryButtons undo and redo the changes of the form recorded by formHistoryScript of the key.
+func formHistoryButtons(msgr *Messages, key string) h.HTMLComponent {
+	history := fmt.Sprintf("(dash.__histories && dash.__histories[%q])", key)
+	return h.Components(
+		VBtn("").Icon("mdi-undo").Variant(VariantText).Size(SizeSmall).Attr("title", msgr.EditingUndo).
+			Attr(":disabled", fmt.Sprintf("!%[1]s || %[1]s.index <= 0", history)).
+			Attr("@click", fmt.Sprintf(`const history = %s; if (history && history.index > 0) { history.index--; history.applying = true; Object.assign(form, JSON.parse(history.stack[history.index])) }`, history)),
+		VBtn("").Icon("mdi-redo").Variant(VariantText).Size(SizeSmall).Attr("title", msgr.EditingRedo).
+			Attr(":disabled", fmt.Sprintf("!%[1]s || %[1]s.index >= %[1]s.stack.length - 1", history)).
+			Attr("@click", fmt.Sprintf(`const history = %s; if (history && history.index < history.stack.length - 1) { history.index++; history.applying = true; Object.assign(form, JSON.parse(history.stack[history.index])) }`, history)),
+	)
+}
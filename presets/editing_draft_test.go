@@ -0,0 +1,90 @@
+package presets
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/perm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryEditingDraftStore map[EditingDraftKey]*EditingDraft
+
+func (s memoryEditingDraftStore) Get(_ context.Context, key EditingDraftKey) (*EditingDraft, error) {
+	return s[key], nil
+}
+
+func (s memoryEditingDraftStore) Save(_ context.Context, draft *EditingDraft) error {
+	s[draft.EditingDraftKey] = draft
+	return nil
+}
+
+func (s memoryEditingDraftStore) Delete(_ context.Context, key EditingDraftKey) error {
+	delete(s, key)
+	return nil
+}
+
+func TestEditingDrafts(t *testing.T) {
+	type Product struct {
+		ID   uint
+		Name string
+	}
+	store := memoryEditingDraftStore{}
+	b := New().Permission(perm.New().Policies(
+		perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(perm.Anything).On(perm.Anything),
+		perm.PolicyFor("viewer").WhoAre(perm.Denied).ToDo(PermUpdate).On("*:products:*"),
+	).SubjectsFunc(func(r *http.Request) []string {
+		return []string{r.Header.Get("Role")}
+	}))
+	mb := b.Model(&Product{})
+	mb.Editing().FetchFunc(func(obj interface{}, id string, _ *web.EventContext) (interface{}, error) {
+		obj.(*Product).ID = 5
+		return obj, nil
+	})
+	mb.Editing().Drafts(store).UserIDFunc(func(r *http.Request) string {
+		return r.Header.Get("User")
+	})
+
+	newCtx := func(user string, form map[string][]string) *web.EventContext {
+		r := httptest.NewRequest("POST", "/products?"+ParamID+"=5", nil)
+		r.Header.Set("User", user)
+		r.MultipartForm = &multipart.Form{Value: form}
+		return &web.EventContext{R: r}
+	}
+
+	_, err := mb.doSaveDraft(newCtx("", map[string][]string{"Name": {"a"}}))
+	assert.Error(t, err)
+
+	_, err = mb.doSaveDraft(newCtx("1", map[string][]string{"Name": {"a b"}}))
+	require.NoError(t, err)
+	key := EditingDraftKey{UserID: "1", ModelName: "products", RecordID: "5"}
+	require.Contains(t, store, key)
+	assert.Equal(t, "Name=a+b", store[key].Form)
+
+	viewer := newCtx("3", map[string][]string{"Name": {"c"}})
+	viewer.R.Header.Set("Role", "viewer")
+	_, err = mb.doSaveDraft(viewer)
+	assert.ErrorIs(t, err, perm.PermissionDenied)
+	assert.NotContains(t, store, EditingDraftKey{UserID: "3", ModelName: "products", RecordID: "5"})
+
+	notice, script := mb.draftNotice(newCtx("2", nil), "5", "", "restore", "discard")
+	assert.Nil(t, notice)
+	assert.Contains(t, script, "presets_SaveDraft")
+	notice, _ = mb.draftNotice(newCtx("1", nil), "5", "", "restore", "discard")
+	assert.NotNil(t, notice)
+
+	ctx := newCtx("1", nil)
+	require.NoError(t, mb.loadDraft(ctx, "5", ""))
+	assert.Equal(t, "a b", ctx.R.MultipartForm.Value["Name"][0])
+	notice, _ = mb.draftNotice(ctx, "5", "", "restore", "discard")
+	assert.Nil(t, notice)
+
+	require.NoError(t, mb.discardDraft(newCtx("1", nil), "5", ""))
+	assert.Empty(t, store)
+	assert.ErrorIs(t, mb.loadDraft(newCtx("1", nil), "5", ""), ErrRecordNotFound)
+}
@@ -0,0 +1,225 @@
+package presets
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/i18n"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/sunfmin/reflectutils"
+	h "github.com/theplant/htmlgo"
+
+	"github.com/qor5/admin/v3/presets/actions"
+)
+
+const (
+	ParamLockToken = "presets_lock_token"
+	ParamForceSave = "presets_force_save"
+)
+
+// ErrOptimisticLockConflict is returned by the SaveFunc if the record was changed after it was fetched.
+var ErrOptimisticLockConflict = errors.New("optimistic lock conflict")
+
+// OptimisticLock is the lock field of the record and the value it had when the editor opened it,
+// SaveFunc implementations should only update the record if the field still has the value.
+type OptimisticLock struct {
+	Field string
+	Value any
+}
+
+type ctxKeyOptimisticLock struct{}
+
+func WithOptimisticLock(ctx context.Context, lock *OptimisticLock) context.Context {
+	return context.WithValue(ctx, ctxKeyOptimisticLock{}, lock)
+}
+
+// OptimisticLockFromContext returns the lock of the current save, see gorm2op.DataOperatorBuilder.Save.
+func OptimisticLockFromContext(ctx context.Context) (*OptimisticLock, bool) {
+	lock, ok := ctx.Value(ctxKeyOptimisticLock{}).(*OptimisticLock)
+	return lock, ok
+}
+
+type ConflictDiff struct {
+	Field  string
+	Theirs string
+	Mine   string
+}
+
+// ConflictDiffFunc lists the differences between the saved record and the one the editor tried to save.
+type ConflictDiffFunc func(theirs, mine any, ctx *web.EventContext) ([]ConflictDiff, error)
+
+type editingConflict struct {
+	diffs []ConflictDiff
+}
+
+type ctxKeyEditingConflict struct{}
+
+// OptimisticLock enables optimistic concurrency on updates, the field is usually UpdatedAt or an integer Version
+// which must not be editable. Its value is embedded in the form, if the record was changed in the meanwhile,
+// the editor could review the differences and choose to reload or to save anyway.
+func (b *EditingBuilder) OptimisticLock(field string) (r *EditingBuilder) {
+	b.lockField = field
+	return b
+}
+
+// ConflictDiffFunc replaces the default comparison of the editing fields, activity plugin sets it with its DiffBuilder.
+func (b *EditingBuilder) ConflictDiffFunc(v ConflictDiffFunc) (r *EditingBuilder) {
+	b.conflictDiffFunc = v
+	return b
+}
+
+func lockToken(obj any, field string) string {
+	v, err := reflectutils.Get(obj, field)
+	if err != nil {
+		return ""
+	}
+	switch vv := v.(type) {
+	case time.Time:
+		return vv.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if vv == nil {
+			return ""
+		}
+		return vv.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+func (b *EditingBuilder) lockHidden(obj any, ctx *web.EventContext) h.HTMLComponent {
+	token := ctx.R.FormValue(ParamLockToken)
+	if token == "" {
+		token = lockToken(obj, b.lockField)
+	}
+	return h.Input("").Type("hidden").Attr(web.VField(ParamLockToken, token)...)
+}
+
+// refreshLockToken passes the token of the saved record to the form, so saving it again without reopening,
+// like the autosave of the Content overlay, is not reported as a conflict.
+func (b *EditingBuilder) refreshLockToken(obj any, ctx *web.EventContext, r *web.EventResponse) {
+	token := lockToken(obj, b.lockField)
+	ctx.R.Form.Set(ParamLockToken, token)
+	web.AppendRunScripts(r, fmt.Sprintf("form[%s] = %s", h.JSONString(ParamLockToken), h.JSONString(token)))
+}
+
+// checkOptimisticLock compares the token of the form with the saved record and passes the lock to the SaveFunc.
+func (b *EditingBuilder) checkOptimisticLock(id string, mine any, ctx *web.EventContext) (conflict *editingConflict, err error) {
+	theirs, err := b.Fetcher(b.mb.NewModel(), id, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.R.FormValue(ParamForceSave) != "true" && ctx.R.FormValue(ParamLockToken) != lockToken(theirs, b.lockField) {
+		return b.editingConflict(theirs, mine, ctx)
+	}
+
+	value, err := reflectutils.Get(theirs, b.lockField)
+	if err != nil {
+		return nil, err
+	}
+	if err = reflectutils.Set(mine, b.lockField, value); err != nil {
+		return nil, err
+	}
+	ctx.WithContextValue(ctxKeyOptimisticLock{}, &OptimisticLock{Field: b.lockField, Value: value})
+	return nil, nil
+}
+
+func (b *EditingBuilder) editingConflict(theirs, mine any, ctx *web.EventContext) (*editingConflict, error) {
+	if theirs == nil {
+		var err error
+		theirs, err = b.Fetcher(b.mb.NewModel(), ctx.R.FormValue(ParamID), ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	diffFunc := b.conflictDiffFunc
+	if diffFunc == nil {
+		diffFunc = b.defaultConflictDiff
+	}
+	diffs, err := diffFunc(theirs, mine, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &editingConflict{diffs: diffs}, nil
+}
+
+func (b *EditingBuilder) defaultConflictDiff(theirs, mine any, _ *web.EventContext) (diffs []ConflictDiff, err error) {
+	for _, name := range b.getFieldNamesFromLayout() {
+		tv, err := reflectutils.Get(theirs, name)
+		if err != nil {
+			continue
+		}
+		mv, err := reflectutils.Get(mine, name)
+		if err != nil {
+			continue
+		}
+		if reflect.DeepEqual(tv, mv) {
+			continue
+		}
+		diffs = append(diffs, ConflictDiff{Field: name, Theirs: fmt.Sprint(tv), Mine: fmt.Sprint(mv)})
+	}
+	return
+}
+
+// updateConflict renders the form again with the conflict, the lock token of the form is kept,
+// so saving again without choosing is still rejected.
+func (b *EditingBuilder) updateConflict(ctx *web.EventContext, r *web.EventResponse, obj any, conflict *editingConflict) error {
+	vErr := &web.ValidationErrors{}
+	vErr.GlobalError(b.mb.mustGetMessages(ctx.R).EditingConflict)
+	ctx.WithContextValue(ctxKeyEditingConflict{}, conflict)
+	b.UpdateOverlayContent(ctx, r, obj, "", vErr)
+	return vErr
+}
+
+func (b *EditingBuilder) conflictComponent(ctx *web.EventContext) h.HTMLComponent {
+	conflict, ok := ctx.ContextValue(ctxKeyEditingConflict{}).(*editingConflict)
+	if !ok {
+		return nil
+	}
+	msgr := b.mb.mustGetMessages(ctx.R)
+
+	var rows []h.HTMLComponent
+	for _, d := range conflict.diffs {
+		label := d.Field
+		if f := b.GetField(d.Field); f != nil {
+			label = i18n.PT(ctx.R, ModelsI18nModuleKey, b.mb.label, b.getLabel(f.NameLabel))
+		}
+		rows = append(rows, h.Tr(
+			h.Td(h.Text(label)),
+			h.Td(h.Text(d.Theirs)),
+			h.Td(h.Text(d.Mine)),
+		))
+	}
+
+	queries := ctx.Queries()
+	queries.Del(ParamForceSave)
+	reload := web.Plaid().EventFunc(actions.Edit).Queries(queries).URL(b.mb.Info().ListingHref()).Go()
+	if b.mb.singleton {
+		reload = web.Plaid().Reload().Go()
+	}
+	queries = ctx.Queries()
+	queries.Set(ParamForceSave, "true")
+
+	return VAlert(
+		h.If(len(rows) > 0, VTable(
+			h.Thead(h.Tr(
+				h.Th(msgr.EditingConflictField),
+				h.Th(msgr.EditingConflictTheirs),
+				h.Th(msgr.EditingConflictMine),
+			)),
+			h.Tbody(rows...),
+		).Density(DensityCompact).Class("mb-2")),
+		h.Div(
+			VBtn(msgr.EditingConflictReload).Variant(VariantTonal).Class("mr-2").
+				Attr("@click", reload),
+			VBtn(msgr.EditingConflictForceSave).Color(ColorError).Variant(VariantFlat).
+				Attr("@click", web.Plaid().
+					EventFunc(actions.Update).
+					Queries(queries).
+					URL(b.mb.Info().ListingHref()).
+					Go()),
+		).Class("d-flex"),
+	).Type(TypeWarning).Variant(VariantTonal).Title(msgr.EditingConflict).Class("mb-4")
+}
@@ -0,0 +1,135 @@
+package presets
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditingOptimisticLock(t *testing.T) {
+	type Product struct {
+		ID      uint
+		Name    string
+		Version int
+	}
+
+	existing := &Product{ID: 1, Name: "Apple", Version: 2}
+	var (
+		saved *Product
+		lock  *OptimisticLock
+	)
+
+	pb := New()
+	mb := pb.Model(&Product{})
+	eb := mb.Editing("Name").OptimisticLock("Version").
+		FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (interface{}, error) {
+			cp := *existing
+			return &cp, nil
+		}).
+		SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) error {
+			saved = obj.(*Product)
+			lock, _ = OptimisticLockFromContext(ctx.R.Context())
+			return nil
+		})
+
+	update := func(fields map[string]string) (web.EventResponse, error) {
+		body := &bytes.Buffer{}
+		mw := multipart.NewWriter(body)
+		for k, v := range fields {
+			require.NoError(t, mw.WriteField(k, v))
+		}
+		require.NoError(t, mw.Close())
+		r := httptest.NewRequest("POST", "/products?id=1", body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		evCtx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+		var resp web.EventResponse
+		_, err := eb.doUpdate(evCtx, &resp, true)
+		return resp, err
+	}
+
+	_, err := update(map[string]string{"Name": "Banana", ParamLockToken: "1"})
+	var vErr *web.ValidationErrors
+	require.ErrorAs(t, err, &vErr)
+	assert.Nil(t, saved)
+
+	conflict, err := eb.editingConflict(nil, &Product{ID: 1, Name: "Banana", Version: 2}, &web.EventContext{R: httptest.NewRequest("POST", "/products?id=1", nil)})
+	require.NoError(t, err)
+	assert.Equal(t, []ConflictDiff{{Field: "Name", Theirs: "Apple", Mine: "Banana"}}, conflict.diffs)
+
+	_, err = update(map[string]string{"Name": "Banana", ParamLockToken: "1", ParamForceSave: "true"})
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, "Banana", saved.Name)
+	assert.Equal(t, &OptimisticLock{Field: "Version", Value: 2}, lock)
+
+	saved = nil
+	_, err = update(map[string]string{"Name": "Cherry", ParamLockToken: "2"})
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, "Cherry", saved.Name)
+}
+
+func TestEditingOptimisticLockSaveTwice(t *testing.T) {
+	type Product struct {
+		ID      uint
+		Name    string
+		Version int
+	}
+
+	existing := &Product{ID: 1, Name: "Apple", Version: 2}
+
+	pb := New()
+	mb := pb.Model(&Product{})
+	eb := mb.Editing("Name").OptimisticLock("Version").
+		FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (interface{}, error) {
+			cp := *existing
+			return &cp, nil
+		}).
+		SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) error {
+			lock, _ := OptimisticLockFromContext(ctx.R.Context())
+			if lock.Value != existing.Version {
+				return ErrOptimisticLockConflict
+			}
+			p := obj.(*Product)
+			p.Version = existing.Version + 1
+			cp := *p
+			existing = &cp
+			return nil
+		})
+
+	save := func(fields map[string]string) (web.EventResponse, error) {
+		body := &bytes.Buffer{}
+		mw := multipart.NewWriter(body)
+		for k, v := range fields {
+			require.NoError(t, mw.WriteField(k, v))
+		}
+		require.NoError(t, mw.Close())
+		r := httptest.NewRequest("POST", "/products?id=1", body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		evCtx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+		var resp web.EventResponse
+		err := eb.SaveOverlayContent(evCtx, &resp)
+		return resp, err
+	}
+
+	resp, err := save(map[string]string{"Name": "Banana", ParamLockToken: "2"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.RunScript, `form["presets_lock_token"] = "3"`)
+
+	resp, err = save(map[string]string{"Name": "Cherry", ParamLockToken: "3"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.RunScript, `form["presets_lock_token"] = "4"`)
+	assert.Equal(t, &Product{ID: 1, Name: "Cherry", Version: 4}, existing)
+
+	_, err = save(map[string]string{"Name": "Durian", ParamLockToken: "3"})
+	var vErr *web.ValidationErrors
+	require.ErrorAs(t, err, &vErr)
+	assert.Equal(t, "Cherry", existing.Name)
+}
@@ -721,6 +721,19 @@ func (b *FieldsBuilder) getFieldNamesFromLayout() []string {
 	return ns
 }
 
+// shownFieldNames returns the fields of the layout, or all the fields which are not hidden.
+func (b *FieldsBuilder) shownFieldNames() (names []string) {
+	if b.fieldsLayout != nil {
+		return b.getFieldNamesFromLayout()
+	}
+	for _, f := range b.fields {
+		if !f.hidden {
+			names = append(names, f.name)
+		}
+	}
+	return
+}
+
 func (b *FieldsBuilder) Prepend(names ...any) (r *FieldsBuilder) {
 	return b.Only(append(names, b.fieldsLayout...)...)
 }
@@ -14,6 +14,9 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// ErrInvalidFilter is returned by DataOperators for a Filter they can not translate, like an unknown field.
+var ErrInvalidFilter = errors.New("invalid filter")
+
 // centralized tokens to avoid hard-coded strings
 const (
 	groupOpKey = "__op"
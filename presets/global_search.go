@@ -0,0 +1,306 @@
+package presets
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/samber/lo"
+	h "github.com/theplant/htmlgo"
+	"go.uber.org/zap"
+
+	"github.com/qor5/admin/v3/presets/actions"
+)
+
+const ParamGlobalSearchKeyword = "presets_global_search_keyword"
+
+const (
+	globalSearchPerModelDefault    = 5
+	globalSearchConcurrencyDefault = 4
+)
+
+// GlobalSearchHitFunc returns the title and the snippet of the record in the global search results.
+type GlobalSearchHitFunc func(evCtx *web.EventContext, obj any) (title, snippet string)
+
+type GlobalSearchResult struct {
+	Model   *ModelBuilder
+	ID      string
+	Title   string
+	Snippet string
+	Score   float64
+}
+
+// GlobalSearchOff hides the global search box in the default layout.
+func (b *Builder) GlobalSearchOff(v bool) (r *Builder) {
+	b.globalSearchOff = v
+	return b
+}
+
+// GlobalSearchPerModel is the max number of results of each model, defaults to 5.
+func (b *Builder) GlobalSearchPerModel(v int) (r *Builder) {
+	b.globalSearchPerModel = v
+	return b
+}
+
+// GlobalSearchConcurrency is the max number of models searched at the same time, defaults to 4.
+func (b *Builder) GlobalSearchConcurrency(v int) (r *Builder) {
+	b.globalSearchConcurrency = v
+	return b
+}
+
+// GlobalSearchOff excludes the model from the global search.
+func (mb *ModelBuilder) GlobalSearchOff(v bool) (r *ModelBuilder) {
+	mb.globalSearchOff = v
+	return mb
+}
+
+// GlobalSearchHitFunc customizes the title and the snippet of the model's records in the global search results,
+// the title defaults to the PageTitle of the record and the snippet to the search column containing the keyword.
+func (mb *ModelBuilder) GlobalSearchHitFunc(v GlobalSearchHitFunc) (r *ModelBuilder) {
+	mb.globalSearchHitFunc = v
+	return mb
+}
+
+func (mb *ModelBuilder) globalSearchable(evCtx *web.EventContext) bool {
+	lb := mb.listing
+	return !mb.globalSearchOff && !mb.singleton &&
+		!lb.keywordSearchOff && len(lb.searchColumns) > 0 && lb.Searcher != nil &&
+		mb.Info().Verifier().Do(PermList).WithReq(evCtx.R).IsAllowed() == nil
+}
+
+// GlobalSearch searches the keyword in the search columns of the models which the request is allowed to list,
+// the results are ranked by how the titles match the keyword and by the order of each model's results.
+func (b *Builder) GlobalSearch(evCtx *web.EventContext, keyword string) []*GlobalSearchResult {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil
+	}
+	perModel := b.globalSearchPerModel
+	if perModel <= 0 {
+		perModel = globalSearchPerModelDefault
+	}
+
+	concurrency := b.globalSearchConcurrency
+	if concurrency <= 0 {
+		concurrency = globalSearchConcurrencyDefault
+	}
+
+	mbs := lo.Filter(b.models, func(mb *ModelBuilder, _ int) bool {
+		return mb.globalSearchable(evCtx)
+	})
+	resultsOfModels := make([][]*GlobalSearchResult, len(mbs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, mb := range mbs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			// the searches may set values to the context of their own copies
+			modelCtx := *evCtx
+			results, err := mb.safeGlobalSearch(&modelCtx, keyword, perModel)
+			if err != nil {
+				b.logger.Warn("global search", zap.String("model", mb.uriName), zap.Error(err))
+				return
+			}
+			resultsOfModels[i] = results
+		}()
+	}
+	wg.Wait()
+
+	results := lo.Flatten(resultsOfModels)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// safeGlobalSearch reports the panic of the searcher of the model as its error,
+// so that it doesn't crash the process from the goroutine of GlobalSearch.
+func (mb *ModelBuilder) safeGlobalSearch(evCtx *web.EventContext, keyword string, limit int) (results []*GlobalSearchResult, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = errors.Errorf("global search panic: %v", v)
+		}
+	}()
+	return mb.globalSearch(evCtx, keyword, limit)
+}
+
+func (mb *ModelBuilder) globalSearch(evCtx *web.EventContext, keyword string, limit int) ([]*GlobalSearchResult, error) {
+	params := mb.listing.NewSearchParams(evCtx, keyword, nil, nil)
+	params.Page = 1
+	params.PerPage = int64(limit)
+	result, err := mb.listing.Searcher(evCtx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*GlobalSearchResult
+	nodes := reflect.ValueOf(result.Nodes)
+	for i := 0; i < nodes.Len() && i < limit; i++ {
+		obj := nodes.Index(i).Interface()
+		id := ObjectID(obj)
+		var title, snippet string
+		if mb.globalSearchHitFunc != nil {
+			title, snippet = mb.globalSearchHitFunc(evCtx, obj)
+		} else {
+			title, snippet = mb.defaultGlobalSearchHit(evCtx, obj, id, keyword)
+		}
+		results = append(results, &GlobalSearchResult{
+			Model:   mb,
+			ID:      id,
+			Title:   title,
+			Snippet: snippet,
+			Score:   globalSearchScore(keyword, title, i),
+		})
+	}
+	return results, nil
+}
+
+// defaultGlobalSearchHit takes the title and the snippet from the search columns which the request is allowed to list.
+func (mb *ModelBuilder) defaultGlobalSearchHit(evCtx *web.EventContext, obj any, id, keyword string) (title, snippet string) {
+	title = getPageTitle(obj, "")
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	for _, col := range mb.listing.searchColumns {
+		name, fv := globalSearchFieldByColumn(v, col)
+		if !fv.IsValid() || fv.Kind() != reflect.String {
+			continue
+		}
+		if mb.Info().Verifier().Do(PermList).ObjectOn(obj).SnakeOn("f_"+name).WithReq(evCtx.R).IsAllowed() != nil {
+			continue
+		}
+		s := fv.String()
+		if title == "" && s != "" {
+			title = s
+			continue
+		}
+		if snippet == "" && s != title && strings.Contains(strings.ToLower(s), strings.ToLower(keyword)) {
+			snippet = globalSearchSnippet(s, keyword)
+		}
+	}
+	if title == "" {
+		title = "#" + id
+	}
+	return
+}
+
+// globalSearchFieldByColumn finds the struct field of the search column, like `name` or `products.name` of Name.
+func globalSearchFieldByColumn(v reflect.Value, col string) (name string, fv reflect.Value) {
+	if v.Kind() != reflect.Struct {
+		return "", reflect.Value{}
+	}
+	if i := strings.LastIndex(col, "."); i >= 0 {
+		col = col[i+1:]
+	}
+	col = strings.Trim(col, "`\"")
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.IsExported() && (strings.EqualFold(f.Name, col) || lo.SnakeCase(f.Name) == col) {
+			return f.Name, v.Field(i)
+		}
+	}
+	return "", reflect.Value{}
+}
+
+const globalSearchSnippetRadius = 40
+
+// globalSearchSnippet excerpts the text around the keyword.
+func globalSearchSnippet(s, keyword string) string {
+	runes := []rune(s)
+	i := strings.Index(strings.ToLower(s), strings.ToLower(keyword))
+	if i < 0 {
+		i = 0
+	}
+	start := len([]rune(s[:i])) - globalSearchSnippetRadius
+	end := len([]rune(s[:i])) + len([]rune(keyword)) + globalSearchSnippetRadius
+	prefix, suffix := "…", "…"
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(runes) {
+		end, suffix = len(runes), ""
+	}
+	return prefix + string(runes[start:end]) + suffix
+}
+
+// globalSearchScore ranks the exact, prefix and partial matches of the title in tiers, then by the rank in the model.
+func globalSearchScore(keyword, title string, rank int) float64 {
+	kw, t := strings.ToLower(keyword), strings.ToLower(title)
+	var score float64
+	switch {
+	case t == kw:
+		score = 3
+	case strings.HasPrefix(t, kw):
+		score = 2
+	case strings.Contains(t, kw):
+		score = 1
+	}
+	return score + 1/float64(rank+2)
+}
+
+func (b *Builder) globalSearchBox(ctx *web.EventContext) h.HTMLComponent {
+	if b.globalSearchOff {
+		return nil
+	}
+	msgr := MustGetMessages(ctx.R)
+	return h.Div(
+		VTextField().
+			Placeholder(msgr.Search).
+			PrependInnerIcon("mdi-magnify").
+			Variant(FieldVariantOutlined).
+			Density(DensityCompact).
+			HideDetails(true).
+			Clearable(true).
+			Attr("@keyup.enter", web.Plaid().
+				EventFunc(actions.GlobalSearch).
+				Query(ParamGlobalSearchKeyword, web.Var("$event.target.value")).
+				Go()),
+	).Class("mx-4 mt-2")
+}
+
+func (b *Builder) globalSearchResults(ctx *web.EventContext) (r web.EventResponse, err error) {
+	msgr := MustGetMessages(ctx.R)
+	keyword := ctx.R.FormValue(ParamGlobalSearchKeyword)
+	results := b.GlobalSearch(ctx, keyword)
+
+	var items []h.HTMLComponent
+	for _, result := range results {
+		info := result.Model.Info()
+		item := VListItem(
+			VListItemTitle(h.Text(result.Title)),
+			VListItemSubtitle(h.Text(strings.TrimSpace(fmt.Sprintf("%s %s", info.LabelName(ctx, true), result.Snippet)))),
+		)
+		if info.HasDetailing() && !info.DetailingInDrawer() {
+			item.Href(info.DetailingHref(result.ID))
+		} else {
+			event := actions.Edit
+			if info.HasDetailing() {
+				event = actions.DetailingDrawer
+			}
+			item.Attr("@click", web.Plaid().
+				URL(info.ListingHref()).
+				EventFunc(event).
+				Query(ParamID, result.ID).
+				Go()+"; vars.presetsDialog = false")
+		}
+		items = append(items, item)
+	}
+
+	var body h.HTMLComponent = VList(items...)
+	if len(items) == 0 {
+		body = h.Div(h.Text(msgr.SearchNoResults)).Class("text-medium-emphasis pa-4")
+	}
+	b.dialog(ctx, &r, VCard(
+		VCardTitle(h.Text(fmt.Sprintf("%s: %s", msgr.SearchResults, keyword))),
+		VCardText(body),
+	), "600")
+	return
+}
@@ -0,0 +1,168 @@
+package presets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/perm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalSearch(t *testing.T) {
+	type Product struct {
+		ID          uint
+		Name        string
+		Description string
+	}
+	type Category struct {
+		ID   uint
+		Name string
+	}
+	type Secret struct {
+		ID   uint
+		Name string
+	}
+	type Tag struct {
+		ID   uint
+		Name string
+	}
+	products := []*Product{
+		{ID: 1, Name: "Red Apple", Description: "a red fruit"},
+		{ID: 2, Name: "Cherry", Description: "small red fruit"},
+		{ID: 3, Name: "Red", Description: "color"},
+	}
+	search := func(nodes func(keyword string) any) SearchFunc {
+		return func(evCtx *web.EventContext, params *SearchParams) (*SearchResult, error) {
+			return &SearchResult{Nodes: nodes(strings.ToLower(params.Keyword))}, nil
+		}
+	}
+
+	b := New().Permission(perm.New().Policies(
+		perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(perm.Anything).On(perm.Anything),
+		perm.PolicyFor(perm.Anybody).WhoAre(perm.Denied).ToDo(PermList).On("*:secrets", "*:secrets:*"),
+	).SubjectsFunc(func(r *http.Request) []string {
+		return []string{"viewer"}
+	}))
+	b.Model(&Product{}).Listing().SearchFunc(search(func(keyword string) any {
+		var r []*Product
+		for _, p := range products {
+			if strings.Contains(strings.ToLower(p.Name+" "+p.Description), keyword) {
+				r = append(r, p)
+			}
+		}
+		return r
+	}))
+	b.Model(&Category{}).GlobalSearchHitFunc(func(evCtx *web.EventContext, obj any) (title, snippet string) {
+		return "Category " + obj.(*Category).Name, "custom"
+	}).Listing().SearchFunc(search(func(keyword string) any {
+		return []*Category{{ID: 1, Name: "Fruits"}}
+	}))
+	b.Model(&Secret{}).Listing().SearchFunc(search(func(keyword string) any {
+		return []*Secret{{ID: 1, Name: "red"}}
+	}))
+	b.Model(&Tag{}).GlobalSearchOff(true).Listing().SearchFunc(search(func(keyword string) any {
+		return []*Tag{{ID: 1, Name: "red"}}
+	}))
+
+	evCtx := &web.EventContext{R: httptest.NewRequest("GET", "/", nil)}
+	results := b.GlobalSearch(evCtx, " red ")
+	var hits [][2]string
+	for _, r := range results {
+		hits = append(hits, [2]string{r.Model.Info().URIName() + ":" + r.ID + ":" + r.Title, r.Snippet})
+	}
+	assert.Equal(t, [][2]string{
+		{"products:3:Red", ""},
+		{"products:1:Red Apple", "a red fruit"},
+		{"categories:1:Category Fruits", "custom"},
+		{"products:2:Cherry", "small red fruit"},
+	}, hits)
+
+	assert.Empty(t, b.GlobalSearch(evCtx, " "))
+
+	b.GlobalSearchPerModel(1)
+	results = b.GlobalSearch(evCtx, "red")
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Red Apple", results[0].Title)
+
+	// a panic of a searcher only fails the results of its model
+	type Broken struct {
+		ID   uint
+		Name string
+	}
+	broken := b.Model(&Broken{})
+	broken.Listing().SearchFunc(func(*web.EventContext, *SearchParams) (*SearchResult, error) {
+		panic("broken searcher")
+	})
+	require.True(t, broken.globalSearchable(evCtx))
+	assert.NotPanics(t, func() {
+		results = b.GlobalSearch(evCtx, "red")
+	})
+	assert.Len(t, results, 2)
+}
+
+func TestGlobalSearchSnippet(t *testing.T) {
+	long := strings.Repeat("a", 50) + "Keyword" + strings.Repeat("b", 50)
+	assert.Equal(t, "…"+strings.Repeat("a", 40)+"Keyword"+strings.Repeat("b", 40)+"…", globalSearchSnippet(long, "keyword"))
+	assert.Equal(t, "short keyword", globalSearchSnippet("short keyword", "KEYWORD"))
+}
+
+func TestGlobalSearchHitFieldPermission(t *testing.T) {
+	type Note struct {
+		ID    uint
+		Title string
+		Body  string
+	}
+	b := New().Permission(perm.New().Policies(
+		perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(perm.Anything).On(perm.Anything),
+		perm.PolicyFor("viewer").WhoAre(perm.Denied).ToDo(PermList).On("*:notes:*:f_body:*"),
+	).SubjectsFunc(func(r *http.Request) []string {
+		return []string{r.Header.Get("Role")}
+	}))
+	b.Model(&Note{}).Listing().SearchColumns("title", "body").
+		SearchFunc(func(*web.EventContext, *SearchParams) (*SearchResult, error) {
+			return &SearchResult{Nodes: []*Note{{ID: 1, Title: "Plan", Body: "the secret plan"}}}, nil
+		})
+
+	search := func(role string) *GlobalSearchResult {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Role", role)
+		results := b.GlobalSearch(&web.EventContext{R: r}, "secret")
+		require.Len(t, results, 1)
+		return results[0]
+	}
+	assert.Equal(t, "the secret plan", search("editor").Snippet)
+	assert.Empty(t, search("viewer").Snippet)
+}
+
+func TestGlobalSearchConcurrency(t *testing.T) {
+	type (
+		Apple  struct{ ID uint }
+		Banana struct{ ID uint }
+		Cherry struct{ ID uint }
+		Durian struct{ ID uint }
+	)
+	var running, maxRunning int32
+	b := New().GlobalSearchConcurrency(2)
+	for _, model := range []any{&Apple{}, &Banana{}, &Cherry{}, &Durian{}} {
+		b.Model(model).Listing().SearchColumns("name").SearchFunc(func(*web.EventContext, *SearchParams) (*SearchResult, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return &SearchResult{Nodes: []any{}}, nil
+		})
+	}
+	b.GlobalSearch(&web.EventContext{R: httptest.NewRequest("GET", "/", nil)}, "x")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+}
@@ -0,0 +1,71 @@
+package gorm2op
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+// Aggregate computes the aggregates in one query with the conditions of the Search.
+func (op *DataOperatorBuilder) Aggregate(evCtx *web.EventContext, params *presets.SearchParams, aggregates []*presets.Aggregate) (r map[string]any, err error) {
+	if len(aggregates) == 0 {
+		return map[string]any{}, nil
+	}
+	wh, err := op.searchDB(evCtx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &gorm.Statement{DB: wh}
+	if err = stmt.Parse(params.Model); err != nil {
+		return nil, err
+	}
+	var selects []string
+	for i, a := range aggregates {
+		expr, err := aggregateExpr(stmt, a)
+		if err != nil {
+			return nil, err
+		}
+		selects = append(selects, fmt.Sprintf("%s AS %s", expr, stmt.Quote(aggregateAlias(i))))
+	}
+
+	row := map[string]any{}
+	if err = wh.Select(strings.Join(selects, ", ")).Scan(&row).Error; err != nil {
+		return nil, err
+	}
+	r = map[string]any{}
+	for i, a := range aggregates {
+		r[a.Field] = row[aggregateAlias(i)]
+	}
+	return
+}
+
+func aggregateAlias(i int) string {
+	return fmt.Sprintf("aggregate_%d", i)
+}
+
+func aggregateExpr(stmt *gorm.Statement, a *presets.Aggregate) (string, error) {
+	if a.Func == presets.AggregateSQL {
+		if a.SQL == "" {
+			return "", errors.Errorf("aggregate %s: sql is required", a.Field)
+		}
+		return a.SQL, nil
+	}
+	field := stmt.Schema.LookUpField(a.Field)
+	if field == nil || field.DBName == "" {
+		return "", errors.Errorf("aggregate %s: field not found", a.Field)
+	}
+	column := stmt.Quote(field.DBName)
+	switch a.Func {
+	case presets.AggregateSum, presets.AggregateAvg, presets.AggregateMin, presets.AggregateMax:
+		return fmt.Sprintf("%s(%s)", strings.ToUpper(string(a.Func)), column), nil
+	case presets.AggregateCountDistinct:
+		return fmt.Sprintf("COUNT(DISTINCT %s)", column), nil
+	}
+	return "", errors.Errorf("aggregate %s: unsupported func %s", a.Field, a.Func)
+}
@@ -0,0 +1,48 @@
+package gorm2op
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+type DashboardLayoutStoreBuilder struct {
+	db *gorm.DB
+}
+
+var _ presets.DashboardLayoutStore = (*DashboardLayoutStoreBuilder)(nil)
+
+// DashboardLayoutStore persists presets.DashboardLayout into the dashboard_layouts table.
+func DashboardLayoutStore(db *gorm.DB) *DashboardLayoutStoreBuilder {
+	return &DashboardLayoutStoreBuilder{db: db}
+}
+
+func (s *DashboardLayoutStoreBuilder) AutoMigrate() *DashboardLayoutStoreBuilder {
+	if err := s.db.AutoMigrate(&presets.DashboardLayout{}); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *DashboardLayoutStoreBuilder) Get(ctx context.Context, userID string) (*presets.DashboardLayout, error) {
+	var layout presets.DashboardLayout
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&layout).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+func (s *DashboardLayoutStoreBuilder) Save(ctx context.Context, layout *presets.DashboardLayout) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"widgets", "updated_at"}),
+	}).Create(layout).Error
+}
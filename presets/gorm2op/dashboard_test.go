@@ -0,0 +1,39 @@
+package gorm2op
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+func TestDashboardLayoutStore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	s := DashboardLayoutStore(db).AutoMigrate()
+	ctx := context.Background()
+
+	layout, err := s.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Nil(t, layout)
+
+	require.NoError(t, s.Save(ctx, &presets.DashboardLayout{UserID: "1", Widgets: []*presets.DashboardWidgetLayout{{Name: "a", Width: 6}}}))
+	require.NoError(t, s.Save(ctx, &presets.DashboardLayout{UserID: "2", Widgets: []*presets.DashboardWidgetLayout{{Name: "a", Width: 4}}}))
+	require.NoError(t, s.Save(ctx, &presets.DashboardLayout{UserID: "1", Widgets: []*presets.DashboardWidgetLayout{{Name: "b", Width: 12}, {Name: "a", Width: 6}}}))
+
+	layout, err = s.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, []*presets.DashboardWidgetLayout{{Name: "b", Width: 12}, {Name: "a", Width: 6}}, layout.Widgets)
+	layout, err = s.Get(ctx, "2")
+	require.NoError(t, err)
+	assert.Equal(t, []*presets.DashboardWidgetLayout{{Name: "a", Width: 4}}, layout.Widgets)
+
+	var count int64
+	require.NoError(t, db.Model(&presets.DashboardLayout{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
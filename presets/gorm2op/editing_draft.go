@@ -0,0 +1,60 @@
+package gorm2op
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+type EditingDraftStoreBuilder struct {
+	db *gorm.DB
+}
+
+var _ presets.EditingDraftStore = (*EditingDraftStoreBuilder)(nil)
+
+// EditingDraftStore persists presets.EditingDraft into the editing_drafts table.
+func EditingDraftStore(db *gorm.DB) *EditingDraftStoreBuilder {
+	return &EditingDraftStoreBuilder{db: db}
+}
+
+func (s *EditingDraftStoreBuilder) AutoMigrate() *EditingDraftStoreBuilder {
+	if err := s.db.AutoMigrate(&presets.EditingDraft{}); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *EditingDraftStoreBuilder) Get(ctx context.Context, key presets.EditingDraftKey) (*presets.EditingDraft, error) {
+	var draft presets.EditingDraft
+	err := whereEditingDraftKey(s.db.WithContext(ctx), key).First(&draft).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+func (s *EditingDraftStoreBuilder) Save(ctx context.Context, draft *presets.EditingDraft) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "user_id"}, {Name: "model_name"}, {Name: "record_id"}, {Name: "section"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"form", "updated_at"}),
+	}).Create(draft).Error
+}
+
+func (s *EditingDraftStoreBuilder) Delete(ctx context.Context, key presets.EditingDraftKey) error {
+	return whereEditingDraftKey(s.db.WithContext(ctx), key).Delete(&presets.EditingDraft{}).Error
+}
+
+// whereEditingDraftKey doesn't use struct conditions which skip the empty RecordID and Section.
+func whereEditingDraftKey(db *gorm.DB, key presets.EditingDraftKey) *gorm.DB {
+	return db.Where("user_id = ? AND model_name = ? AND record_id = ? AND section = ?",
+		key.UserID, key.ModelName, key.RecordID, key.Section)
+}
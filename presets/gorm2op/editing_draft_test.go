@@ -0,0 +1,53 @@
+package gorm2op
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+func TestEditingDraftStore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	s := EditingDraftStore(db).AutoMigrate()
+	ctx := context.Background()
+
+	creating := presets.EditingDraftKey{UserID: "1", ModelName: "products"}
+	editing := presets.EditingDraftKey{UserID: "1", ModelName: "products", RecordID: "5"}
+	section := presets.EditingDraftKey{UserID: "1", ModelName: "products", RecordID: "5", Section: "Details"}
+
+	draft, err := s.Get(ctx, creating)
+	require.NoError(t, err)
+	assert.Nil(t, draft)
+
+	require.NoError(t, s.Save(ctx, &presets.EditingDraft{EditingDraftKey: creating, Form: "Name=new"}))
+	require.NoError(t, s.Save(ctx, &presets.EditingDraft{EditingDraftKey: editing, Form: "Name=a"}))
+	require.NoError(t, s.Save(ctx, &presets.EditingDraft{EditingDraftKey: section, Form: "Details.Code=c"}))
+	require.NoError(t, s.Save(ctx, &presets.EditingDraft{EditingDraftKey: editing, Form: "Name=ab"}))
+
+	forms := func() (r []string) {
+		for _, key := range []presets.EditingDraftKey{creating, editing, section} {
+			draft, err := s.Get(ctx, key)
+			require.NoError(t, err)
+			if draft == nil {
+				r = append(r, "")
+				continue
+			}
+			assert.Equal(t, key, draft.EditingDraftKey)
+			r = append(r, draft.Form)
+		}
+		return
+	}
+	assert.Equal(t, []string{"Name=new", "Name=ab", "Details.Code=c"}, forms())
+
+	require.NoError(t, s.Delete(ctx, editing))
+	assert.Equal(t, []string{"Name=new", "", "Details.Code=c"}, forms())
+	require.NoError(t, s.Delete(ctx, creating))
+	assert.Equal(t, []string{"", "", "Details.Code=c"}, forms())
+}
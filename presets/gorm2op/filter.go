@@ -0,0 +1,361 @@
+package gorm2op
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+// likeEscape is used instead of backslash which is an escape character in the string literals of mysql.
+const likeEscape = "!"
+
+var likeEscaper = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+// ApplyFilter translates the filter tree into parameterized conditions of the model.
+// Fields are the Go field names or the columns of the model, conditions on fields of belongs to and has one
+// relations like `Category.Name` are checked with EXISTS subqueries, so they only match the records
+// which have the related record, and the columns of the model stay unambiguous for the other conditions.
+// Other fields are rejected with presets.ErrInvalidFilter.
+func ApplyFilter(db *gorm.DB, model any, f *presets.Filter) (*gorm.DB, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, errors.Wrap(err, "parse model")
+	}
+	ft := &filterTranslator{
+		dialect: db.Dialector.Name(),
+		schema:  stmt.Schema,
+	}
+	expr, err := ft.translate(f)
+	if err != nil {
+		return nil, errors.Wrap(presets.ErrInvalidFilter, err.Error())
+	}
+	if expr == nil {
+		return db, nil
+	}
+	return db.Where(expr), nil
+}
+
+type filterTranslator struct {
+	dialect string
+	schema  *schema.Schema
+}
+
+// relationHop is a relation in the path of a filter field, the related table is aliased
+// with the path like `Category__Parent` and linked to the table of the previous hop.
+type relationHop struct {
+	rel    *schema.Relationship
+	alias  string
+	parent string
+}
+
+func (ft *filterTranslator) translate(f *presets.Filter) (clause.Expression, error) {
+	if f == nil {
+		return nil, nil
+	}
+	var exprs []clause.Expression
+	if f.Condition != nil {
+		expr, err := ft.condition(f.Condition)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(f.And) > 0 {
+		and, err := ft.translateAll(f.And)
+		if err != nil {
+			return nil, err
+		}
+		if len(and) > 0 {
+			exprs = append(exprs, clause.And(and...))
+		}
+	}
+	if len(f.Or) > 0 {
+		or, err := ft.translateAll(f.Or)
+		if err != nil {
+			return nil, err
+		}
+		if len(or) > 0 {
+			exprs = append(exprs, clause.Or(or...))
+		}
+	}
+	if f.Not != nil {
+		not, err := ft.translate(f.Not)
+		if err != nil {
+			return nil, err
+		}
+		if not != nil {
+			exprs = append(exprs, clause.Not(not))
+		}
+	}
+	switch len(exprs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return exprs[0], nil
+	}
+	return clause.And(exprs...), nil
+}
+
+func (ft *filterTranslator) translateAll(fs []*presets.Filter) (r []clause.Expression, err error) {
+	for _, f := range fs {
+		expr, err := ft.translate(f)
+		if err != nil {
+			return nil, err
+		}
+		if expr != nil {
+			r = append(r, expr)
+		}
+	}
+	return
+}
+
+// column resolves the field against the model schema, along with the relations in its path.
+func (ft *filterTranslator) column(name string) (clause.Column, *schema.Field, []relationHop, error) {
+	segs := strings.Split(name, ".")
+	s := ft.schema
+	table := clause.CurrentTable
+	var hops []relationHop
+	for i, seg := range segs[:len(segs)-1] {
+		rel, ok := s.Relationships.Relations[seg]
+		if !ok {
+			return clause.Column{}, nil, nil, errors.Errorf("unknown filter relation %q of %s", seg, s.Name)
+		}
+		if rel.Type != schema.BelongsTo && rel.Type != schema.HasOne {
+			return clause.Column{}, nil, nil, errors.Errorf("filter relation %q of %s is not belongs to or has one", seg, s.Name)
+		}
+		alias := strings.Join(segs[:i+1], "__")
+		hops = append(hops, relationHop{rel: rel, alias: alias, parent: table})
+		table = alias
+		s = rel.FieldSchema
+	}
+
+	field := s.LookUpField(segs[len(segs)-1])
+	if field == nil || field.DBName == "" {
+		return clause.Column{}, nil, nil, errors.Errorf("unknown filter field %q", name)
+	}
+	return clause.Column{Table: table, Name: field.DBName}, field, hops, nil
+}
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// exists wraps the condition on the related record into EXISTS subqueries, from the last hop to the first.
+func exists(hops []relationHop, expr clause.Expression) clause.Expression {
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := hops[i]
+		var conds []clause.Expression
+		for _, ref := range hop.rel.References {
+			switch {
+			case ref.PrimaryValue != "":
+				// the type column of polymorphic relations
+				conds = append(conds, clause.Eq{Column: clause.Column{Table: hop.alias, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+			case ref.OwnPrimaryKey:
+				// has one, the foreign key is in the related table
+				conds = append(conds, clause.Eq{
+					Column: clause.Column{Table: hop.alias, Name: ref.ForeignKey.DBName},
+					Value:  clause.Column{Table: hop.parent, Name: ref.PrimaryKey.DBName},
+				})
+			default:
+				conds = append(conds, clause.Eq{
+					Column: clause.Column{Table: hop.alias, Name: ref.PrimaryKey.DBName},
+					Value:  clause.Column{Table: hop.parent, Name: ref.ForeignKey.DBName},
+				})
+			}
+		}
+		for _, f := range hop.rel.FieldSchema.Fields {
+			if f.FieldType == deletedAtType && f.DBName != "" {
+				conds = append(conds, clause.Expr{SQL: "? IS NULL", Vars: []any{clause.Column{Table: hop.alias, Name: f.DBName}}})
+			}
+		}
+		conds = append(conds, expr)
+		expr = clause.Expr{
+			SQL:  "EXISTS (SELECT 1 FROM ? WHERE ?)",
+			Vars: []any{clause.Table{Name: hop.rel.FieldSchema.Table, Alias: hop.alias}, clause.And(conds...)},
+		}
+	}
+	return expr
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// coerce converts the value, which is usually a string from the query or a JSON value, to the type of the field.
+func coerce(field *schema.Field, v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	t := indirectType(field.FieldType)
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return cast.ToTimeE(v)
+	case t.Kind() == reflect.Bool:
+		return cast.ToBoolE(v)
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		return cast.ToInt64E(v)
+	case t.Kind() >= reflect.Uint && t.Kind() <= reflect.Uint64:
+		return cast.ToUint64E(v)
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return cast.ToFloat64E(v)
+	case t.Kind() == reflect.String:
+		return cast.ToStringE(v)
+	}
+	return v, nil
+}
+
+func isString(field *schema.Field) bool {
+	return indirectType(field.FieldType).Kind() == reflect.String
+}
+
+// compare wraps the column and the value to compare them with the same case sensitivity on all dialects,
+// folded strings are lowered, mysql compares the other strings as binary because of its case insensitive collations.
+func (ft *filterTranslator) compare(field *schema.Field, fold bool) string {
+	if !isString(field) {
+		return "?"
+	}
+	if fold {
+		return "LOWER(?)"
+	}
+	if ft.dialect == "mysql" {
+		return "CAST(? AS BINARY)"
+	}
+	return "?"
+}
+
+var globEscaper = strings.NewReplacer("[", "[[]", "*", "[*]", "?", "[?]")
+
+// like matches the value with the wildcards around it, prefix for EndsWith and suffix for StartsWith.
+func (ft *filterTranslator) like(col clause.Column, value string, prefix, suffix bool, fold bool) clause.Expression {
+	wildcards := func(pattern, wildcard string) string {
+		if prefix {
+			pattern = wildcard + pattern
+		}
+		if suffix {
+			pattern += wildcard
+		}
+		return pattern
+	}
+	if !fold && ft.dialect == "sqlite" {
+		// LIKE of sqlite is case insensitive
+		return clause.Expr{SQL: "? GLOB ?", Vars: []any{col, wildcards(globEscaper.Replace(value), "*")}}
+	}
+
+	pattern := wildcards(likeEscaper.Replace(value), "%")
+	escape := " ESCAPE '" + likeEscape + "'"
+	switch {
+	case !fold && ft.dialect == "mysql":
+		return clause.Expr{SQL: "CAST(? AS BINARY) LIKE CAST(? AS BINARY)" + escape, Vars: []any{col, pattern}}
+	case !fold:
+		return clause.Expr{SQL: "? LIKE ?" + escape, Vars: []any{col, pattern}}
+	case ft.dialect == "postgres":
+		return clause.Expr{SQL: "? ILIKE ?" + escape, Vars: []any{col, pattern}}
+	}
+	return clause.Expr{SQL: "LOWER(?) LIKE LOWER(?)" + escape, Vars: []any{col, pattern}}
+}
+
+func (ft *filterTranslator) condition(c *presets.FieldCondition) (clause.Expression, error) {
+	col, field, hops, err := ft.column(c.Field)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := ft.fieldCondition(c, col, field)
+	if err != nil {
+		return nil, err
+	}
+	return exists(hops, expr), nil
+}
+
+func (ft *filterTranslator) fieldCondition(c *presets.FieldCondition, col clause.Column, field *schema.Field) (clause.Expression, error) {
+
+	switch c.Operator {
+	case presets.FilterOperatorIsNull:
+		isNull, err := cast.ToBoolE(c.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "filter %s", c.Field)
+		}
+		if isNull {
+			return clause.Expr{SQL: "? IS NULL", Vars: []any{col}}, nil
+		}
+		return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{col}}, nil
+
+	case presets.FilterOperatorIn, presets.FilterOperatorNotIn:
+		rv := reflect.ValueOf(c.Value)
+		if c.Value == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+			return nil, errors.Errorf("filter %s %s requires a list", c.Field, c.Operator)
+		}
+		values := make([]any, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			v, err := coerce(field, rv.Index(i).Interface())
+			if err != nil {
+				return nil, errors.Wrapf(err, "filter %s", c.Field)
+			}
+			values = append(values, v)
+		}
+		if len(values) == 0 {
+			if c.Operator == presets.FilterOperatorIn {
+				return clause.Expr{SQL: "1 = 0"}, nil
+			}
+			return clause.Expr{SQL: "1 = 1"}, nil
+		}
+		l := ft.compare(field, c.Fold)
+		if isString(field) && c.Fold {
+			for i, v := range values {
+				values[i] = strings.ToLower(v.(string))
+			}
+		}
+		op := "IN"
+		if c.Operator == presets.FilterOperatorNotIn {
+			op = "NOT IN"
+		}
+		return clause.Expr{SQL: fmt.Sprintf("%s %s ?", l, op), Vars: []any{col, values}}, nil
+
+	case presets.FilterOperatorContains, presets.FilterOperatorStartsWith, presets.FilterOperatorEndsWith:
+		if !isString(field) {
+			return nil, errors.Errorf("filter %s %s requires a string field", c.Field, c.Operator)
+		}
+		prefix := c.Operator != presets.FilterOperatorStartsWith
+		suffix := c.Operator != presets.FilterOperatorEndsWith
+		return ft.like(col, cast.ToString(c.Value), prefix, suffix, c.Fold), nil
+	}
+
+	v, err := coerce(field, c.Value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "filter %s", c.Field)
+	}
+	var op string
+	switch c.Operator {
+	case presets.FilterOperatorEq, "":
+		if v == nil {
+			return clause.Expr{SQL: "? IS NULL", Vars: []any{col}}, nil
+		}
+		op = "="
+	case presets.FilterOperatorNeq:
+		if v == nil {
+			return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{col}}, nil
+		}
+		op = "<>"
+	case presets.FilterOperatorLt:
+		op = "<"
+	case presets.FilterOperatorLte:
+		op = "<="
+	case presets.FilterOperatorGt:
+		op = ">"
+	case presets.FilterOperatorGte:
+		op = ">="
+	default:
+		return nil, errors.Errorf("unsupported filter operator %q", c.Operator)
+	}
+	w := ft.compare(field, c.Fold)
+	return clause.Expr{SQL: fmt.Sprintf("%s %s %s", w, op, w), Vars: []any{col, v}}, nil
+}
@@ -0,0 +1,145 @@
+package gorm2op
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theplant/relay"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+func TestDataOperatorSearchFilter(t *testing.T) {
+	type Category struct {
+		ID   uint
+		Name string
+	}
+	type Product struct {
+		ID         uint
+		Name       string
+		Price      int
+		Note       *string
+		CategoryID uint
+		Category   *Category
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Category{}, &Product{}))
+	require.NoError(t, db.Create([]*Category{{ID: 1, Name: "Fruit"}, {ID: 2, Name: "Nut"}}).Error)
+	note := "100%_fresh"
+	require.NoError(t, db.Create([]*Product{
+		{ID: 1, Name: "Apple", Price: 3, CategoryID: 1, Note: &note},
+		{ID: 2, Name: "apricot", Price: 5, CategoryID: 1},
+		{ID: 3, Name: "Almond", Price: 8, CategoryID: 2},
+		{ID: 4, Name: "Banana", Price: 2, CategoryID: 1},
+	}).Error)
+
+	op := DataOperator(db)
+	evCtx := &web.EventContext{R: httptest.NewRequest("GET", "/products", nil)}
+	search := func(f *presets.Filter) ([]uint, error) {
+		result, err := op.Search(evCtx, &presets.SearchParams{
+			Model:   &Product{},
+			Filter:  f,
+			OrderBy: []relay.Order{{Field: "ID", Direction: relay.OrderDirectionAsc}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var ids []uint
+		for _, p := range result.Nodes.([]*Product) {
+			ids = append(ids, p.ID)
+		}
+		return ids, nil
+	}
+	cond := func(field string, operator presets.FilterOperator, value any, fold bool) *presets.Filter {
+		return &presets.Filter{Condition: &presets.FieldCondition{Field: field, Operator: operator, Value: value, Fold: fold}}
+	}
+
+	cases := []struct {
+		name   string
+		filter *presets.Filter
+		ids    []uint
+	}{
+		{"eq value from query", cond("Price", presets.FilterOperatorEq, "3", false), []uint{1}},
+		{"eq column name", cond("price", presets.FilterOperatorEq, 5, false), []uint{2}},
+		{"eq is case sensitive", cond("Name", presets.FilterOperatorEq, "apple", false), nil},
+		{"eq fold", cond("Name", presets.FilterOperatorEq, "apple", true), []uint{1}},
+		{"neq", cond("Price", presets.FilterOperatorNeq, 3, false), []uint{2, 3, 4}},
+		{"range", &presets.Filter{And: []*presets.Filter{
+			cond("Price", presets.FilterOperatorGte, 3, false),
+			cond("Price", presets.FilterOperatorLt, 8, false),
+		}}, []uint{1, 2}},
+		{"in", cond("ID", presets.FilterOperatorIn, []any{1.0, "4"}, false), []uint{1, 4}},
+		{"in empty", cond("ID", presets.FilterOperatorIn, []any{}, false), nil},
+		{"not in fold", cond("Name", presets.FilterOperatorNotIn, []string{"APPLE", "banana"}, true), []uint{2, 3}},
+		{"is null", cond("Note", presets.FilterOperatorIsNull, true, false), []uint{2, 3, 4}},
+		{"eq nil", cond("Note", presets.FilterOperatorEq, nil, false), []uint{2, 3, 4}},
+		{"starts with is case sensitive", cond("Name", presets.FilterOperatorStartsWith, "Ap", false), []uint{1}},
+		{"starts with fold", cond("Name", presets.FilterOperatorStartsWith, "ap", true), []uint{1, 2}},
+		{"ends with", cond("Name", presets.FilterOperatorEndsWith, "ond", false), []uint{3}},
+		{"contains wildcards literally", cond("Note", presets.FilterOperatorContains, "%_", false), []uint{1}},
+		{"contains wildcards literally fold", cond("Note", presets.FilterOperatorContains, "0%_F", true), []uint{1}},
+		{"contains glob characters literally", cond("Name", presets.FilterOperatorContains, "*", false), nil},
+		{"or and not", &presets.Filter{
+			Or: []*presets.Filter{
+				cond("Price", presets.FilterOperatorGt, 4, false),
+				cond("Name", presets.FilterOperatorEq, "Banana", false),
+			},
+			Not: cond("Name", presets.FilterOperatorStartsWith, "Al", false),
+		}, []uint{2, 4}},
+		{"empty not", &presets.Filter{Not: &presets.Filter{}}, []uint{1, 2, 3, 4}},
+		{"belongs to", &presets.Filter{And: []*presets.Filter{
+			cond("Category.Name", presets.FilterOperatorEq, "fruit", true),
+			cond("Name", presets.FilterOperatorContains, "a", false),
+		}}, []uint{2, 4}},
+		{"not belongs to", &presets.Filter{Not: cond("Category.Name", presets.FilterOperatorEq, "Fruit", false)}, []uint{3}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ids, err := search(c.filter)
+			require.NoError(t, err)
+			assert.Equal(t, c.ids, ids)
+		})
+	}
+
+	// the columns of the model are not ambiguous with the relation conditions
+	result, err := op.Search(evCtx, &presets.SearchParams{
+		Model:          &Product{},
+		KeywordColumns: []string{"name"},
+		Keyword:        "an",
+		SQLConditions:  []*presets.SQLCondition{{Query: "price < ?", Args: []any{8}}},
+		Filter:         cond("Category.Name", presets.FilterOperatorEq, "Fruit", false),
+		OrderBy:        []relay.Order{{Field: "ID", Direction: relay.OrderDirectionAsc}},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Nodes, 1)
+	assert.Equal(t, uint(4), result.Nodes.([]*Product)[0].ID)
+
+	for _, f := range []*presets.Filter{
+		cond("Secret", presets.FilterOperatorEq, 1, false),
+		cond("name; DROP TABLE products", presets.FilterOperatorEq, 1, false),
+		cond("Category.Secret", presets.FilterOperatorEq, 1, false),
+		cond("Price", presets.FilterOperatorContains, "1", false),
+		cond("Price", presets.FilterOperatorIn, 1, false),
+		cond("Price", presets.FilterOperatorEq, "x", false),
+		cond("Price", "regexp", 1, false),
+	} {
+		_, err := search(f)
+		assert.ErrorIs(t, err, presets.ErrInvalidFilter, f.Condition.Field)
+	}
+
+	// the filter of the listing is applied by the SQLConditions
+	result, err = op.Search(evCtx, &presets.SearchParams{
+		Model:                 &Product{},
+		Filter:                cond("Secret", presets.FilterOperatorEq, 1, false),
+		FilterInSQLConditions: true,
+	})
+	require.NoError(t, err)
+	assert.Len(t, result.Nodes, 4)
+}
@@ -0,0 +1,26 @@
+package gorm2op
+
+import (
+	"context"
+
+	"github.com/qor5/web/v3"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+// ImportTransact is a presets.ImportTransactFunc running the batches of presets.ImportBuilder in transactions of the db,
+// the nested calls for the rows run in savepoints of the transaction of the batch.
+func ImportTransact(db *gorm.DB) presets.ImportTransactFunc {
+	return func(evCtx *web.EventContext, f func(txCtx *web.EventContext) error) error {
+		wh := db
+		if tx, ok := evCtx.ContextValue(CtxKeyDB{}).(*gorm.DB); ok {
+			wh = tx
+		}
+		return wh.Transaction(func(tx *gorm.DB) error {
+			txCtx := *evCtx
+			txCtx.R = evCtx.R.WithContext(context.WithValue(evCtx.R.Context(), CtxKeyDB{}, tx))
+			return f(&txCtx)
+		})
+	}
+}
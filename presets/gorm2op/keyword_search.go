@@ -0,0 +1,222 @@
+package gorm2op
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+// KeywordSearch is a keyword search strategy of the DataOperator for ListingBuilder.KeywordSearch.
+type KeywordSearch interface {
+	presets.KeywordSearch
+	// Search filters the records of the model matching the keyword, and orders them by relevance if orderByRelevance.
+	Search(db *gorm.DB, model any, keyword string, orderByRelevance bool) (*gorm.DB, error)
+}
+
+// keywordSearchAlias is the alias of the joined relevance of the keyword searches.
+const keywordSearchAlias = "_keyword_search"
+
+var relevanceOrder = clause.OrderByColumn{
+	Column: clause.Column{Table: keywordSearchAlias, Name: "relevance"},
+	Desc:   true,
+}
+
+func parseSchema(db *gorm.DB, model any) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, errors.Wrap(err, "parse model")
+	}
+	return stmt.Schema, nil
+}
+
+// searchColumns resolves the Go field names or the columns of the model to the columns.
+func searchColumns(s *schema.Schema, columns []string) ([]string, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("columns are required")
+	}
+	var r []string
+	for _, c := range columns {
+		f := s.LookUpField(c)
+		if f == nil || f.DBName == "" {
+			return nil, errors.Errorf("unknown column %q of %s", c, s.Name)
+		}
+		r = append(r, f.DBName)
+	}
+	return r, nil
+}
+
+type PostgresFullTextSearchBuilder struct {
+	column string
+	config string
+}
+
+var _ KeywordSearch = (*PostgresFullTextSearchBuilder)(nil)
+
+// PostgresFullTextSearch matches the keyword with websearch_to_tsquery on the tsvector column,
+// which could be created with the generated column and the GIN index by Migrate.
+func PostgresFullTextSearch(column string) *PostgresFullTextSearchBuilder {
+	return &PostgresFullTextSearchBuilder{column: column, config: "simple"}
+}
+
+// Config is the text search configuration, like english, defaults to simple.
+func (b *PostgresFullTextSearchBuilder) Config(v string) *PostgresFullTextSearchBuilder {
+	b.config = v
+	return b
+}
+
+func (b *PostgresFullTextSearchBuilder) KeywordSearchName() string {
+	return "postgres full-text search"
+}
+
+func (b *PostgresFullTextSearchBuilder) Search(db *gorm.DB, _ any, keyword string, orderByRelevance bool) (*gorm.DB, error) {
+	col := clause.Column{Table: clause.CurrentTable, Name: b.column}
+	query := clause.Expr{SQL: "websearch_to_tsquery(?::regconfig, ?)", Vars: []any{b.config, keyword}}
+	db = db.Where("? @@ ?", col, query)
+	if orderByRelevance {
+		db = db.Joins(fmt.Sprintf("CROSS JOIN LATERAL (SELECT ts_rank(?, ?) AS relevance) AS %s", keywordSearchAlias), col, query).
+			Order(relevanceOrder)
+	}
+	return db, nil
+}
+
+var configReg = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// Migrate adds the column generated from the columns of the model, with a GIN index on it.
+func (b *PostgresFullTextSearchBuilder) Migrate(db *gorm.DB, model any, columns ...string) error {
+	if !configReg.MatchString(b.config) {
+		return errors.Errorf("invalid text search config %q", b.config)
+	}
+	s, err := parseSchema(db, model)
+	if err != nil {
+		return err
+	}
+	cols, err := searchColumns(s, columns)
+	if err != nil {
+		return err
+	}
+
+	quote := db.Statement.Quote
+	var docs []string
+	for _, c := range cols {
+		docs = append(docs, fmt.Sprintf("coalesce(%s::text, '')", quote(c)))
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf(
+			"ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s tsvector GENERATED ALWAYS AS (to_tsvector('%s', %s)) STORED",
+			quote(s.Table), quote(b.column), b.config, strings.Join(docs, " || ' ' || "),
+		)).Error; err != nil {
+			return errors.Wrap(err, "add tsvector column")
+		}
+		return errors.Wrap(tx.Exec(fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (%s)",
+			quote(fmt.Sprintf("idx_%s_%s", s.Table, b.column)), quote(s.Table), quote(b.column),
+		)).Error, "create gin index")
+	})
+}
+
+type SQLiteFullTextSearchBuilder struct {
+	table string
+}
+
+var _ KeywordSearch = (*SQLiteFullTextSearchBuilder)(nil)
+
+// SQLiteFullTextSearch matches the keyword as prefixes of the terms in the FTS5 table,
+// which could be created with the triggers syncing it by Migrate.
+// FTS5 of github.com/mattn/go-sqlite3 requires the build tag sqlite_fts5.
+func SQLiteFullTextSearch(table string) *SQLiteFullTextSearchBuilder {
+	return &SQLiteFullTextSearchBuilder{table: table}
+}
+
+func (b *SQLiteFullTextSearchBuilder) KeywordSearchName() string {
+	return "sqlite full-text search"
+}
+
+// fts5Query quotes the terms of the keyword to match them as prefixes, the FTS5 syntax in the keyword is not supported.
+func fts5Query(keyword string) string {
+	var terms []string
+	for _, t := range strings.Fields(keyword) {
+		terms = append(terms, `"`+strings.ReplaceAll(t, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+func (b *SQLiteFullTextSearchBuilder) Search(db *gorm.DB, model any, keyword string, orderByRelevance bool) (*gorm.DB, error) {
+	query := fts5Query(keyword)
+	if query == "" {
+		return db, nil
+	}
+	s, err := parseSchema(db, model)
+	if err != nil {
+		return nil, err
+	}
+	if s.PrioritizedPrimaryField == nil {
+		return nil, errors.Errorf("%s has no primary field", s.Name)
+	}
+
+	table := db.Statement.Quote(b.table)
+	// bm25 is lower for the better matches
+	db = db.Joins(fmt.Sprintf(
+		"JOIN (SELECT rowid, -bm25(%s) AS relevance FROM %s WHERE %s MATCH ?) AS %s ON %s.rowid = ?",
+		table, table, table, keywordSearchAlias, keywordSearchAlias,
+	), query, clause.Column{Table: clause.CurrentTable, Name: s.PrioritizedPrimaryField.DBName})
+	if orderByRelevance {
+		db = db.Order(relevanceOrder)
+	}
+	return db, nil
+}
+
+// Migrate creates the external content FTS5 table of the columns of the model, and the triggers syncing it.
+func (b *SQLiteFullTextSearchBuilder) Migrate(db *gorm.DB, model any, columns ...string) error {
+	s, err := parseSchema(db, model)
+	if err != nil {
+		return err
+	}
+	if s.PrioritizedPrimaryField == nil {
+		return errors.Errorf("%s has no primary field", s.Name)
+	}
+	cols, err := searchColumns(s, columns)
+	if err != nil {
+		return err
+	}
+
+	quote := db.Statement.Quote
+	fts, table, pk := quote(b.table), quote(s.Table), quote(s.PrioritizedPrimaryField.DBName)
+	quotedCols := make([]string, len(cols))
+	newCols := make([]string, len(cols))
+	oldCols := make([]string, len(cols))
+	for i, c := range cols {
+		quotedCols[i] = quote(c)
+		newCols[i] = "new." + quotedCols[i]
+		oldCols[i] = "old." + quotedCols[i]
+	}
+	colList := strings.Join(quotedCols, ", ")
+	insert := fmt.Sprintf("INSERT INTO %s(rowid, %s) VALUES (new.%s, %s);", fts, colList, pk, strings.Join(newCols, ", "))
+	remove := fmt.Sprintf("INSERT INTO %s(%s, rowid, %s) VALUES ('delete', old.%s, %s);", fts, fts, colList, pk, strings.Join(oldCols, ", "))
+
+	stmts := []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s, content='%s', content_rowid='%s')",
+			fts, colList, s.Table, s.PrioritizedPrimaryField.DBName),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER INSERT ON %s BEGIN %s END",
+			quote(b.table+"_ai"), table, insert),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER DELETE ON %s BEGIN %s END",
+			quote(b.table+"_ad"), table, remove),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER UPDATE ON %s BEGIN %s %s END",
+			quote(b.table+"_au"), table, remove, insert),
+		fmt.Sprintf("INSERT INTO %s(%s) VALUES ('rebuild')", fts, fts),
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range stmts {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return errors.Wrap(err, "migrate fts5 table")
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,102 @@
+package gorm2op
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theplant/relay"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+type keywordSearchProduct struct {
+	ID          uint
+	Name        string
+	Description string
+}
+
+// go test -tags sqlite_fts5 to run with FTS5
+func TestSQLiteFullTextSearch(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&keywordSearchProduct{}))
+	require.NoError(t, db.Create([]*keywordSearchProduct{
+		{ID: 1, Name: "Apple", Description: "a red fruit"},
+	}).Error)
+
+	ks := SQLiteFullTextSearch("keyword_search_products_fts")
+	if err := ks.Migrate(db, &keywordSearchProduct{}, "Name", "description"); err != nil {
+		if strings.Contains(err.Error(), "no such module: fts5") {
+			t.Skip("fts5 is not enabled")
+		}
+		require.NoError(t, err)
+	}
+	assert.Error(t, ks.Migrate(db, &keywordSearchProduct{}, "Secret"))
+
+	// the records are synced by the triggers
+	require.NoError(t, db.Create([]*keywordSearchProduct{
+		{ID: 2, Name: "Red Pepper", Description: "vegetable"},
+		{ID: 3, Name: "Cherry", Description: "small red fruit, red red"},
+		{ID: 4, Name: "Banana", Description: "yellow fruit"},
+	}).Error)
+	require.NoError(t, db.Model(&keywordSearchProduct{}).Where("id = ?", 4).Update("description", "a long yellow thing").Error)
+
+	op := DataOperator(db)
+	evCtx := &web.EventContext{R: httptest.NewRequest("GET", "/products", nil)}
+	search := func(keyword string, orderByRelevance bool) (ids []uint) {
+		result, err := op.Search(evCtx, &presets.SearchParams{
+			Model:            &keywordSearchProduct{},
+			KeywordColumns:   []string{"name"},
+			Keyword:          keyword,
+			KeywordSearch:    ks,
+			OrderByRelevance: orderByRelevance,
+			OrderBy:          []relay.Order{{Field: "ID", Direction: relay.OrderDirectionAsc}},
+			PerPage:          10,
+		})
+		require.NoError(t, err)
+		for _, p := range result.Nodes.([]*keywordSearchProduct) {
+			ids = append(ids, p.ID)
+		}
+		assert.Equal(t, len(ids), *result.TotalCount)
+		return
+	}
+
+	assert.Equal(t, []uint{1, 2, 3}, search("red", false))
+	// more occurrences in shorter documents are more relevant
+	assert.Equal(t, []uint{3, 2, 1}, search("red", true))
+	assert.Equal(t, []uint{3, 1}, search("RED fru", true))
+	assert.Equal(t, []uint{1, 3}, search("fruit", false))
+	assert.Empty(t, search(`"red" OR NEAR(`, false))
+	assert.Equal(t, []uint{1, 2, 3, 4}, search(" ", true))
+
+	require.NoError(t, db.Delete(&keywordSearchProduct{}, 3).Error)
+	assert.Equal(t, []uint{2, 1}, search("red", true))
+}
+
+func TestPostgresFullTextSearch(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+
+	ks := PostgresFullTextSearch("search_vector").Config("english")
+	tx, err := ks.Search(db.Model(&keywordSearchProduct{}), &keywordSearchProduct{}, "red fruit", true)
+	require.NoError(t, err)
+	stmt := tx.Find(&[]*keywordSearchProduct{}).Statement
+	assert.Equal(t,
+		`SELECT "keyword_search_products"."id","keyword_search_products"."name","keyword_search_products"."description" FROM "keyword_search_products" `+
+			`CROSS JOIN LATERAL (SELECT ts_rank("keyword_search_products"."search_vector", websearch_to_tsquery($1::regconfig, $2)) AS relevance) AS _keyword_search `+
+			`WHERE "keyword_search_products"."search_vector" @@ websearch_to_tsquery($3::regconfig, $4) ORDER BY "_keyword_search"."relevance" DESC`,
+		stmt.SQL.String())
+	assert.Equal(t, []any{"english", "red fruit", "english", "red fruit"}, stmt.Vars)
+
+	assert.Error(t, PostgresFullTextSearch("search_vector").Config("english'").Migrate(db, &keywordSearchProduct{}, "Name"))
+}
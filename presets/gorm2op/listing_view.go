@@ -0,0 +1,87 @@
+package gorm2op
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+type ListingViewStoreBuilder struct {
+	db *gorm.DB
+}
+
+var _ presets.ListingViewStore = (*ListingViewStoreBuilder)(nil)
+
+// ListingViewStore persists presets.ListingView into the listing_views table.
+func ListingViewStore(db *gorm.DB) *ListingViewStoreBuilder {
+	return &ListingViewStoreBuilder{db: db}
+}
+
+func (s *ListingViewStoreBuilder) AutoMigrate() *ListingViewStoreBuilder {
+	if err := s.db.AutoMigrate(&presets.ListingView{}); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *ListingViewStoreBuilder) List(ctx context.Context, modelName string, userID string, roles []string) (views []*presets.ListingView, err error) {
+	db := s.db.WithContext(ctx)
+	visible := db.Where("user_id = ?", userID).
+		Or("user_id = '' AND shared_role = ''")
+	if len(roles) > 0 {
+		visible = visible.Or("shared_role IN ?", roles)
+	}
+	err = db.Where("model_name = ?", modelName).
+		Where(visible).
+		Order("pinned DESC, id ASC").
+		Find(&views).Error
+	return
+}
+
+func (s *ListingViewStoreBuilder) Save(ctx context.Context, view *presets.ListingView) error {
+	db := s.db.WithContext(ctx)
+	if view.ID == 0 {
+		return db.Create(view).Error
+	}
+	result := db.Model(&presets.ListingView{}).
+		Where("id = ? AND model_name = ? AND user_id = ?", view.ID, view.ModelName, view.UserID).
+		Select("*").Omit("id", "created_at", "user_id", "model_name").
+		Updates(view)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return presets.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *ListingViewStoreBuilder) Delete(ctx context.Context, modelName string, userID string, id uint) error {
+	return s.db.WithContext(ctx).
+		Where("id = ? AND model_name = ? AND user_id = ?", id, modelName, userID).
+		Delete(&presets.ListingView{}).Error
+}
+
+func (s *ListingViewStoreBuilder) Seed(ctx context.Context, views ...*presets.ListingView) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, v := range views {
+			var existing presets.ListingView
+			err := tx.Where("model_name = ? AND user_id = ? AND name = ?", v.ModelName, v.UserID, v.Name).
+				First(&existing).Error
+			if err == nil {
+				*v = existing
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err = tx.Create(v).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+package gorm2op
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+func TestListingViewStore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	s := ListingViewStore(db).AutoMigrate()
+	ctx := context.Background()
+
+	mine := &presets.ListingView{UserID: "1", ModelName: "products", Name: "Mine", FilterQuery: "status=draft"}
+	require.NoError(t, s.Save(ctx, mine))
+	require.NoError(t, s.Save(ctx, &presets.ListingView{UserID: "2", ModelName: "products", Name: "Others"}))
+	require.NoError(t, s.Save(ctx, &presets.ListingView{UserID: "2", ModelName: "products", Name: "Shared", SharedRole: "editor"}))
+	require.NoError(t, s.Save(ctx, &presets.ListingView{UserID: "1", ModelName: "orders", Name: "Orders"}))
+
+	seeded := []*presets.ListingView{{ModelName: "products", Name: "Published", Pinned: true}}
+	require.NoError(t, s.Seed(ctx, seeded...))
+	require.NoError(t, s.Seed(ctx, &presets.ListingView{ModelName: "products", Name: "Published"}))
+
+	names := func(views []*presets.ListingView) (r []string) {
+		for _, v := range views {
+			r = append(r, v.Name)
+		}
+		return
+	}
+
+	views, err := s.List(ctx, "products", "1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Published", "Mine"}, names(views))
+	assert.True(t, views[0].Pinned)
+
+	views, err = s.List(ctx, "products", "1", []string{"editor"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Published", "Mine", "Shared"}, names(views))
+
+	mine.Name = "Drafts"
+	mine.DisplayColumns = []*presets.DisplayColumn{{Name: "Name", Visible: true}}
+	require.NoError(t, s.Save(ctx, mine))
+	views, err = s.List(ctx, "products", "1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Drafts", views[1].Name)
+	assert.Equal(t, mine.DisplayColumns, views[1].DisplayColumns)
+
+	// views of others can't be changed
+	other := *mine
+	other.UserID = "2"
+	assert.ErrorIs(t, s.Save(ctx, &other), presets.ErrRecordNotFound)
+	require.NoError(t, s.Delete(ctx, "products", "2", mine.ID))
+	require.NoError(t, s.Delete(ctx, "products", "1", mine.ID))
+	views, err = s.List(ctx, "products", "1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Published"}, names(views))
+}
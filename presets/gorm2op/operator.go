@@ -11,11 +11,13 @@ import (
 
 	"github.com/qor5/web/v3"
 	"github.com/qor5/x/v3/hook"
+	"github.com/qor5/x/v3/perm"
 	"github.com/samber/lo"
 	"github.com/theplant/relay"
 	"github.com/theplant/relay/cursor"
 	"github.com/theplant/relay/gormrelay"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/qor5/admin/v3/presets"
 )
@@ -50,31 +52,9 @@ type DataOperatorBuilder struct {
 }
 
 func (op *DataOperatorBuilder) Search(evCtx *web.EventContext, params *presets.SearchParams) (result *presets.SearchResult, err error) {
-	ilike := "ILIKE"
-	db := op.getDB(evCtx)
-	if db.Dialector.Name() == "sqlite" {
-		ilike = "LIKE"
-	}
-
-	wh := db.Model(params.Model)
-	if len(params.KeywordColumns) > 0 && params.Keyword != "" {
-		var segs []string
-		var args []interface{}
-		for _, c := range params.KeywordColumns {
-			segs = append(segs, fmt.Sprintf("%s %s ?", c, ilike))
-			kw := wildcardReg.ReplaceAllString(params.Keyword, `\$0`)
-			args = append(args, fmt.Sprintf("%%%s%%", kw))
-		}
-		wh = wh.Where(strings.Join(segs, " OR "), args...)
-	}
-
-	for _, cond := range params.SQLConditions {
-		wh = wh.Where(strings.ReplaceAll(cond.Query, " ILIKE ", " "+ilike+" "), cond.Args...)
-	}
-
-	dbHook, _ := evCtx.ContextValue(ctxKeyHook{}).(hook.Hook[*gorm.DB])
-	if dbHook != nil {
-		wh = dbHook(wh.Session(&gorm.Session{}))
+	wh, err := op.searchDB(evCtx, params)
+	if err != nil {
+		return nil, err
 	}
 
 	var p relay.Paginator[any]
@@ -145,6 +125,62 @@ func (op *DataOperatorBuilder) Search(evCtx *web.EventContext, params *presets.S
 	}, nil
 }
 
+// searchDB applies the conditions of the params, without the paging and ordering.
+func (op *DataOperatorBuilder) searchDB(evCtx *web.EventContext, params *presets.SearchParams) (wh *gorm.DB, err error) {
+	ilike := "ILIKE"
+	db := op.getDB(evCtx)
+	if db.Dialector.Name() == "sqlite" {
+		ilike = "LIKE"
+	}
+
+	wh = op.rowScoped(db, evCtx, params.Model).Model(params.Model)
+	if params.Trashed {
+		column, err := op.deletedAtColumn(db, params.Model)
+		if err != nil {
+			return nil, err
+		}
+		wh = wh.Unscoped().Where(fmt.Sprintf("%s IS NOT NULL", column))
+	}
+	if params.KeywordSearch != nil {
+		ks, ok := params.KeywordSearch.(KeywordSearch)
+		if !ok {
+			return nil, errors.Errorf("unsupported keyword search: %s", params.KeywordSearch.KeywordSearchName())
+		}
+		if params.Keyword != "" {
+			wh, err = ks.Search(wh, params.Model, params.Keyword, params.OrderByRelevance)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if len(params.KeywordColumns) > 0 && params.Keyword != "" {
+		var segs []string
+		var args []interface{}
+		for _, c := range params.KeywordColumns {
+			segs = append(segs, fmt.Sprintf("%s %s ?", c, ilike))
+			kw := wildcardReg.ReplaceAllString(params.Keyword, `\$0`)
+			args = append(args, fmt.Sprintf("%%%s%%", kw))
+		}
+		wh = wh.Where(strings.Join(segs, " OR "), args...)
+	}
+
+	for _, cond := range params.SQLConditions {
+		wh = wh.Where(strings.ReplaceAll(cond.Query, " ILIKE ", " "+ilike+" "), cond.Args...)
+	}
+
+	if params.Filter != nil && !params.FilterInSQLConditions {
+		wh, err = ApplyFilter(wh, params.Model, params.Filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dbHook, _ := evCtx.ContextValue(ctxKeyHook{}).(hook.Hook[*gorm.DB])
+	if dbHook != nil {
+		wh = dbHook(wh.Session(&gorm.Session{}))
+	}
+	return wh, nil
+}
+
 func (*DataOperatorBuilder) primarySluggerWhere(db *gorm.DB, obj interface{}, id string) *gorm.DB {
 	wh := db.Model(obj)
 
@@ -165,7 +201,7 @@ func (*DataOperatorBuilder) primarySluggerWhere(db *gorm.DB, obj interface{}, id
 }
 
 func (op *DataOperatorBuilder) Fetch(obj interface{}, id string, ctx *web.EventContext) (r interface{}, err error) {
-	db := op.getDB(ctx)
+	db := op.rowScoped(op.getDB(ctx), ctx, obj)
 	err = op.primarySluggerWhere(db, obj, id).First(obj).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -187,16 +223,129 @@ func (op *DataOperatorBuilder) getDB(ctx *web.EventContext) *gorm.DB {
 	return op.db
 }
 
+// rowScoped applies the presets.RowScopeFromContext of the model to the db.
+func (*DataOperatorBuilder) rowScoped(db *gorm.DB, ctx *web.EventContext, obj interface{}) *gorm.DB {
+	if ctx.R == nil {
+		return db
+	}
+	cond, ok := presets.RowScopeFromContext(ctx.R.Context(), obj)
+	if !ok {
+		return db
+	}
+	return db.Where(cond.Query, cond.Args...).Session(&gorm.Session{})
+}
+
 func (op *DataOperatorBuilder) Save(obj interface{}, id string, ctx *web.EventContext) (err error) {
 	db := op.getDB(ctx)
+	if ctx.R != nil {
+		if _, ok := presets.RowScopeFromContext(ctx.R.Context(), obj); ok {
+			return db.Transaction(func(tx *gorm.DB) error {
+				return op.saveInRowScope(tx, obj, id, ctx)
+			})
+		}
+	}
+	return op.save(db, obj, id, ctx)
+}
+
+// saveInRowScope only updates the records in the row scope, and the saved record must be in it.
+func (op *DataOperatorBuilder) saveInRowScope(tx *gorm.DB, obj interface{}, id string, ctx *web.EventContext) error {
+	scoped := op.rowScoped(tx, ctx, obj)
+	if id != "" {
+		var count int64
+		if err := op.primarySluggerWhere(scoped, obj, id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return presets.ErrRecordNotFound
+		}
+	}
+	if err := op.save(tx, obj, id, ctx); err != nil {
+		return err
+	}
+
+	wh := op.primarySluggerWhere(scoped, obj, id)
+	if id == "" {
+		// created
+		var err error
+		if wh, err = primaryKeyWhere(scoped, obj); err != nil {
+			return err
+		}
+	}
+	var count int64
+	if err := wh.Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return perm.PermissionDenied
+	}
+	return nil
+}
+
+// primaryKeyWhere matches the record by the primary keys of the object.
+func primaryKeyWhere(db *gorm.DB, obj interface{}) (*gorm.DB, error) {
+	s, err := parseSchema(db, obj)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.PrimaryFields) == 0 {
+		return nil, errors.Errorf("%s has no primary field", s.Name)
+	}
+	wh := db.Model(obj)
+	rv := reflect.ValueOf(obj)
+	for _, f := range s.PrimaryFields {
+		v, _ := f.ValueOf(db.Statement.Context, rv)
+		wh = wh.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: f.DBName}, Value: v})
+	}
+	return wh, nil
+}
+
+func (op *DataOperatorBuilder) save(db *gorm.DB, obj interface{}, id string, ctx *web.EventContext) (err error) {
 	if id == "" {
 		err = db.Create(obj).Error
 		return
 	}
+	if ctx.R != nil {
+		if lock, ok := presets.OptimisticLockFromContext(ctx.R.Context()); ok {
+			err = op.updateWithLock(db, obj, id, lock)
+			return
+		}
+	}
 	err = op.saveOrUpdate(db, obj, id)
 	return
 }
 
+// updateWithLock only updates the record if the lock field still has the fetched value,
+// integer lock fields are increased, time ones are refreshed by gorm's autoUpdateTime.
+func (op *DataOperatorBuilder) updateWithLock(db *gorm.DB, obj interface{}, id string, lock *presets.OptimisticLock) error {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(obj); err != nil {
+		return err
+	}
+	field := stmt.Schema.LookUpField(lock.Field)
+	if field == nil {
+		return errors.Errorf("optimistic lock field %s not found", lock.Field)
+	}
+
+	fv := reflect.Indirect(reflect.ValueOf(obj)).FieldByIndex(field.StructField.Index)
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fv.SetInt(reflect.ValueOf(lock.Value).Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fv.SetUint(reflect.ValueOf(lock.Value).Uint() + 1)
+	}
+
+	result := op.primarySluggerWhere(db, obj, id).
+		Where(fmt.Sprintf("%s = ?", stmt.Quote(field.DBName)), lock.Value).
+		Select("*").Updates(obj)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return presets.ErrOptimisticLockConflict
+	}
+	return nil
+}
+
 func (op *DataOperatorBuilder) saveOrUpdate(db *gorm.DB, obj interface{}, id string) (err error) {
 	var count int64
 	if op.primarySluggerWhere(db, obj, id).Count(&count).Error != nil {
@@ -208,9 +357,86 @@ func (op *DataOperatorBuilder) saveOrUpdate(db *gorm.DB, obj interface{}, id str
 	return op.primarySluggerWhere(db, obj, id).Save(obj).Error
 }
 
+// Delete reports presets.ErrRecordNotFound for the records out of the row scope.
 func (op *DataOperatorBuilder) Delete(obj interface{}, id string, ctx *web.EventContext) (err error) {
 	db := op.getDB(ctx)
+	scoped := op.rowScoped(db, ctx, obj)
 
-	err = op.primarySluggerWhere(db, obj, id).Delete(obj).Error
-	return
+	result := op.primarySluggerWhere(scoped, obj, id).Delete(obj)
+	if result.Error != nil {
+		return result.Error
+	}
+	if scoped != db && result.RowsAffected == 0 {
+		return presets.ErrRecordNotFound
+	}
+	return nil
+}
+
+var _ presets.TrashDataOperator = (*DataOperatorBuilder)(nil)
+var _ presets.TransactionDataOperator = (*DataOperatorBuilder)(nil)
+var _ presets.AggregateDataOperator = (*DataOperatorBuilder)(nil)
+
+// deletedAtColumn returns the column of the gorm.DeletedAt field of the model.
+func (*DataOperatorBuilder) deletedAtColumn(db *gorm.DB, obj interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(obj); err != nil {
+		return "", err
+	}
+	for _, f := range stmt.Schema.Fields {
+		if f.FieldType == reflect.TypeOf(gorm.DeletedAt{}) {
+			return f.DBName, nil
+		}
+	}
+	return "", errors.Errorf("%s has no gorm.DeletedAt field", stmt.Schema.Name)
+}
+
+func (op *DataOperatorBuilder) Restore(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	db := op.rowScoped(op.getDB(ctx), ctx, obj)
+	column, err := op.deletedAtColumn(db, obj)
+	if err != nil {
+		return
+	}
+	result := op.primarySluggerWhere(db.Unscoped(), obj, id).
+		Where(fmt.Sprintf("%s IS NOT NULL", column)).
+		Update(column, nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return presets.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge only deletes the record permanently if it's soft deleted already.
+func (op *DataOperatorBuilder) Purge(obj interface{}, id string, ctx *web.EventContext) (err error) {
+	db := op.getDB(ctx)
+	scoped := op.rowScoped(db, ctx, obj)
+	column, err := op.deletedAtColumn(db, obj)
+	if err != nil {
+		return
+	}
+	result := op.primarySluggerWhere(scoped.Unscoped(), obj, id).
+		Where(fmt.Sprintf("%s IS NOT NULL", column)).
+		Delete(obj)
+	if result.Error != nil {
+		return result.Error
+	}
+	if scoped != db && result.RowsAffected == 0 {
+		return presets.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Transaction runs f with the transaction as the CtxKeyDB of the context, so the fetches and saves of f are in it.
+func (op *DataOperatorBuilder) Transaction(ctx *web.EventContext, f func(txCtx *web.EventContext) error) (err error) {
+	return op.getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		txCtx := &web.EventContext{
+			R:        ctx.R.WithContext(context.WithValue(ctx.R.Context(), CtxKeyDB{}, tx)),
+			W:        ctx.W,
+			Injector: ctx.Injector,
+			Flash:    ctx.Flash,
+		}
+		return f(txCtx)
+	})
 }
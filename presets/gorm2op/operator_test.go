@@ -0,0 +1,249 @@
+package gorm2op
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/perm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+func TestDataOperatorSaveWithOptimisticLock(t *testing.T) {
+	type Product struct {
+		ID      uint
+		Name    string
+		Version int
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}))
+	require.NoError(t, db.Create(&Product{ID: 1, Name: "Apple", Version: 1}).Error)
+
+	op := DataOperator(db)
+	save := func(p *Product, version int) error {
+		r := httptest.NewRequest("POST", "/products", nil)
+		r = r.WithContext(presets.WithOptimisticLock(r.Context(), &presets.OptimisticLock{Field: "Version", Value: version}))
+		return op.Save(p, "1", &web.EventContext{R: r})
+	}
+
+	mine := &Product{ID: 1, Name: "Banana", Version: 1}
+	require.NoError(t, save(mine, 1))
+	assert.Equal(t, 2, mine.Version)
+
+	theirs := &Product{ID: 1, Name: "Cherry", Version: 1}
+	assert.ErrorIs(t, save(theirs, 1), presets.ErrOptimisticLockConflict)
+
+	var saved Product
+	require.NoError(t, db.First(&saved, 1).Error)
+	assert.Equal(t, "Banana", saved.Name)
+	assert.Equal(t, 2, saved.Version)
+
+	// saves without a request, e.g. from jobs, skip the lock
+	plain := &Product{ID: 1, Name: "Durian", Version: 2}
+	require.NoError(t, op.Save(plain, "1", &web.EventContext{}))
+	require.NoError(t, db.First(&saved, 1).Error)
+	assert.Equal(t, "Durian", saved.Name)
+}
+
+func TestDataOperatorTrash(t *testing.T) {
+	type Product struct {
+		gorm.Model
+		Name string
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}))
+	for _, name := range []string{"Apple", "Banana", "Cherry"} {
+		require.NoError(t, db.Create(&Product{Name: name}).Error)
+	}
+
+	op := DataOperator(db)
+	evCtx := &web.EventContext{R: httptest.NewRequest("GET", "/products", nil)}
+	names := func(trashed bool) (r []string) {
+		result, err := op.Search(evCtx, &presets.SearchParams{Model: &Product{}, Trashed: trashed})
+		require.NoError(t, err)
+		for _, p := range result.Nodes.([]*Product) {
+			r = append(r, p.Name)
+		}
+		return
+	}
+
+	require.NoError(t, op.Delete(&Product{}, "1", evCtx))
+	require.NoError(t, op.Delete(&Product{}, "2", evCtx))
+	assert.Equal(t, []string{"Cherry"}, names(false))
+	assert.Equal(t, []string{"Apple", "Banana"}, names(true))
+
+	require.NoError(t, op.Restore(&Product{}, "1", evCtx))
+	assert.ErrorIs(t, op.Restore(&Product{}, "3", evCtx), presets.ErrRecordNotFound)
+	assert.Equal(t, []string{"Apple", "Cherry"}, names(false))
+
+	// only soft deleted records could be purged
+	require.NoError(t, op.Purge(&Product{}, "3", evCtx))
+	require.NoError(t, op.Purge(&Product{}, "2", evCtx))
+	assert.Empty(t, names(true))
+	var count int64
+	require.NoError(t, db.Unscoped().Model(&Product{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestDataOperatorRowScope(t *testing.T) {
+	type Product struct {
+		gorm.Model
+		Name    string
+		BrandID uint
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}))
+	for i, name := range []string{"Apple", "Banana", "Cherry"} {
+		require.NoError(t, db.Create(&Product{Name: name, BrandID: uint(i%2 + 1)}).Error)
+	}
+
+	op := DataOperator(db)
+	r := httptest.NewRequest("GET", "/products", nil)
+	r = r.WithContext(presets.WithRowScope(r.Context(), &Product{}, &presets.SQLCondition{Query: "brand_id = ?", Args: []any{1}}))
+	evCtx := &web.EventContext{R: r}
+
+	result, err := op.Search(evCtx, &presets.SearchParams{Model: &Product{}})
+	require.NoError(t, err)
+	assert.Len(t, result.Nodes, 2)
+
+	_, err = op.Fetch(&Product{}, "2", evCtx)
+	assert.ErrorIs(t, err, presets.ErrRecordNotFound)
+	obj, err := op.Fetch(&Product{}, "1", evCtx)
+	require.NoError(t, err)
+
+	p := obj.(*Product)
+	p.Name = "Apricot"
+	require.NoError(t, op.Save(p, "1", evCtx))
+	assert.ErrorIs(t, op.Save(&Product{Model: gorm.Model{ID: 2}, Name: "Blueberry", BrandID: 1}, "2", evCtx), presets.ErrRecordNotFound)
+	// moving the record out of the scope is rolled back
+	p.BrandID = 2
+	assert.ErrorIs(t, op.Save(p, "1", evCtx), perm.PermissionDenied)
+	assert.ErrorIs(t, op.Save(&Product{Name: "Durian", BrandID: 2}, "", evCtx), perm.PermissionDenied)
+	require.NoError(t, op.Save(&Product{Name: "Elderberry", BrandID: 1}, "", evCtx))
+
+	var names []string
+	require.NoError(t, db.Model(&Product{}).Order("id").Pluck("name", &names).Error)
+	assert.Equal(t, []string{"Apricot", "Banana", "Cherry", "Elderberry"}, names)
+
+	assert.ErrorIs(t, op.Delete(&Product{}, "2", evCtx), presets.ErrRecordNotFound)
+	require.NoError(t, op.Delete(&Product{}, "3", evCtx))
+	assert.ErrorIs(t, op.Restore(&Product{}, "2", evCtx), presets.ErrRecordNotFound)
+	assert.ErrorIs(t, op.Purge(&Product{}, "2", evCtx), presets.ErrRecordNotFound)
+	require.NoError(t, op.Purge(&Product{}, "3", evCtx))
+
+	// the scope only applies to the model
+	type Brand struct {
+		ID   uint
+		Name string
+	}
+	require.NoError(t, db.AutoMigrate(&Brand{}))
+	require.NoError(t, db.Create(&Brand{ID: 2, Name: "Acme"}).Error)
+	_, err = op.Fetch(&Brand{}, "2", evCtx)
+	require.NoError(t, err)
+}
+
+func TestDataOperatorTransaction(t *testing.T) {
+	type Product struct {
+		ID   uint
+		Name string
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}))
+	require.NoError(t, db.Create(&Product{ID: 1, Name: "Apple"}).Error)
+
+	op := DataOperator(db)
+	ctx := &web.EventContext{R: httptest.NewRequest("POST", "/products", nil)}
+	rename := func(name string, fail error) error {
+		return op.Transaction(ctx, func(txCtx *web.EventContext) error {
+			obj, err := op.Fetch(&Product{}, "1", txCtx)
+			if err != nil {
+				return err
+			}
+			obj.(*Product).Name = name
+			if err := op.Save(obj, "1", txCtx); err != nil {
+				return err
+			}
+			fetched, err := op.Fetch(&Product{}, "1", txCtx)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, name, fetched.(*Product).Name)
+			return fail
+		})
+	}
+
+	failed := errors.New("failed")
+	assert.ErrorIs(t, rename("Banana", failed), failed)
+	obj, err := op.Fetch(&Product{}, "1", ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Apple", obj.(*Product).Name)
+
+	require.NoError(t, rename("Cherry", nil))
+	obj, err = op.Fetch(&Product{}, "1", ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Cherry", obj.(*Product).Name)
+}
+
+func TestDataOperatorAggregate(t *testing.T) {
+	type Order struct {
+		gorm.Model
+		Customer string
+		Quantity int
+		Price    float64
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Order{}))
+	for _, o := range []*Order{
+		{Customer: "Alice", Quantity: 1, Price: 10},
+		{Customer: "Bob", Quantity: 2, Price: 20},
+		{Customer: "Alice", Quantity: 3, Price: 30},
+		{Customer: "Carol", Quantity: 4, Price: 40},
+	} {
+		require.NoError(t, db.Create(o).Error)
+	}
+	require.NoError(t, db.Delete(&Order{}, 4).Error)
+
+	op := DataOperator(db)
+	evCtx := &web.EventContext{R: httptest.NewRequest("GET", "/orders", nil)}
+	aggregates := []*presets.Aggregate{
+		{Field: "Quantity", Func: presets.AggregateSum},
+		{Field: "Price", Func: presets.AggregateAvg},
+		{Field: "ID", Func: presets.AggregateMax},
+		{Field: "Customer", Func: presets.AggregateCountDistinct},
+		{Field: "Total", Func: presets.AggregateSQL, SQL: "SUM(quantity * price)"},
+	}
+
+	r, err := op.Aggregate(evCtx, &presets.SearchParams{Model: &Order{}, PerPage: 1, Page: 2}, aggregates)
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, r["Quantity"])
+	assert.EqualValues(t, 20, r["Price"])
+	assert.EqualValues(t, 3, r["ID"])
+	assert.EqualValues(t, 2, r["Customer"])
+	assert.EqualValues(t, 140, r["Total"])
+
+	r, err = op.Aggregate(evCtx, &presets.SearchParams{
+		Model:         &Order{},
+		SQLConditions: []*presets.SQLCondition{{Query: "customer = ?", Args: []interface{}{"Alice"}}},
+	}, aggregates[:1])
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, r["Quantity"])
+
+	_, err = op.Aggregate(evCtx, &presets.SearchParams{Model: &Order{}}, []*presets.Aggregate{{Field: "Unknown", Func: presets.AggregateSum}})
+	assert.ErrorContains(t, err, "field not found")
+}
@@ -0,0 +1,241 @@
+package presets
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/qor5/web/v3"
+	"github.com/samber/lo"
+	"github.com/sunfmin/reflectutils"
+	h "github.com/theplant/htmlgo"
+	"go.uber.org/zap"
+)
+
+type AggregateFunc string
+
+const (
+	AggregateSum           AggregateFunc = "sum"
+	AggregateAvg           AggregateFunc = "avg"
+	AggregateMin           AggregateFunc = "min"
+	AggregateMax           AggregateFunc = "max"
+	AggregateCountDistinct AggregateFunc = "count_distinct"
+	// AggregateSQL computes the SQL expression of the Aggregate, like "SUM(price * quantity)".
+	AggregateSQL AggregateFunc = "sql"
+)
+
+// Aggregate is computed over all the records matching the SearchParams, Field is the name of the listing field.
+type Aggregate struct {
+	Field string
+	Func  AggregateFunc
+	SQL   string
+}
+
+// AggregateDataOperator is implemented by data operators which could compute aggregates, see gorm2op.DataOperatorBuilder.
+type AggregateDataOperator interface {
+	// Aggregate returns the values by the Field of the aggregates, the paging and ordering of the params are ignored.
+	Aggregate(ctx *web.EventContext, params *SearchParams, aggregates []*Aggregate) (r map[string]any, err error)
+}
+
+type AggregatorFunc func(ctx *web.EventContext, params *SearchParams, aggregates []*Aggregate) (r map[string]any, err error)
+
+type AggregateComponentFunc func(value any, ctx *web.EventContext) h.HTMLComponent
+
+type ListingAggregateBuilder struct {
+	Aggregate
+	label    string
+	compFunc AggregateComponentFunc
+}
+
+// Aggregate shows the aggregate of the field in the footer row of the listing table, it is computed over all the
+// filtered records rather than the current page. The value is set to the field of a new model and rendered with the
+// listing field, so it is formatted the same as the cells, unless the ComponentFunc is set.
+// The aggregates are computed by the AggregatorFunc which defaults to the AggregateDataOperator.
+func (b *ListingBuilder) Aggregate(field string, fn AggregateFunc) (r *ListingAggregateBuilder) {
+	if b.aggregator == nil {
+		if op, ok := b.mb.p.dataOperator.(AggregateDataOperator); ok {
+			b.aggregator = op.Aggregate
+		}
+	}
+	for _, a := range b.aggregates {
+		if a.Field == field {
+			a.Func = fn
+			return a
+		}
+	}
+	r = &ListingAggregateBuilder{Aggregate: Aggregate{Field: field, Func: fn}}
+	b.aggregates = append(b.aggregates, r)
+	return
+}
+
+func (b *ListingBuilder) AggregatorFunc(v AggregatorFunc) (r *ListingBuilder) {
+	b.aggregator = v
+	return b
+}
+
+// SQL is the expression of AggregateSQL.
+func (b *ListingAggregateBuilder) SQL(v string) (r *ListingAggregateBuilder) {
+	b.Func = AggregateSQL
+	b.Aggregate.SQL = v
+	return b
+}
+
+// Label is shown above the value, defaults to the name of the AggregateFunc.
+func (b *ListingAggregateBuilder) Label(v string) (r *ListingAggregateBuilder) {
+	b.label = v
+	return b
+}
+
+func (b *ListingAggregateBuilder) ComponentFunc(v AggregateComponentFunc) (r *ListingAggregateBuilder) {
+	b.compFunc = v
+	return b
+}
+
+func (b *ListingAggregateBuilder) getLabel(msgr *Messages) string {
+	if b.label != "" {
+		return b.label
+	}
+	switch b.Func {
+	case AggregateSum:
+		return msgr.AggregateSum
+	case AggregateAvg:
+		return msgr.AggregateAvg
+	case AggregateMin:
+		return msgr.AggregateMin
+	case AggregateMax:
+		return msgr.AggregateMax
+	case AggregateCountDistinct:
+		return msgr.AggregateCountDistinct
+	}
+	return ""
+}
+
+// aggregateFooter returns the footer row aligned with the columns of the data table, or nil if there are no aggregates.
+func (c *ListingCompo) aggregateFooter(ctx context.Context, searchParams *SearchParams, nodes any, columns []*Column, hasRowMenuHead bool) h.HTMLComponent {
+	if len(c.lb.aggregates) == 0 || c.lb.aggregator == nil {
+		return nil
+	}
+	evCtx, msgr := c.MustGetEventContext(ctx)
+
+	params := *searchParams
+	params.RelayPagination = nil
+	params.RelayPaginateRequest = nil
+	params.OrderByRelevance = false
+	values, err := c.lb.aggregator(evCtx, &params, lo.Map(c.lb.aggregates, func(a *ListingAggregateBuilder, _ int) *Aggregate {
+		return &a.Aggregate
+	}))
+	if err != nil {
+		c.lb.mb.p.logger.Warn("aggregate listing", zap.Error(err))
+		return nil
+	}
+
+	var cells []h.HTMLComponent
+	if len(c.lb.bulkActions) > 0 || c.inTrash(evCtx) {
+		cells = append(cells, h.Td())
+	}
+	for _, col := range columns {
+		if !col.Visible {
+			continue
+		}
+		a, ok := lo.Find(c.lb.aggregates, func(a *ListingAggregateBuilder) bool { return a.Field == col.Name })
+		if !ok {
+			cells = append(cells, h.Td())
+			continue
+		}
+		cells = append(cells, c.aggregateCell(evCtx, msgr, a, values[a.Field]))
+	}
+	if hasRowMenuHead || c.hasRowMenus(ctx, nodes) {
+		cells = append(cells, h.Td())
+	}
+	return h.Tr(cells...).Class("font-weight-medium bg-grey-lighten-5")
+}
+
+func (c *ListingCompo) aggregateCell(evCtx *web.EventContext, msgr *Messages, a *ListingAggregateBuilder, value any) h.HTMLComponent {
+	caption := h.Div(h.Text(a.getLabel(msgr))).Class("text-caption text-medium-emphasis")
+	var comp h.HTMLComponent
+	switch {
+	case value == nil:
+	case a.compFunc != nil:
+		comp = a.compFunc(value, evCtx)
+	default:
+		obj := c.lb.mb.NewModel()
+		if setAggregateValue(obj, a.Field, value) {
+			comp = c.lb.cellComponentFunc(c.lb.getFieldOrDefault(a.Field))(obj, a.Field, evCtx)
+		} else {
+			comp = h.Text(fmt.Sprint(value))
+		}
+	}
+	if td, ok := comp.(*h.HTMLTagBuilder); ok {
+		return td.PrependChildren(caption)
+	}
+	return h.Td(caption, comp)
+}
+
+// hasRowMenus reports whether the data table has the row menu column the same as vx.DataTableBuilder.
+func (c *ListingCompo) hasRowMenus(ctx context.Context, nodes any) (r bool) {
+	evCtx, _ := c.MustGetEventContext(ctx)
+	funcs := c.rowMenuItemFuncs(ctx)
+	reflectutils.ForEach(nodes, func(obj any) {
+		if r {
+			return
+		}
+		id := ObjectID(obj)
+		for _, f := range funcs {
+			if f(obj, id, evCtx) != nil {
+				r = true
+				return
+			}
+		}
+	})
+	return
+}
+
+// setAggregateValue sets the value returned by the database to the field, numbers are converted to the type of the field.
+func setAggregateValue(obj any, name string, value any) bool {
+	fv := reflect.Indirect(reflect.ValueOf(obj)).FieldByName(name)
+	if !fv.IsValid() || !fv.CanSet() {
+		return false
+	}
+	t := fv.Type()
+	isPtr := t.Kind() == reflect.Ptr
+	if isPtr {
+		t = t.Elem()
+	}
+
+	v := reflect.ValueOf(value)
+	if b, ok := value.([]byte); ok {
+		v = reflect.ValueOf(string(b))
+	}
+	if v.Kind() == reflect.String && t.Kind() != reflect.String {
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return false
+		}
+		v = reflect.ValueOf(f)
+	}
+	if !v.CanConvert(t) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.CanFloat() && v.Float() != float64(int64(v.Float())) {
+			// the average of integers is shown as it is
+			return false
+		}
+	case reflect.String:
+		if v.Kind() != reflect.String {
+			return false
+		}
+	}
+	v = v.Convert(t)
+
+	if isPtr {
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		v = p
+	}
+	fv.Set(v)
+	return true
+}
@@ -0,0 +1,77 @@
+package presets
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	h "github.com/theplant/htmlgo"
+)
+
+func TestSetAggregateValue(t *testing.T) {
+	type Order struct {
+		Quantity int
+		Price    *float64
+		Customer string
+	}
+
+	o := &Order{}
+	assert.True(t, setAggregateValue(o, "Quantity", int64(6)))
+	assert.Equal(t, 6, o.Quantity)
+	assert.True(t, setAggregateValue(o, "Quantity", []byte("7")))
+	assert.Equal(t, 7, o.Quantity)
+	assert.False(t, setAggregateValue(o, "Quantity", 2.5))
+	assert.True(t, setAggregateValue(o, "Price", "12.5"))
+	assert.Equal(t, 12.5, *o.Price)
+	assert.True(t, setAggregateValue(o, "Customer", "Alice"))
+	assert.False(t, setAggregateValue(o, "Customer", int64(2)))
+	assert.False(t, setAggregateValue(o, "Unknown", int64(2)))
+}
+
+func TestListingCompoAggregateFooter(t *testing.T) {
+	type Order struct {
+		ID       uint
+		Customer string
+		Quantity int
+	}
+
+	var params *SearchParams
+	pb := New()
+	mb := pb.Model(&Order{})
+	lb := mb.Listing("Customer", "Quantity").
+		AggregatorFunc(func(ctx *web.EventContext, p *SearchParams, aggregates []*Aggregate) (map[string]any, error) {
+			params = p
+			return map[string]any{"Customer": int64(2), "Quantity": int64(42)}, nil
+		})
+	lb.Aggregate("Quantity", AggregateSum)
+	lb.Aggregate("Customer", AggregateCountDistinct).ComponentFunc(func(value any, ctx *web.EventContext) h.HTMLComponent {
+		return h.Td(h.Textf("%d customers", value))
+	})
+	lb.Field("Quantity").ComponentFunc(func(obj interface{}, field *FieldContext, ctx *web.EventContext) h.HTMLComponent {
+		return h.Td(h.Textf("%d pcs", obj.(*Order).Quantity))
+	})
+
+	r := httptest.NewRequest("GET", "/orders", nil)
+	evCtx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+	ctx := web.WrapEventContext(r.Context(), evCtx)
+	c := &ListingCompo{lb: lb}
+	columns := []*Column{{DisplayColumn: &DisplayColumn{Name: "Customer", Visible: true}}, {DisplayColumn: &DisplayColumn{Name: "Quantity", Visible: true}}}
+
+	footer := c.aggregateFooter(ctx, &SearchParams{Model: &Order{}, PerPage: 10, Page: 2, OrderByRelevance: true}, []*Order{}, columns, true)
+	require.NotNil(t, footer)
+	require.NotNil(t, params)
+	assert.False(t, params.OrderByRelevance)
+
+	html, err := footer.MarshalHTML(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "2 customers")
+	assert.Contains(t, string(html), "42 pcs")
+	assert.Contains(t, string(html), "Sum")
+	assert.Equal(t, 3, strings.Count(string(html), "<td"))
+
+	lb.aggregates = nil
+	assert.Nil(t, c.aggregateFooter(ctx, &SearchParams{Model: &Order{}}, []*Order{}, columns, true))
+}
@@ -48,6 +48,7 @@ type ListingBuilder struct {
 	rowMenu                *RowMenuBuilder
 	filterDataFunc         FilterDataFunc
 	filterTabsFunc         FilterTabsFunc
+	views                  *ListingViewsBuilder
 	filterNotificationFunc FilterNotificationFunc
 	newBtnFunc             ComponentFunc
 	pageFunc               web.PageFunc
@@ -57,6 +58,7 @@ type ListingBuilder struct {
 	tableProcessor         TableProcessor
 	Searcher               SearchFunc
 	searchColumns          []string
+	inlineEditFields       []string
 	titleFunc              func(evCtx *web.EventContext, style ListingStyle, defaultTitle string) (title string, titleCompo h.HTMLComponent, err error)
 
 	// perPage is the number of records per page.
@@ -83,7 +85,13 @@ type ListingBuilder struct {
 	dialogWidth       string
 	dialogHeight      string
 	keywordSearchOff  bool
+	keywordSearch     KeywordSearch
 	columnsProcessor  ColumnsProcessor
+	exporting         *ListingExportBuilder
+	aggregates        []*ListingAggregateBuilder
+	aggregator        AggregatorFunc
+	kanban            *KanbanBuilder
+	calendar          *CalendarBuilder
 
 	FieldsBuilder
 
@@ -174,7 +182,7 @@ func (b *ListingBuilder) DisablePagination(v bool) (r *ListingBuilder) {
 }
 
 func (b *ListingBuilder) SearchFunc(v SearchFunc) (r *ListingBuilder) {
-	b.Searcher = v
+	b.Searcher = b.mb.rowScopedSearchFunc(v)
 	return b
 }
 
@@ -194,6 +202,13 @@ func (b *ListingBuilder) KeywordSearchOff(v bool) (r *ListingBuilder) {
 	return b
 }
 
+// KeywordSearch replaces the default ILIKE search on the search columns, like the full-text searches of gorm2op.
+// The results are ordered by relevance if no column is ordered and the listing has no relay pagination.
+func (b *ListingBuilder) KeywordSearch(v KeywordSearch) (r *ListingBuilder) {
+	b.keywordSearch = v
+	return b
+}
+
 func (b *ListingBuilder) WrapColumns(w func(in ColumnsProcessor) ColumnsProcessor) (r *ListingBuilder) {
 	if b.columnsProcessor == nil {
 		b.columnsProcessor = w(func(_ *web.EventContext, columns []*Column) ([]*Column, error) {
@@ -231,6 +246,10 @@ func (b *ListingBuilder) RelayPagination(v RelayPagination) (r *ListingBuilder)
 	return b
 }
 
+func (b *ListingBuilder) GetRelayPagination() RelayPagination {
+	return b.relayPagination
+}
+
 func (b *ListingBuilder) FilterNotificationFunc(v FilterNotificationFunc) (r *ListingBuilder) {
 	b.filterNotificationFunc = v
 	return b
@@ -281,6 +300,10 @@ func (b *ListingBuilder) DisableModelListeners(v bool) (r *ListingBuilder) {
 	return b
 }
 
+func (b *ListingBuilder) GetModelBuilder() *ModelBuilder {
+	return b.mb
+}
+
 func (b *ListingBuilder) GetPageFunc() web.PageFunc {
 	if b.pageFunc != nil {
 		return b.pageFunc
@@ -513,3 +536,60 @@ func (b *ListingBuilder) defaultNewBtnFunc(ctx *web.EventContext) h.HTMLComponen
 		Theme("light").Class("ml-2").
 		Attr("@click", onClick.Go())
 }
+
+func (b *ListingBuilder) orderBy(colOrderBys []ColOrderBy, orderableFieldMap map[string]bool) []relay.Order {
+	var orderBy []relay.Order
+	for _, ob := range colOrderBys {
+		if orderableFieldMap[ob.FieldName] {
+			direction := relay.OrderDirectionAsc
+			if ob.OrderBy == OrderByDESC {
+				direction = relay.OrderDirectionDesc
+			}
+			orderBy = append(orderBy, relay.Order{
+				Field:     ob.FieldName,
+				Direction: direction,
+			})
+		}
+	}
+	var primaryOrderBy []relay.Order
+	if len(b.defaultOrderBy) > 0 {
+		primaryOrderBy = b.defaultOrderBy
+	} else {
+		// fallback to deprecated defaultOrderBys
+		primaryOrderBy = relay.OrderByFromOrderBys(b.defaultOrderBys)
+	}
+	if len(primaryOrderBy) == 0 && b.mb.primaryField != "" {
+		primaryOrderBy = []relay.Order{{Field: b.mb.primaryField, Direction: relay.OrderDirectionDesc}}
+	}
+	orderBy = relay.AppendPrimaryOrderBy(orderBy, primaryOrderBy...)
+	return orderBy
+}
+
+// NewSearchParams builds the SearchParams of the listing for callers without the listing compo,
+// the conditions, search columns, orderable fields and default order of the listing are applied.
+func (b *ListingBuilder) NewSearchParams(evCtx *web.EventContext, keyword string, filter *Filter, colOrderBys []ColOrderBy) *SearchParams {
+	params := &SearchParams{
+		Model:         b.mb.NewModel(),
+		PageURL:       evCtx.R.URL,
+		SQLConditions: b.conditions,
+		Filter:        filter,
+		OrderBy:       b.orderBy(colOrderBys, b.orderableFieldMap()),
+	}
+	b.setKeyword(params, keyword, colOrderBys)
+	return params
+}
+
+func (b *ListingBuilder) setKeyword(params *SearchParams, keyword string, colOrderBys []ColOrderBy) {
+	if b.keywordSearchOff {
+		return
+	}
+	params.KeywordColumns = b.searchColumns
+	params.Keyword = keyword
+	params.KeywordSearch = b.keywordSearch
+	if b.keywordSearch != nil && keyword != "" && b.relayPagination == nil {
+		orderableFieldMap := b.orderableFieldMap()
+		params.OrderByRelevance = !lo.SomeBy(colOrderBys, func(ob ColOrderBy) bool {
+			return orderableFieldMap[ob.FieldName]
+		})
+	}
+}
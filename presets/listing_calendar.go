@@ -0,0 +1,428 @@
+package presets
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/web/v3/stateful"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/samber/lo"
+	"github.com/sunfmin/reflectutils"
+	h "github.com/theplant/htmlgo"
+)
+
+type CalendarMode string
+
+const (
+	CalendarModeMonth CalendarMode = "month"
+	CalendarModeWeek  CalendarMode = "week"
+	CalendarModeDay   CalendarMode = "day"
+
+	calendarDateLayout = "2006-01-02"
+)
+
+type CalendarTitleFunc func(evCtx *web.EventContext, obj any) string
+
+type CalendarBuilder struct {
+	lb          *ListingBuilder
+	startField  string
+	startColumn string
+	endField    string
+	endColumn   string
+	mode        CalendarMode
+	weekStart   time.Weekday
+	location    *time.Location
+	titleFunc   CalendarTitleFunc
+}
+
+// Calendar lists the records on a month, week or day calendar by the time field, the records between the start and
+// the end are searched with the listing filters. The records could be dragged to other days to be rescheduled, their
+// time of the day and duration are kept, they are saved with the SetterFunc, the ValidateFunc and the SaveFunc of the editing.
+// The fields could be time.Time or *time.Time, like the ScheduledStartAt and ScheduledEndAt of publish.Schedule.
+func (b *ListingBuilder) Calendar(startField string) (r *CalendarBuilder) {
+	if b.calendar == nil {
+		b.calendar = &CalendarBuilder{lb: b, mode: CalendarModeMonth, weekStart: time.Sunday, location: time.Local}
+		b.dataTableFunc = b.calendar.dataTable
+	}
+	r = b.calendar
+	r.startField = startField
+	r.startColumn = strcase.ToSnake(startField)
+	return
+}
+
+func (b *ListingBuilder) GetCalendar() *CalendarBuilder {
+	return b.calendar
+}
+
+// EndField is optional, the records are shown on every day from the start to the end.
+func (b *CalendarBuilder) EndField(v string) (r *CalendarBuilder) {
+	b.endField = v
+	b.endColumn = strcase.ToSnake(v)
+	return b
+}
+
+// Columns are the database columns of the start and end fields, default to the snake case of the fields.
+func (b *CalendarBuilder) Columns(start string, end string) (r *CalendarBuilder) {
+	b.startColumn = start
+	b.endColumn = end
+	return b
+}
+
+func (b *CalendarBuilder) DefaultMode(v CalendarMode) (r *CalendarBuilder) {
+	b.mode = v
+	return b
+}
+
+func (b *CalendarBuilder) WeekStart(v time.Weekday) (r *CalendarBuilder) {
+	b.weekStart = v
+	return b
+}
+
+func (b *CalendarBuilder) Location(v *time.Location) (r *CalendarBuilder) {
+	b.location = v
+	return b
+}
+
+// TitleFunc returns the text of the records on the calendar, defaults to the page title.
+func (b *CalendarBuilder) TitleFunc(v CalendarTitleFunc) (r *CalendarBuilder) {
+	b.titleFunc = v
+	return b
+}
+
+// view returns the mode, the anchor date and the days shown of the listing.
+func (b *CalendarBuilder) view(c *ListingCompo) (mode CalendarMode, date time.Time, days []time.Time) {
+	mode = CalendarMode(c.CalendarMode)
+	if !lo.Contains([]CalendarMode{CalendarModeMonth, CalendarModeWeek, CalendarModeDay}, mode) {
+		mode = b.mode
+	}
+	now := time.Now().In(b.location)
+	date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, b.location)
+	if d, err := time.ParseInLocation(calendarDateLayout, c.CalendarDate, b.location); err == nil {
+		date = d
+	}
+
+	start, count := date, 1
+	switch mode {
+	case CalendarModeMonth:
+		first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, b.location)
+		start = first.AddDate(0, 0, -((int(first.Weekday()) - int(b.weekStart) + 7) % 7))
+		count = 42
+	case CalendarModeWeek:
+		start = date.AddDate(0, 0, -((int(date.Weekday()) - int(b.weekStart) + 7) % 7))
+		count = 7
+	}
+	for i := 0; i < count; i++ {
+		days = append(days, start.AddDate(0, 0, i))
+	}
+	return
+}
+
+// searchParams adds the condition of the records overlapping [from, to) to the listing params.
+func (b *CalendarBuilder) searchParams(searchParams *SearchParams, from, to time.Time) *SearchParams {
+	params := *searchParams
+	cond := &SQLCondition{
+		Query: fmt.Sprintf("%s >= ? AND %s < ?", b.startColumn, b.startColumn),
+		Args:  []interface{}{from, to},
+	}
+	if b.endField != "" {
+		cond = &SQLCondition{
+			Query: fmt.Sprintf("%s < ? AND COALESCE(%s, %s) >= ?", b.startColumn, b.endColumn, b.startColumn),
+			Args:  []interface{}{to, from},
+		}
+	}
+	params.SQLConditions = append(slices.Clone(searchParams.SQLConditions), cond)
+	params.Page = 1
+	params.PerPage = PerPageMax
+	params.OrderBy = nil
+	params.RelayPagination = nil
+	params.RelayPaginateRequest = nil
+	return &params
+}
+
+type calendarEvent struct {
+	obj   any
+	id    string
+	start time.Time
+	end   time.Time
+}
+
+func (b *CalendarBuilder) dataTable(evCtx *web.EventContext, searchParams *SearchParams, _ *SearchResult, _ h.HTMLComponent) h.HTMLComponent {
+	c := ListingCompoFromEventContext(evCtx)
+	ctx := web.WrapEventContext(evCtx.R.Context(), evCtx)
+
+	mode, date, days := b.view(c)
+	from, to := days[0], days[len(days)-1].AddDate(0, 0, 1)
+	result, err := b.lb.Searcher(evCtx, b.searchParams(searchParams, from, to))
+	if err != nil {
+		panic(errors.Wrap(err, "searcher error"))
+	}
+
+	var events []*calendarEvent
+	reflectutils.ForEach(result.Nodes, func(obj any) {
+		start, ok := calendarTime(obj, b.startField)
+		if !ok {
+			return
+		}
+		end := start
+		if b.endField != "" {
+			if t, ok := calendarTime(obj, b.endField); ok && t.After(start) {
+				end = t
+			}
+		}
+		events = append(events, &calendarEvent{obj: obj, id: ObjectID(obj), start: start.In(b.location), end: end.In(b.location)})
+	})
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].start.Before(events[j].start)
+	})
+
+	var cells []h.HTMLComponent
+	for _, day := range days {
+		next := day.AddDate(0, 0, 1)
+		dayEvents := lo.Filter(events, func(e *calendarEvent, _ int) bool {
+			return e.start.Before(next) && !e.end.Before(day)
+		})
+		cells = append(cells, b.dayComponent(ctx, c, mode, date, day, dayEvents))
+	}
+
+	cols := 7
+	if mode == CalendarModeDay {
+		cols = 1
+	}
+	_, msgr := c.MustGetEventContext(ctx)
+	var heads []h.HTMLComponent
+	for _, day := range days[:cols] {
+		heads = append(heads, h.Div(h.Text(msgr.CalendarWeekday(day.Weekday()))).Class("text-caption text-medium-emphasis text-center py-1"))
+	}
+
+	// the records beyond PerPageMax are not searched, the user is told to narrow down the filters
+	var moreNotice h.HTMLComponent
+	if shown := reflect.Indirect(reflect.ValueOf(result.Nodes)).Len(); result.TotalCount != nil && *result.TotalCount > shown {
+		moreNotice = VAlert(h.Text(msgr.CalendarMoreRecords(shown, *result.TotalCount))).
+			Type("warning").Density(DensityCompact).Variant(VariantTonal).Class("mb-4")
+	}
+
+	return web.Scope(
+		b.toolbar(ctx, c, mode, date),
+		moreNotice,
+		h.Div(heads...).Style(fmt.Sprintf("display: grid; grid-template-columns: repeat(%d, minmax(0, 1fr));", cols)),
+		h.Div(cells...).Class("border-s border-t").
+			Style(fmt.Sprintf("display: grid; grid-template-columns: repeat(%d, minmax(0, 1fr));", cols)),
+	).VSlot("{ locals: calendarLocals }").Init(`{ dragging: "", over: "" }`)
+}
+
+func (b *CalendarBuilder) toolbar(ctx context.Context, c *ListingCompo, mode CalendarMode, date time.Time) h.HTMLComponent {
+	_, msgr := c.MustGetEventContext(ctx)
+	reload := func(mode CalendarMode, date time.Time) string {
+		return stateful.ReloadAction(ctx, c, func(target *ListingCompo) {
+			target.CalendarMode = string(mode)
+			target.CalendarDate = date.Format(calendarDateLayout)
+		}).Go()
+	}
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, b.location)
+	prev, next := first.AddDate(0, -1, 0), first.AddDate(0, 1, 0)
+	title := msgr.CalendarTitle(date, msgr.CalendarMonthTitleLayout)
+	switch mode {
+	case CalendarModeWeek:
+		prev, next = date.AddDate(0, 0, -7), date.AddDate(0, 0, 7)
+	case CalendarModeDay:
+		prev, next = date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+		title = msgr.CalendarTitle(date, msgr.CalendarDayTitleLayout)
+	}
+	now := time.Now().In(b.location)
+
+	modeBtn := func(m CalendarMode, label string) h.HTMLComponent {
+		return VBtn(label).Size(SizeSmall).Variant(lo.Ternary(m == mode, VariantFlat, VariantText)).
+			Color(lo.Ternary(m == mode, ColorPrimary, "")).
+			Attr("@click", reload(m, date))
+	}
+	return h.Div(
+		h.Div(
+			VBtn(msgr.CalendarToday).Size(SizeSmall).Variant(VariantOutlined).
+				Attr("@click", reload(mode, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, b.location))),
+			VBtn("").Icon("mdi-chevron-left").Size(SizeSmall).Variant(VariantText).Attr("@click", reload(mode, prev)),
+			VBtn("").Icon("mdi-chevron-right").Size(SizeSmall).Variant(VariantText).Attr("@click", reload(mode, next)),
+			h.Span(title).Class("text-h6 ml-2"),
+		).Class("d-flex align-center ga-1"),
+		h.Div(
+			modeBtn(CalendarModeMonth, msgr.CalendarMonth),
+			modeBtn(CalendarModeWeek, msgr.CalendarWeek),
+			modeBtn(CalendarModeDay, msgr.CalendarDay),
+		).Class("d-flex ga-1"),
+	).Class("d-flex align-center justify-space-between mb-4")
+}
+
+func (b *CalendarBuilder) dayComponent(ctx context.Context, c *ListingCompo, mode CalendarMode, date time.Time, day time.Time, events []*calendarEvent) h.HTMLComponent {
+	evCtx, _ := c.MustGetEventContext(ctx)
+	key := day.Format(calendarDateLayout)
+
+	var items []h.HTMLComponent
+	for _, e := range events {
+		items = append(items, b.eventComponent(evCtx, c, mode, e))
+	}
+	minHeight := "120px"
+	if mode != CalendarModeMonth {
+		minHeight = "480px"
+	}
+	return h.Div(
+		h.Div(h.Text(fmt.Sprint(day.Day()))).Class("text-caption mb-1").
+			ClassIf("text-disabled", mode == CalendarModeMonth && day.Month() != date.Month()),
+		h.Div(items...).Class("d-flex flex-column ga-1"),
+	).Class("border-e border-b pa-1").Style(fmt.Sprintf("min-height: %s; min-width: 0;", minHeight)).
+		Attr(":class", fmt.Sprintf(`{ "bg-grey-lighten-4": calendarLocals.over === %q }`, key)).
+		Attr("@dragover.prevent", fmt.Sprintf(`calendarLocals.over = %q`, key)).
+		Attr("@dragleave", `calendarLocals.over = ""`).
+		Attr("@drop.prevent", fmt.Sprintf(`calendarLocals.over = ""; calendarLocals.dragging && %s`,
+			stateful.PostAction(ctx, c, c.MoveCalendarEvent, MoveCalendarEventRequest{Date: key},
+				stateful.WithAppendFix(`v.request.id = calendarLocals.dragging;`),
+			).ThenScript(`calendarLocals.dragging = ""`).Go()))
+}
+
+func (b *CalendarBuilder) eventComponent(evCtx *web.EventContext, c *ListingCompo, mode CalendarMode, e *calendarEvent) h.HTMLComponent {
+	title := getPageTitle(e.obj, e.id)
+	if b.titleFunc != nil {
+		title = b.titleFunc(evCtx, e.obj)
+	}
+	text := title
+	if mode != CalendarModeMonth || e.start.Hour() != 0 || e.start.Minute() != 0 {
+		text = fmt.Sprintf("%s %s", e.start.Format("15:04"), title)
+	}
+
+	item := h.Div(
+		VChip(h.Text(text)).Size(SizeSmall).Color(ColorPrimary).Variant(VariantTonal).Label(true).
+			Class("w-100 cursor-pointer").Attr("title", text),
+	)
+	if b.moveIsAllowed(evCtx, e.obj) == nil {
+		item.Attr("draggable", "true").
+			Attr("@dragstart", fmt.Sprintf(`$event.dataTransfer.setData("text/plain", %q); calendarLocals.dragging = %q`, e.id, e.id)).
+			Attr("@dragend", `calendarLocals.over = ""`)
+	}
+	return c.defaultCellWrapperFunc(evCtx, item, e.id, e.obj, "")
+}
+
+func (b *CalendarBuilder) moveIsAllowed(evCtx *web.EventContext, obj any) error {
+	if err := b.lb.inlineEditIsAllowed(evCtx, obj, b.startField); err != nil {
+		return err
+	}
+	if b.endField != "" {
+		return b.lb.mb.Info().Verifier().Do(PermUpdate).ObjectOn(obj).SnakeOn("f_" + b.endField).WithReq(evCtx.R).IsAllowed()
+	}
+	return nil
+}
+
+type MoveCalendarEventRequest struct {
+	ID   string `json:"id"`
+	Date string `json:"date"`
+}
+
+// MoveCalendarEvent moves the start of the record to the date keeping the time of the day, the end is moved as much.
+func (c *ListingCompo) MoveCalendarEvent(ctx context.Context, req MoveCalendarEventRequest) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	b := c.lb.calendar
+	if b == nil {
+		return r, errors.New("calendar is not enabled")
+	}
+	date, err := time.ParseInLocation(calendarDateLayout, req.Date, b.location)
+	if err != nil {
+		return r, errors.Wrap(err, "parse date")
+	}
+
+	eb := c.lb.mb.editing
+	obj, err := eb.Fetcher(c.lb.mb.NewModel(), req.ID, evCtx)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			ShowMessage(&r, msgr.RecordNotFound, ColorError)
+			return r, nil
+		}
+		return r, err
+	}
+	if b.moveIsAllowed(evCtx, obj) != nil {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+	start, ok := calendarTime(obj, b.startField)
+	if !ok {
+		return r, errors.Errorf("%s of %s is not set", b.startField, req.ID)
+	}
+	start = start.In(b.location)
+	moved := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), b.location)
+
+	if eb.Setter != nil {
+		eb.Setter(obj, evCtx)
+	}
+	if err = setCalendarTime(obj, b.startField, moved); err != nil {
+		return r, err
+	}
+	if b.endField != "" {
+		if end, ok := calendarTime(obj, b.endField); ok {
+			if err = setCalendarTime(obj, b.endField, end.Add(moved.Sub(start))); err != nil {
+				return r, err
+			}
+		}
+	}
+	if eb.Validator != nil {
+		if vErr := eb.Validator(obj, evCtx); vErr.HaveErrors() {
+			ShowMessage(&r, strings.Join(validationErrorMessages(&vErr), "; "), ColorError)
+			return r, nil
+		}
+	}
+	if err = eb.Saver(obj, req.ID, evCtx); err != nil {
+		var ve *web.ValidationErrors
+		if errors.As(err, &ve) {
+			ShowMessage(&r, ve.Error(), ColorError)
+			return r, nil
+		}
+		return r, err
+	}
+
+	r.Emit(
+		c.lb.mb.NotifModelsUpdated(),
+		PayloadModelsUpdated{Ids: []string{req.ID}, Models: map[string]any{req.ID: obj}},
+	)
+	if c.lb.disableModelListeners {
+		web.AppendRunScripts(&r, stateful.ReloadAction(ctx, c, nil).Go())
+	}
+	ShowMessage(&r, msgr.SuccessfullyUpdated, "")
+	return r, nil
+}
+
+// calendarTime returns the value of the time.Time or *time.Time field, false if it is not set.
+func calendarTime(obj any, name string) (time.Time, bool) {
+	fv := reflect.Indirect(reflect.ValueOf(obj)).FieldByName(name)
+	if !fv.IsValid() || !fv.CanInterface() {
+		return time.Time{}, false
+	}
+	switch t := fv.Interface().(type) {
+	case time.Time:
+		return t, !t.IsZero()
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, false
+		}
+		return *t, !t.IsZero()
+	}
+	return time.Time{}, false
+}
+
+func setCalendarTime(obj any, name string, t time.Time) error {
+	fv := reflect.Indirect(reflect.ValueOf(obj)).FieldByName(name)
+	if !fv.CanSet() {
+		return errors.Errorf("field %s could not be set", name)
+	}
+	switch fv.Interface().(type) {
+	case time.Time:
+		fv.Set(reflect.ValueOf(t))
+	case *time.Time:
+		fv.Set(reflect.ValueOf(&t))
+	default:
+		return errors.Errorf("field %s is not a time", name)
+	}
+	return nil
+}
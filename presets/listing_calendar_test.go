@@ -0,0 +1,126 @@
+package presets
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListingCalendar(t *testing.T) {
+	type Post struct {
+		ID      uint
+		Title   string
+		StartAt *time.Time
+		EndAt   *time.Time
+		Editor  string
+	}
+
+	at := func(s string) *time.Time {
+		v, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+		require.NoError(t, err)
+		return &v
+	}
+	posts := map[string]*Post{
+		"1": {ID: 1, Title: "Launch", StartAt: at("2026-03-10 09:30"), EndAt: at("2026-03-11 18:00")},
+		"2": {ID: 2, Title: "Archived", StartAt: at("2026-03-20 00:00")},
+	}
+	var searched *SearchParams
+	var saved *Post
+	total := 2
+
+	pb := New()
+	mb := pb.Model(&Post{})
+	lb := mb.Listing("Title").SearchFunc(func(ctx *web.EventContext, params *SearchParams) (*SearchResult, error) {
+		searched = params
+		return &SearchResult{Nodes: []*Post{posts["1"], posts["2"]}, TotalCount: &total}, nil
+	})
+	lb.Calendar("StartAt").EndField("EndAt").WeekStart(time.Monday).Location(time.UTC)
+	mb.Editing("Title").
+		FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (interface{}, error) {
+			cp := *posts[id]
+			return &cp, nil
+		}).
+		SetterFunc(func(obj interface{}, ctx *web.EventContext) {
+			obj.(*Post).Editor = "calendar"
+		}).
+		ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+			if obj.(*Post).Title == "Archived" {
+				err.GlobalError("archived posts could not be rescheduled")
+			}
+			return
+		}).
+		SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) error {
+			saved = obj.(*Post)
+			return nil
+		})
+
+	c := &ListingCompo{lb: lb, ID: "posts", CalendarMode: string(CalendarModeMonth), CalendarDate: "2026-03-15"}
+	newCtx := func() context.Context {
+		r := httptest.NewRequest("POST", "/posts", nil)
+		evCtx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+		evCtx.WithContextValue(ctxKeyListingCompo{}, c)
+		return web.WrapEventContext(evCtx.R.Context(), evCtx)
+	}
+
+	mode, _, days := lb.calendar.view(c)
+	assert.Equal(t, CalendarModeMonth, mode)
+	require.Len(t, days, 42)
+	assert.Equal(t, "2026-02-23", days[0].Format(calendarDateLayout))
+	assert.Equal(t, time.Monday, days[0].Weekday())
+
+	c.CalendarMode = string(CalendarModeWeek)
+	_, _, days = lb.calendar.view(c)
+	require.Len(t, days, 7)
+	assert.Equal(t, "2026-03-09", days[0].Format(calendarDateLayout))
+
+	ctx := newCtx()
+	html, err := lb.dataTableFunc(web.MustGetEventContext(ctx), &SearchParams{Model: &Post{}, PerPage: 10, Page: 2}, &SearchResult{}, nil).MarshalHTML(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, searched)
+	cond := searched.SQLConditions[len(searched.SQLConditions)-1]
+	assert.Equal(t, "start_at < ? AND COALESCE(end_at, start_at) >= ?", cond.Query)
+	assert.Equal(t, []interface{}{*at("2026-03-16 00:00"), *at("2026-03-09 00:00")}, cond.Args)
+	assert.Equal(t, int64(1), searched.Page)
+	assert.Contains(t, string(html), "09:30 1")
+	assert.Contains(t, string(html), "MoveCalendarEvent")
+	assert.NotContains(t, string(html), "records are shown")
+
+	total = 2000
+	html, err = lb.dataTableFunc(web.MustGetEventContext(ctx), &SearchParams{Model: &Post{}}, &SearchResult{}, nil).MarshalHTML(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "Only 2 of 2000 records are shown")
+
+	_, err = c.MoveCalendarEvent(newCtx(), MoveCalendarEventRequest{ID: "2", Date: "2026-03-21"})
+	require.NoError(t, err)
+	assert.Nil(t, saved)
+
+	r, err := c.MoveCalendarEvent(newCtx(), MoveCalendarEventRequest{ID: "1", Date: "2026-03-13"})
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, *at("2026-03-13 09:30"), *saved.StartAt)
+	assert.Equal(t, *at("2026-03-14 18:00"), *saved.EndAt)
+	assert.Equal(t, "calendar", saved.Editor)
+	assert.NotEmpty(t, r.RunScript)
+}
+
+func TestCalendarMessages(t *testing.T) {
+	date := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		msgr       *Messages
+		month, day string
+		weekday    string
+	}{
+		{Messages_en_US, "March 2024", "Tuesday, March 5, 2024", "Tue"},
+		{Messages_zh_CN, "2024年3月", "2024年3月5日 星期二", "二"},
+		{Messages_ja_JP, "2024年3月", "2024年3月5日(火)", "火"},
+	} {
+		assert.Equal(t, c.month, c.msgr.CalendarTitle(date, c.msgr.CalendarMonthTitleLayout))
+		assert.Equal(t, c.day, c.msgr.CalendarTitle(date, c.msgr.CalendarDayTitleLayout))
+		assert.Equal(t, c.weekday, c.msgr.CalendarWeekday(date.Weekday()))
+	}
+}
@@ -52,6 +52,7 @@ type ListingCompo struct {
 	lb *ListingBuilder `inject:""`
 
 	activeFilterTabQuery string
+	listingViews         []*ListingView
 
 	ID                 string           `json:"id"`
 	Popup              bool             `json:"popup"`
@@ -66,6 +67,9 @@ type ListingCompo struct {
 	DisplayColumns     []*DisplayColumn `json:"display_columns" query:",omitempty;cookie"`
 	ActiveFilterTab    string           `json:"active_filter_tab" query:",omitempty"`
 	FilterQuery        string           `json:"filter_query" query:";method:bare,f_"`
+	KanbanPages        map[string]int64 `json:"kanban_pages,omitempty"`
+	CalendarMode       string           `json:"calendar_mode" query:",omitempty"`
+	CalendarDate       string           `json:"calendar_date" query:",omitempty"`
 
 	OnMounted string `json:"on_mounted"`
 	ParentID  string `json:"parent_id,omitempty"`
@@ -135,6 +139,12 @@ func (c *ListingCompo) MarshalHTML(ctx context.Context) (r []byte, err error) {
 	evCtx, _ := c.MustGetEventContext(ctx)
 	evCtx.WithContextValue(ctxKeyListingCompo{}, c)
 
+	if c.lb.views != nil {
+		if c.listingViews, err = c.lb.views.list(evCtx.R); err != nil {
+			return nil, err
+		}
+	}
+
 	return stateful.Actionable(ctx, c,
 		h.Div().Id(ListingLocatorID(c.CompoID())),
 		// onMounted for selected_ids front-end autonomy
@@ -196,13 +206,20 @@ func (c *ListingCompo) filterNotification(ctx context.Context) h.HTMLComponent {
 }
 
 func (c *ListingCompo) tabsFilter(ctx context.Context) h.HTMLComponent {
-	if c.lb.filterTabsFunc == nil {
+	pinnedViews := lo.Filter(c.listingViews, func(v *ListingView, _ int) bool {
+		return v.Pinned
+	})
+	evCtx, _ := c.MustGetEventContext(ctx)
+	trash := c.lb.mb.trash != nil && c.lb.mb.trash.tabIsAllowed(evCtx)
+	if c.lb.filterTabsFunc == nil && len(pinnedViews) == 0 && !trash {
 		return nil
 	}
-	evCtx, _ := c.MustGetEventContext(ctx)
 
 	activeIndex := -1
-	fts := c.lb.filterTabsFunc(evCtx)
+	var fts []*FilterTab
+	if c.lb.filterTabsFunc != nil {
+		fts = c.lb.filterTabsFunc(evCtx)
+	}
 	tabs := VTabs().Class("mb-2").ShowArrows(true).Color(ColorPrimary).Density(DensityCompact)
 	for i, ft := range fts {
 		if ft.ID == "" {
@@ -230,6 +247,24 @@ func (c *ListingCompo) tabsFilter(ctx context.Context) h.HTMLComponent {
 				),
 		)
 	}
+	for i, v := range pinnedViews {
+		if c.ActiveFilterTab == listingViewTabID(v) {
+			activeIndex = len(fts) + i
+		}
+		tabs.AppendChildren(
+			VTab().
+				Attr("@click", stateful.ReloadAction(ctx, c, func(target *ListingCompo) {
+					c.applyView(target, v)
+				}).ThenScript(c.JsScrollToTop()).Go()).
+				Children(h.Text(v.Name)),
+		)
+	}
+	if trash {
+		if c.inTrash(evCtx) {
+			activeIndex = len(fts) + len(pinnedViews)
+		}
+		tabs.AppendChildren(c.trashTab(ctx))
+	}
 	return tabs.ModelValue(activeIndex)
 }
 
@@ -396,10 +431,14 @@ func (c *ListingCompo) toolbarSearch(ctx context.Context) h.HTMLComponent {
 	return VToolbar().Flat(true).Color("surface").AutoHeight(true).Class("pa-2").Class("filter-comp-wrap").Children(
 		textFieldSearch,
 		filterSearch,
+		c.viewsMenu(ctx, c.listingViews),
 	)
 }
 
 func (c *ListingCompo) defaultCellWrapperFunc(envCtx *web.EventContext, cell h.MutableAttrHTMLComponent, id string, _ any, _ string) h.HTMLComponent {
+	if c.inTrash(envCtx) {
+		return cell
+	}
 	if c.lb.mb.hasDetailing && !c.lb.mb.detailing.drawer {
 		cell.SetAttr("@click", web.Plaid().PushStateURL(c.lb.mb.Info().DetailingHref(id)).Go())
 		return cell
@@ -425,31 +464,7 @@ func (c *ListingCompo) defaultCellWrapperFunc(envCtx *web.EventContext, cell h.M
 }
 
 func (c *ListingCompo) getOrderBy(colOrderBys []ColOrderBy, orderableFieldMap map[string]bool) []relay.Order {
-	var orderBy []relay.Order
-	for _, ob := range colOrderBys {
-		if orderableFieldMap[ob.FieldName] {
-			direction := relay.OrderDirectionAsc
-			if ob.OrderBy == OrderByDESC {
-				direction = relay.OrderDirectionDesc
-			}
-			orderBy = append(orderBy, relay.Order{
-				Field:     ob.FieldName,
-				Direction: direction,
-			})
-		}
-	}
-	var primaryOrderBy []relay.Order
-	if len(c.lb.defaultOrderBy) > 0 {
-		primaryOrderBy = c.lb.defaultOrderBy
-	} else {
-		// fallback to deprecated defaultOrderBys
-		primaryOrderBy = relay.OrderByFromOrderBys(c.lb.defaultOrderBys)
-	}
-	if len(primaryOrderBy) == 0 && c.lb.mb.primaryField != "" {
-		primaryOrderBy = []relay.Order{{Field: c.lb.mb.primaryField, Direction: relay.OrderDirectionDesc}}
-	}
-	orderBy = relay.AppendPrimaryOrderBy(orderBy, primaryOrderBy...)
-	return orderBy
+	return c.lb.orderBy(colOrderBys, orderableFieldMap)
 }
 
 func (c *ListingCompo) processFilter(evCtx *web.EventContext) (h.HTMLComponent, []*SQLCondition, *Filter) {
@@ -586,12 +601,11 @@ func (c *ListingCompo) headCellWrapperFunc(ctx context.Context, columns []*Colum
 }
 
 func (c *ListingCompo) setupBulkActions(ctx context.Context, dataTable *vx.DataTableBuilder) {
-	if len(c.lb.bulkActions) <= 0 {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	if len(c.lb.bulkActions) <= 0 && !c.inTrash(evCtx) {
 		return
 	}
 
-	_, msgr := c.MustGetEventContext(ctx)
-
 	syncQuery := ""
 	if stateful.IsSyncQuery(ctx) {
 		syncQuery = web.Plaid().PushState(true).MergeQuery(true).Query("selected_ids", web.Var(`selected_ids`)).RunPushState()
@@ -602,49 +616,48 @@ func (c *ListingCompo) setupBulkActions(ctx context.Context, dataTable *vx.DataT
 		ClearSelectionLabel(msgr.ListingClearSelection)
 }
 
-func (c *ListingCompo) setupColumns(dataTable *vx.DataTableBuilder, columns []*Column) {
+func (c *ListingCompo) setupColumns(ctx context.Context, dataTable *vx.DataTableBuilder, columns []*Column) {
 	for _, col := range columns {
 		if !col.Visible {
 			continue
 		}
 		// fill in empty compFunc and setter func with default
 		f := c.lb.getFieldOrDefault(col.Name)
-		dataTable.Column(col.Name).Title(col.Label).CellComponentFunc(c.lb.cellComponentFunc(f))
+		dataTable.Column(col.Name).Title(col.Label).CellComponentFunc(c.cellComponentFunc(ctx, f))
 	}
 }
 
-func (c *ListingCompo) dataTable(ctx context.Context) h.HTMLComponent {
-	if c.lb.Searcher == nil {
-		panic(errors.New("function Searcher is not set"))
-	}
-
-	evCtx, _ := c.MustGetEventContext(ctx)
-
-	searchParams := &SearchParams{
-		Model:         c.lb.mb.NewModel(),
-		PageURL:       evCtx.R.URL,
-		SQLConditions: c.lb.conditions,
-	}
-
-	if !c.lb.keywordSearchOff {
-		searchParams.KeywordColumns = c.lb.searchColumns
-		searchParams.Keyword = c.Keyword
-	}
-
-	colOrderBys := lo.Map(c.OrderBys, func(ob ColOrderBy, _ int) ColOrderBy {
+func (c *ListingCompo) colOrderBys() []ColOrderBy {
+	return lo.Map(c.OrderBys, func(ob ColOrderBy, _ int) ColOrderBy {
 		ob.OrderBy = strings.ToUpper(ob.OrderBy)
 		if ob.OrderBy != OrderByASC && ob.OrderBy != OrderByDESC {
 			ob.OrderBy = OrderByDESC
 		}
 		return ob
 	})
+}
 
+func (b *ListingBuilder) orderableFieldMap() map[string]bool {
 	orderableFieldMap := make(map[string]bool)
-	for _, v := range c.lb.orderableFields {
+	for _, v := range b.orderableFields {
 		orderableFieldMap[v.FieldName] = true
 	}
+	return orderableFieldMap
+}
+
+// searchParams builds the SearchParams for the current state of the compo,
+// the returned filterScript reports invalid filter values to the user.
+func (c *ListingCompo) searchParams(evCtx *web.EventContext) (searchParams *SearchParams, filterScript h.HTMLComponent) {
+	searchParams = &SearchParams{
+		Model:         c.lb.mb.NewModel(),
+		PageURL:       evCtx.R.URL,
+		SQLConditions: c.lb.conditions,
+		Trashed:       c.inTrash(evCtx),
+	}
 
-	searchParams.OrderBy = c.getOrderBy(colOrderBys, orderableFieldMap)
+	colOrderBys := c.colOrderBys()
+	c.lb.setKeyword(searchParams, c.Keyword, colOrderBys)
+	searchParams.OrderBy = c.getOrderBy(colOrderBys, c.lb.orderableFieldMap())
 
 	if !c.lb.disablePagination {
 		perPage := c.PerPage
@@ -670,8 +683,22 @@ func (c *ListingCompo) dataTable(ctx context.Context) h.HTMLComponent {
 	searchParams.SQLConditions = append(searchParams.SQLConditions, filterConds...)
 	if builtFilter != nil {
 		searchParams.Filter = builtFilter
+		searchParams.FilterInSQLConditions = true
+	}
+	return searchParams, filterScript
+}
+
+func (c *ListingCompo) dataTable(ctx context.Context) h.HTMLComponent {
+	if c.lb.Searcher == nil {
+		panic(errors.New("function Searcher is not set"))
 	}
 
+	evCtx, _ := c.MustGetEventContext(ctx)
+
+	colOrderBys := c.colOrderBys()
+	orderableFieldMap := c.lb.orderableFieldMap()
+	searchParams, filterScript := c.searchParams(evCtx)
+
 	var searchResult *SearchResult
 	if c.lb.relayPagination != nil {
 		searchParams.RelayPagination = c.lb.relayPagination
@@ -720,11 +747,14 @@ func (c *ListingCompo) dataTable(ctx context.Context) h.HTMLComponent {
 			HeadCellWrapperFunc(c.headCellWrapperFunc(ctx, columns, colOrderBys, orderableFieldMap)).
 			RowWrapperFunc(c.rowWrapperFunc(evCtx)).
 			RowMenuHead(btnConfigColumns).
-			RowMenuItemFuncs(c.lb.RowMenu().listingItemFuncs(evCtx)...).
+			RowMenuItemFuncs(c.rowMenuItemFuncs(ctx)...).
 			CellWrapperFunc(c.cellWrapperFunc(evCtx))
 
 		c.setupBulkActions(ctx, dataTable)
-		c.setupColumns(dataTable, columns)
+		c.setupColumns(ctx, dataTable, columns)
+		if footer := c.aggregateFooter(ctx, searchParams, searchResult.Nodes, columns, btnConfigColumns != nil); footer != nil {
+			dataTable.Tfoot(footer)
+		}
 
 		if c.lb.tableProcessor != nil {
 			dataTable, err = c.lb.tableProcessor(evCtx, dataTable)
@@ -1171,6 +1201,9 @@ func (c *ListingCompo) ActionsComponentTeleportToID() string {
 
 func (c *ListingCompo) actionsComponent(ctx context.Context) (r h.HTMLComponent) {
 	evCtx, msgr := c.MustGetEventContext(ctx)
+	if c.inTrash(evCtx) {
+		return c.trashActionsComponent(ctx)
+	}
 
 	var buttons []h.HTMLComponent
 
@@ -1212,6 +1245,8 @@ func (c *ListingCompo) actionsComponent(ctx context.Context) (r h.HTMLComponent)
 		)
 	}
 
+	buttons = append(buttons, c.exportButton(ctx), c.importButton(ctx))
+
 	buttonNew := c.lb.newBtnFunc(evCtx)
 
 	if c.lb.actionsAsMenu {
@@ -1260,6 +1295,22 @@ func (c *ListingCompo) bulkPanel(ctx context.Context, bulk *BulkActionBuilder, s
 		}
 	}
 
+	actions := VCardActions(
+		VSpacer(),
+		VBtn(msgr.Cancel).Variant(VariantFlat).Class("ml-2").Attr("@click", c.closeActionDialog()),
+		VBtn(msgr.OK).Color("primary").Variant(VariantFlat).Theme(ThemeDark).Attr("@click",
+			stateful.PostAction(ctx, c, c.DoBulkAction, DoBulkActionRequest{
+				Name: bulk.name,
+			}).Go(),
+		),
+	)
+	if bulk.readOnly {
+		actions = VCardActions(
+			VSpacer(),
+			VBtn(msgr.Close).Variant(VariantFlat).Class("ml-2").Attr("@click", c.closeActionDialog()),
+		)
+	}
+
 	return VCard(
 		VCardTitle(
 			h.Text(bulk.NameLabel.label),
@@ -1269,15 +1320,7 @@ func (c *ListingCompo) bulkPanel(ctx context.Context, bulk *BulkActionBuilder, s
 			alertCompo,
 			bulk.compFunc(selectedIds, evCtx),
 		),
-		VCardActions(
-			VSpacer(),
-			VBtn(msgr.Cancel).Variant(VariantFlat).Class("ml-2").Attr("@click", c.closeActionDialog()),
-			VBtn(msgr.OK).Color("primary").Variant(VariantFlat).Theme(ThemeDark).Attr("@click",
-				stateful.PostAction(ctx, c, c.DoBulkAction, DoBulkActionRequest{
-					Name: bulk.name,
-				}).Go(),
-			),
-		),
+		actions,
 	)
 }
 
@@ -1289,7 +1332,7 @@ func (c *ListingCompo) fetchBulkAction(ctx context.Context, name string) (*BulkA
 		return nil, errors.New("cannot find requested bulk action")
 	}
 
-	if bulk.updateFunc == nil {
+	if bulk.updateFunc == nil && !bulk.readOnly {
 		return nil, errors.New("bulk.updateFunc not set")
 	}
 
@@ -1306,6 +1349,12 @@ func (c *ListingCompo) fetchBulkAction(ctx context.Context, name string) (*BulkA
 	if len(c.SelectedIds) == 0 {
 		return nil, errors.New(msgr.BulkActionNoRecordsSelected)
 	}
+	if c.SelectedIds, err = c.lb.mb.idsInRowScope(evCtx, c.SelectedIds); err != nil {
+		return nil, err
+	}
+	if len(c.SelectedIds) == 0 {
+		return nil, errors.New(msgr.BulkActionNoAvailableRecords)
+	}
 
 	return bulk, nil
 }
@@ -1352,6 +1401,9 @@ func (c *ListingCompo) DoBulkAction(ctx context.Context, req DoBulkActionRequest
 	evCtx, _ := c.MustGetEventContext(ctx)
 
 	bulk, err := c.fetchBulkAction(ctx, req.Name)
+	if err == nil && bulk.readOnly {
+		err = errors.New("bulk action is read only")
+	}
 	if err != nil {
 		ShowMessage(&r, err.Error(), ColorError)
 		return r, nil
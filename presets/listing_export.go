@@ -0,0 +1,465 @@
+package presets
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/web/v3/stateful"
+	"github.com/qor5/x/v3/i18n"
+	"github.com/qor5/x/v3/perm"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/samber/lo"
+	h "github.com/theplant/htmlgo"
+	"github.com/theplant/relay"
+	"github.com/xuri/excelize/v2"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatXLSX ExportFormat = "xlsx"
+)
+
+const (
+	paramExportRequest = "request"
+	exportSheetName    = "Sheet1"
+)
+
+var exportTextPolicy = bluemonday.StrictPolicy()
+
+type (
+	// ExportValueFunc returns the raw value written into the exported cell,
+	// numbers and times are kept as is in xlsx, everything else is formatted with fmt.
+	ExportValueFunc func(obj any, field *FieldContext, ctx *web.EventContext) any
+
+	// ListingExportJobFunc hands the export over to a background runner instead of
+	// streaming it in the current request, see ListingExportBuilder.JobHandoff.
+	ListingExportJobFunc func(evCtx *web.EventContext, req *ListingExportRequest, r *web.EventResponse) (err error)
+)
+
+// ListingExportRequest is the serializable state of an export,
+// it is resolved from ListingCompo so it can be replayed later, e.g. by a worker job.
+type ListingExportRequest struct {
+	Format      ExportFormat `json:"format"`
+	Keyword     string       `json:"keyword,omitempty"`
+	FilterQuery string       `json:"filter_query,omitempty"`
+	OrderBys    []ColOrderBy `json:"order_bys,omitempty"`
+	ParentID    string       `json:"parent_id,omitempty"`
+	// Columns are the visible columns in display order, already filtered by the field permissions
+	Columns []string `json:"columns"`
+}
+
+type ListingExportBuilder struct {
+	lb           *ListingBuilder
+	formats      []ExportFormat
+	valueFuncs   map[string]ExportValueFunc
+	fileNameFunc func(evCtx *web.EventContext, format ExportFormat) string
+	jobThreshold int
+	jobFunc      ListingExportJobFunc
+}
+
+// Export enables the export action on the listing, csv and xlsx are enabled if no formats given.
+func (b *ListingBuilder) Export(formats ...ExportFormat) (r *ListingExportBuilder) {
+	if b.exporting == nil {
+		b.exporting = &ListingExportBuilder{
+			lb:         b,
+			valueFuncs: map[string]ExportValueFunc{},
+		}
+	}
+	r = b.exporting
+	if len(formats) == 0 {
+		formats = []ExportFormat{ExportFormatCSV, ExportFormatXLSX}
+	}
+	r.formats = formats
+	return
+}
+
+func (b *ListingBuilder) GetExport() *ListingExportBuilder {
+	return b.exporting
+}
+
+// ValueFunc overrides the cell value of the field, by default the text of the listing cell component is used.
+func (b *ListingExportBuilder) ValueFunc(name string, v ExportValueFunc) (r *ListingExportBuilder) {
+	b.valueFuncs[name] = v
+	return b
+}
+
+func (b *ListingExportBuilder) FileNameFunc(v func(evCtx *web.EventContext, format ExportFormat) string) (r *ListingExportBuilder) {
+	b.fileNameFunc = v
+	return b
+}
+
+// JobHandoff calls f instead of streaming the file when the listing has more than threshold records,
+// a threshold <= 0 hands off every export.
+func (b *ListingExportBuilder) JobHandoff(threshold int, f ListingExportJobFunc) (r *ListingExportBuilder) {
+	b.jobThreshold = threshold
+	b.jobFunc = f
+	return b
+}
+
+func (b *ListingExportBuilder) Href(req *ListingExportRequest) string {
+	data, _ := json.Marshal(req)
+	return fmt.Sprintf("%s/export/%s?%s", b.lb.mb.Info().ListingHref(), req.Format, url.Values{paramExportRequest: {string(data)}}.Encode())
+}
+
+func (b *ListingExportBuilder) FileName(evCtx *web.EventContext, format ExportFormat) string {
+	if b.fileNameFunc != nil {
+		return b.fileNameFunc(evCtx, format)
+	}
+	return fmt.Sprintf("%s-%s.%s", b.lb.mb.uriName, time.Now().Format("20060102150405"), format)
+}
+
+func (b *ListingExportBuilder) isAllowed(evCtx *web.EventContext) error {
+	if b.lb.mb.Info().Verifier().Do(PermList).WithReq(evCtx.R).IsAllowed() != nil {
+		return perm.PermissionDenied
+	}
+	return b.lb.mb.Info().Verifier().Do(PermExport).WithReq(evCtx.R).IsAllowed()
+}
+
+func (b *ListingExportBuilder) compo(req *ListingExportRequest) *ListingCompo {
+	return &ListingCompo{
+		lb:          b.lb,
+		Keyword:     req.Keyword,
+		FilterQuery: req.FilterQuery,
+		OrderBys:    req.OrderBys,
+		ParentID:    req.ParentID,
+	}
+}
+
+// permittedColumns returns the visible columns of the compo which the current user is allowed to list.
+func (b *ListingExportBuilder) permittedColumns(ctx context.Context, c *ListingCompo) ([]string, error) {
+	_, columns, err := c.getColumns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.FilterMap(columns, func(col *Column, _ int) (string, bool) {
+		return col.Name, col.Visible
+	}), nil
+}
+
+// NewRequest resolves the export request for the current state of the listing compo.
+func (b *ListingExportBuilder) NewRequest(ctx context.Context, c *ListingCompo, format ExportFormat) (*ListingExportRequest, error) {
+	columns, err := b.permittedColumns(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return &ListingExportRequest{
+		Format:      format,
+		Keyword:     c.Keyword,
+		FilterQuery: c.FilterQuery,
+		OrderBys:    c.OrderBys,
+		ParentID:    c.ParentID,
+		Columns:     columns,
+	}, nil
+}
+
+// Write re-runs the search of the request without pagination and streams the rows into w.
+// The columns of req are trusted, callers must make sure they are resolved with NewRequest.
+func (b *ListingExportBuilder) Write(evCtx *web.EventContext, req *ListingExportRequest, w io.Writer) (err error) {
+	if !lo.Contains(b.formats, req.Format) {
+		return errors.Errorf("unsupported export format %q", req.Format)
+	}
+	if b.lb.Searcher == nil {
+		return errors.New("function Searcher is not set")
+	}
+
+	c := b.compo(req)
+	ctx := web.WrapEventContext(context.WithValue(evCtx.R.Context(), ctxKeyListingCompo{}, c), evCtx)
+	evCtx.R = evCtx.R.WithContext(ctx)
+
+	fields := lo.Map(req.Columns, func(name string, _ int) *FieldBuilder {
+		return b.lb.getFieldOrDefault(name)
+	})
+	header := lo.Map(fields, func(f *FieldBuilder, _ int) any {
+		return i18n.PT(evCtx.R, ModelsI18nModuleKey, b.lb.mb.label, b.lb.mb.getLabel(f.NameLabel))
+	})
+
+	var rw exportRowWriter
+	switch req.Format {
+	case ExportFormatXLSX:
+		rw, err = newXLSXRowWriter(w)
+	default:
+		rw, err = newCSVRowWriter(w)
+	}
+	if err != nil {
+		return err
+	}
+	if err = rw.WriteRow(header); err != nil {
+		return err
+	}
+
+	searchParams, _ := c.searchParams(evCtx)
+	searchParams.PerPage = PerPageMax
+	searchParams.Page = 1
+	if b.lb.relayPagination != nil {
+		searchParams.RelayPagination = b.lb.relayPagination
+		searchParams.RelayPaginateRequest = &relay.PaginateRequest[any]{
+			First:   lo.ToPtr(PerPageMax),
+			OrderBy: searchParams.OrderBy,
+		}
+	}
+
+	written := 0
+	for {
+		result, err := b.lb.Searcher(evCtx, searchParams)
+		if err != nil {
+			return errors.Wrap(err, "searcher error")
+		}
+		nodes := reflect.Indirect(reflect.ValueOf(result.Nodes))
+		if !nodes.IsValid() || nodes.Len() == 0 {
+			break
+		}
+		for i := 0; i < nodes.Len(); i++ {
+			obj := nodes.Index(i).Interface()
+			row := make([]any, 0, len(fields))
+			for _, f := range fields {
+				row = append(row, b.cellValue(obj, f, evCtx))
+			}
+			if err = rw.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		written += nodes.Len()
+		if result.TotalCount != nil && written >= *result.TotalCount {
+			break
+		}
+
+		if b.lb.relayPagination != nil {
+			if !result.PageInfo.HasNextPage || result.PageInfo.EndCursor == nil {
+				break
+			}
+			searchParams.RelayPaginateRequest.After = result.PageInfo.EndCursor
+			continue
+		}
+		if int64(nodes.Len()) < searchParams.PerPage {
+			break
+		}
+		searchParams.Page++
+	}
+	return rw.Flush()
+}
+
+func (b *ListingExportBuilder) cellValue(obj any, f *FieldBuilder, evCtx *web.EventContext) any {
+	field := b.lb.mb.getComponentFuncField(f)
+	if vf, ok := b.valueFuncs[f.name]; ok {
+		return vf(obj, field, evCtx)
+	}
+	compo := b.lb.cellComponentFunc(f)(obj, f.name, evCtx)
+	if compo == nil {
+		return ""
+	}
+	body, err := compo.MarshalHTML(evCtx.R.Context())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(html.UnescapeString(exportTextPolicy.Sanitize(string(body))))
+}
+
+func (b *ListingExportBuilder) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	evCtx := &web.EventContext{R: r, W: w}
+	if err := b.isAllowed(evCtx); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	req := &ListingExportRequest{}
+	if err := json.Unmarshal([]byte(r.FormValue(paramExportRequest)), req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Format = ExportFormat(r.PathValue("format"))
+
+	// the requested columns can not exceed what the current user is allowed to see
+	c := b.compo(req)
+	permitted, err := b.permittedColumns(web.WrapEventContext(r.Context(), evCtx), c)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	req.Columns = lo.Intersect(req.Columns, permitted)
+	if len(req.Columns) == 0 {
+		req.Columns = permitted
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if req.Format == ExportFormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename=%q`, b.FileName(evCtx, req.Format)))
+	if err := b.Write(evCtx, req, w); err != nil {
+		// headers may already be sent, so the error can only be logged
+		b.lb.mb.p.logger.Error(fmt.Sprintf("export %s failed: %+v", b.lb.mb.uriName, err))
+	}
+}
+
+type ExportListingRequest struct {
+	Format ExportFormat `json:"format"`
+}
+
+func (c *ListingCompo) ExportListing(ctx context.Context, req ExportListingRequest) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	eb := c.lb.exporting
+	if eb == nil {
+		return r, errors.New("export is not enabled")
+	}
+	if err = eb.isAllowed(evCtx); err != nil {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+
+	exportReq, err := eb.NewRequest(ctx, c, req.Format)
+	if err != nil {
+		return r, err
+	}
+
+	if eb.jobFunc != nil {
+		handoff := eb.jobThreshold <= 0
+		if !handoff {
+			searchParams, _ := c.searchParams(evCtx)
+			searchParams.Page, searchParams.PerPage = 1, 1
+			result, err := c.lb.Searcher(evCtx, searchParams)
+			if err != nil {
+				return r, errors.Wrap(err, "searcher error")
+			}
+			// the total count is unknown with some relay paginations, play safe then
+			handoff = result.TotalCount == nil || *result.TotalCount > eb.jobThreshold
+		}
+		if handoff {
+			if err = eb.jobFunc(evCtx, exportReq, &r); err != nil {
+				return r, err
+			}
+			ShowMessage(&r, msgr.ListingExportJobStarted, "")
+			return r, nil
+		}
+	}
+
+	web.AppendRunScripts(&r, fmt.Sprintf(`window.open(%q, "_blank")`, eb.Href(exportReq)))
+	return r, nil
+}
+
+func (c *ListingCompo) exportButton(ctx context.Context) h.HTMLComponent {
+	eb := c.lb.exporting
+	if eb == nil || len(eb.formats) == 0 {
+		return nil
+	}
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	if eb.isAllowed(evCtx) != nil {
+		return nil
+	}
+	return VMenu().Children(
+		web.Slot().Name("activator").Scope("{ props }").Children(
+			VBtn(msgr.ListingExport).Attr("v-bind", "props").
+				Variant(VariantFlat).Color(ColorSecondary).Class("ml-2").
+				PrependIcon("mdi-download"),
+		),
+		VList(lo.Map(eb.formats, func(format ExportFormat, _ int) h.HTMLComponent {
+			return VListItem(
+				VListItemTitle(h.Text(strings.ToUpper(string(format)))),
+			).Attr("@click", stateful.PostAction(ctx, c, c.ExportListing, ExportListingRequest{
+				Format: format,
+			}).Go())
+		})...).Density(DensityCompact),
+	)
+}
+
+type exportRowWriter interface {
+	WriteRow(values []any) error
+	Flush() error
+}
+
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func newCSVRowWriter(w io.Writer) (*csvRowWriter, error) {
+	// the BOM makes excel detect the encoding correctly
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return nil, err
+	}
+	return &csvRowWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (cw *csvRowWriter) WriteRow(values []any) error {
+	return cw.w.Write(lo.Map(values, func(v any, _ int) string {
+		switch vt := v.(type) {
+		case nil:
+			return ""
+		case time.Time:
+			return vt.Format(time.RFC3339)
+		}
+		s := fmt.Sprint(v)
+		switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return s
+		}
+		return escapeCSVFormula(s)
+	}))
+}
+
+// escapeCSVFormula prefixes the values which spreadsheets would evaluate as formulas with a quote,
+// the numbers are kept so that the negative ones are still numbers.
+func escapeCSVFormula(v string) string {
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v
+	}
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func (cw *csvRowWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type xlsxRowWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXRowWriter(w io.Writer) (*xlsxRowWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(exportSheetName)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxRowWriter{w: w, file: file, stream: stream}, nil
+}
+
+func (xw *xlsxRowWriter) WriteRow(values []any) error {
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	return xw.stream.SetRow(cell, values)
+}
+
+func (xw *xlsxRowWriter) Flush() error {
+	defer xw.file.Close()
+	if err := xw.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := xw.file.WriteTo(xw.w)
+	return err
+}
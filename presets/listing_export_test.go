@@ -0,0 +1,152 @@
+package presets
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/perm"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCSVRowWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rw, err := newCSVRowWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, row := range [][]any{
+		{"Name", "Price", "CreatedAt"},
+		{"Apple, red", 1.5, at},
+		{nil, 2, "x"},
+		{"=HYPERLINK(\"http://x\")", -3, "@SUM(A1)"},
+		{"-4.5", "+1", "-x"},
+	} {
+		if err := rw.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\xEF\xBB\xBFName,Price,CreatedAt\n\"Apple, red\",1.5,2024-01-02T03:04:05Z\n,2,x\n\"'=HYPERLINK(\"\"http://x\"\")\",-3,'@SUM(A1)\n-4.5,+1,'-x\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestXLSXRowWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rw, err := newXLSXRowWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range [][]any{
+		{"Name", "Price"},
+		{"Apple", 1.5},
+	} {
+		if err := rw.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows(exportSheetName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0][0] != "Name" || rows[1][0] != "Apple" || rows[1][1] != "1.5" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+type exportProduct struct {
+	ID    uint
+	Name  string
+	Price float64
+}
+
+func newExportTestBuilder(products []*exportProduct, withTotal bool, searches *int) (*Builder, *ListingExportBuilder) {
+	b := New().Permission(perm.New().Policies(
+		perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(perm.Anything).On(perm.Anything),
+		perm.PolicyFor("viewer").WhoAre(perm.Denied).ToDo(PermExport).On(perm.Anything),
+	).SubjectsFunc(func(r *http.Request) []string {
+		return []string{r.Header.Get("Role")}
+	}))
+	mb := b.Model(&exportProduct{})
+	mb.Listing("Name", "Price").SearchFunc(func(evCtx *web.EventContext, params *SearchParams) (*SearchResult, error) {
+		*searches++
+		start := min(int((params.Page-1)*params.PerPage), len(products))
+		end := min(start+int(params.PerPage), len(products))
+		result := &SearchResult{Nodes: products[start:end]}
+		if withTotal {
+			result.TotalCount = lo.ToPtr(len(products))
+		}
+		return result, nil
+	})
+	return b, mb.Listing().Export()
+}
+
+func TestListingExportWrite(t *testing.T) {
+	products := make([]*exportProduct, PerPageMax)
+	for i := range products {
+		products[i] = &exportProduct{ID: uint(i + 1), Name: fmt.Sprintf("P%d", i+1), Price: -float64(i)}
+	}
+	write := func(withTotal bool) (string, int) {
+		searches := 0
+		_, eb := newExportTestBuilder(products, withTotal, &searches)
+		buf := &bytes.Buffer{}
+		evCtx := &web.EventContext{R: httptest.NewRequest(http.MethodGet, "/export-products/export/csv", nil)}
+		err := eb.Write(evCtx, &ListingExportRequest{Format: ExportFormatCSV, Columns: []string{"Name", "Price"}}, buf)
+		require.NoError(t, err)
+		return buf.String(), searches
+	}
+
+	body, searches := write(true)
+	assert.Equal(t, 1, searches, "stops when the total count is reached")
+	lines := strings.Split(strings.TrimSuffix(strings.TrimPrefix(body, "\xEF\xBB\xBF"), "\n"), "\n")
+	require.Len(t, lines, PerPageMax+1)
+	assert.Equal(t, "Name,Price", lines[0])
+	assert.Equal(t, "P2,-1", lines[2])
+
+	_, searches = write(false)
+	assert.Equal(t, 2, searches, "stops on the empty page without a total count")
+}
+
+func TestListingExportRoutePermission(t *testing.T) {
+	searches := 0
+	_, eb := newExportTestBuilder([]*exportProduct{{ID: 1, Name: "Apple", Price: 1.5}}, true, &searches)
+	get := func(role string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, eb.Href(&ListingExportRequest{Format: ExportFormatCSV, Columns: []string{"Name", "Price"}}), nil)
+		r.SetPathValue("format", string(ExportFormatCSV))
+		r.Header.Set("Role", role)
+		w := httptest.NewRecorder()
+		eb.serveHTTP(w, r)
+		return w
+	}
+
+	w := get("viewer")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, 0, searches)
+
+	w = get("editor")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "\xEF\xBB\xBFName,Price\nApple,1.5\n", w.Body.String())
+}
@@ -0,0 +1,182 @@
+package presets
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/web/v3/stateful"
+	"github.com/qor5/x/v3/i18n"
+	"github.com/qor5/x/v3/perm"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	vx "github.com/qor5/x/v3/ui/vuetifyx"
+	"github.com/samber/lo"
+	h "github.com/theplant/htmlgo"
+)
+
+// InlineEditFields renders the editing component of the fields in the listing cells,
+// the value is validated with the ValidateFunc of the editing and saved with its SaveFunc.
+func (b *ListingBuilder) InlineEditFields(names ...string) (r *ListingBuilder) {
+	b.inlineEditFields = names
+	return b
+}
+
+func (b *ListingBuilder) isInlineEditField(name string) bool {
+	return lo.Contains(b.inlineEditFields, name) && b.mb.editing.GetField(name) != nil
+}
+
+func (b *ListingBuilder) inlineEditIsAllowed(evCtx *web.EventContext, obj any, name string) error {
+	if b.mb.Info().Verifier().Do(PermUpdate).ObjectOn(obj).WithReq(evCtx.R).IsAllowed() != nil {
+		return perm.PermissionDenied
+	}
+	return b.mb.Info().Verifier().Do(PermUpdate).ObjectOn(obj).SnakeOn("f_" + name).WithReq(evCtx.R).IsAllowed()
+}
+
+func (c *ListingCompo) inlineEditCellComponentFunc(ctx context.Context, f *FieldBuilder) vx.CellComponentFunc {
+	display := c.lb.cellComponentFunc(f)
+	return func(obj interface{}, fieldName string, evCtx *web.EventContext) h.HTMLComponent {
+		compo := display(obj, fieldName, evCtx)
+		if c.lb.inlineEditIsAllowed(evCtx, obj, f.name) != nil {
+			return compo
+		}
+
+		ef := c.lb.mb.editing.getFieldOrDefault(f.name)
+		return web.Scope().VSlot("{ locals: xlocals, form }").Init(`{ editing: false }`).Children(
+			h.Div(
+				h.Div(
+					compo,
+					VBtn("").Icon("mdi-pencil-outline").Variant(VariantText).Size(SizeXSmall).Class("ml-1").
+						Attr("@click", "xlocals.editing = true"),
+				).Attr("v-if", "!xlocals.editing").Class("d-flex align-center"),
+				h.Div(
+					ef.lazyCompFunc().FieldComponent(obj, &FieldContext{
+						ModelInfo: c.lb.mb.Info(),
+						Name:      ef.name,
+						FormKey:   inlineEditFormKey(ObjectID(obj), ef.name),
+						Label:     i18n.PT(evCtx.R, ModelsI18nModuleKey, c.lb.mb.label, c.lb.mb.editing.getLabel(ef.NameLabel)),
+						Context:   ef.context,
+					}, evCtx),
+					VBtn("").Icon("mdi-check").Variant(VariantText).Size(SizeSmall).Color(ColorPrimary).
+						Attr("@click", stateful.PostAction(ctx, c, c.UpdateCell, UpdateCellRequest{
+							ID:    ObjectID(obj),
+							Field: f.name,
+						}).Go()),
+					VBtn("").Icon("mdi-close").Variant(VariantText).Size(SizeSmall).
+						Attr("@click", "xlocals.editing = false"),
+				).Attr("v-else").Class("d-flex align-center"),
+			).Attr("@click.stop", ""),
+		)
+	}
+}
+
+type UpdateCellRequest struct {
+	ID    string `json:"id"`
+	Field string `json:"field"`
+}
+
+// UpdateCell saves the value of an inline editing cell, other listings are refreshed by NotifModelsUpdated.
+func (c *ListingCompo) UpdateCell(ctx context.Context, req UpdateCellRequest) (r web.EventResponse, err error) {
+	if !c.lb.isInlineEditField(req.Field) {
+		return r, errors.Errorf("field %s is not inline editable", req.Field)
+	}
+	evCtx, _ := c.MustGetEventContext(ctx)
+	unprefixInlineEditForm(evCtx.R, req.ID)
+	return c.updateField(ctx, req.ID, req.Field)
+}
+
+const inlineEditFormKeyPrefix = "presets_inline_edit_"
+
+// inlineEditFormKey prefixes the form key of the inline editor with the row id,
+// so that the editors of different rows don't share the same form value.
+func inlineEditFormKey(id string, name string) string {
+	return inlineEditFormKeyPrefix + id + "__" + name
+}
+
+// unprefixInlineEditForm moves the posted values of the inline editors of the row to the field names,
+// the values of the editors of the other rows are dropped.
+func unprefixInlineEditForm(r *http.Request, id string) {
+	rowPrefix := inlineEditFormKey(id, "")
+	unprefix := func(values url.Values) {
+		for k, vs := range values {
+			if !strings.HasPrefix(k, inlineEditFormKeyPrefix) {
+				continue
+			}
+			delete(values, k)
+			if name, ok := strings.CutPrefix(k, rowPrefix); ok {
+				values[name] = vs
+			}
+		}
+	}
+	if r.Form != nil {
+		unprefix(r.Form)
+	}
+	if r.PostForm != nil {
+		unprefix(r.PostForm)
+	}
+	if r.MultipartForm != nil {
+		unprefix(r.MultipartForm.Value)
+	}
+}
+
+// updateField saves the posted value of the editing field of the record with the permission of the field,
+// the ValidateFunc and the SaveFunc of the editing.
+func (c *ListingCompo) updateField(ctx context.Context, id string, field string) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	eb := c.lb.mb.editing
+	obj, err := eb.Fetcher(c.lb.mb.NewModel(), id, evCtx)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			ShowMessage(&r, msgr.RecordNotFound, ColorError)
+			return r, nil
+		}
+		return r, err
+	}
+	if c.lb.inlineEditIsAllowed(evCtx, obj, field) != nil {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+
+	vErr := eb.FieldsBuilder.Only(field).Unmarshal(obj, c.lb.mb.Info(), false, evCtx)
+	if eb.Validator != nil {
+		vErrValidator := eb.Validator(obj, evCtx)
+		_ = vErr.Merge(&vErrValidator)
+	}
+	if vErr.HaveErrors() {
+		msgs := vErr.GetGlobalErrors()
+		for _, fieldErrs := range vErr.FieldErrors() {
+			msgs = append(msgs, fieldErrs...)
+		}
+		ShowMessage(&r, strings.Join(msgs, "; "), ColorError)
+		return r, nil
+	}
+
+	if err = eb.Saver(obj, id, evCtx); err != nil {
+		var ve *web.ValidationErrors
+		if errors.As(err, &ve) {
+			ShowMessage(&r, ve.Error(), ColorError)
+			return r, nil
+		}
+		return r, err
+	}
+
+	r.Emit(
+		c.lb.mb.NotifModelsUpdated(),
+		PayloadModelsUpdated{Ids: []string{id}, Models: map[string]any{id: obj}},
+	)
+	if c.lb.disableModelListeners {
+		web.AppendRunScripts(&r, stateful.ReloadAction(ctx, c, nil).Go())
+	}
+	ShowMessage(&r, msgr.SuccessfullyUpdated, "")
+	return r, nil
+}
+
+func (c *ListingCompo) cellComponentFunc(ctx context.Context, f *FieldBuilder) vx.CellComponentFunc {
+	evCtx, _ := c.MustGetEventContext(ctx)
+	if c.lb.isInlineEditField(f.name) && !c.inTrash(evCtx) {
+		return c.inlineEditCellComponentFunc(ctx, f)
+	}
+	return c.lb.cellComponentFunc(f)
+}
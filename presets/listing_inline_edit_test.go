@@ -0,0 +1,96 @@
+package presets
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListingCompoUpdateCell(t *testing.T) {
+	type Product struct {
+		ID    uint
+		Name  string
+		Price int
+	}
+
+	existing := &Product{ID: 1, Name: "Apple", Price: 3}
+	var saved *Product
+
+	pb := New()
+	mb := pb.Model(&Product{})
+	mb.Listing("Name", "Price").InlineEditFields("Price")
+	mb.Editing("Name", "Price").
+		FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (interface{}, error) {
+			cp := *existing
+			return &cp, nil
+		}).
+		SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) error {
+			saved = obj.(*Product)
+			return nil
+		}).
+		ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+			if obj.(*Product).Price < 0 {
+				err.FieldError("Price", "price must not be negative")
+			}
+			return
+		})
+
+	c := &ListingCompo{lb: mb.Listing()}
+	update := func(field string, value string) (web.EventResponse, error) {
+		body := &bytes.Buffer{}
+		mw := multipart.NewWriter(body)
+		require.NoError(t, mw.WriteField(inlineEditFormKey("1", field), value))
+		require.NoError(t, mw.WriteField(inlineEditFormKey("2", field), "100"))
+		require.NoError(t, mw.Close())
+		r := httptest.NewRequest("POST", "/products", body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		evCtx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+		return c.UpdateCell(web.WrapEventContext(r.Context(), evCtx), UpdateCellRequest{ID: "1", Field: field})
+	}
+
+	_, err := update("Name", "Banana")
+	require.Error(t, err)
+
+	_, err = update("Price", "-1")
+	require.NoError(t, err)
+	assert.Nil(t, saved)
+
+	r, err := update("Price", "5")
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, 5, saved.Price)
+	assert.Equal(t, "Apple", saved.Name)
+	assert.NotEmpty(t, r.RunScript)
+}
+
+func TestListingCompoInlineEditFormKey(t *testing.T) {
+	type Product struct {
+		ID    uint
+		Price int
+	}
+
+	pb := New()
+	mb := pb.Model(&Product{})
+	mb.Listing("Price").InlineEditFields("Price")
+	mb.Editing("Price")
+
+	c := &ListingCompo{lb: mb.Listing(), ID: "products"}
+	r := httptest.NewRequest("GET", "/products", nil)
+	evCtx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+	evCtx.WithContextValue(ctxKeyListingCompo{}, c)
+	ctx := web.WrapEventContext(evCtx.R.Context(), evCtx)
+
+	cell := c.inlineEditCellComponentFunc(ctx, mb.Listing().GetField("Price"))
+	for _, id := range []uint{1, 2} {
+		body, err := cell(&Product{ID: id, Price: 3}, "Price", evCtx).MarshalHTML(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), inlineEditFormKey(fmt.Sprint(id), "Price"))
+	}
+}
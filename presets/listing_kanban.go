@@ -0,0 +1,207 @@
+package presets
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"slices"
+
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/web/v3/stateful"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/sunfmin/reflectutils"
+	h "github.com/theplant/htmlgo"
+)
+
+const kanbanPerColumnDefault = 20
+
+type KanbanColumn struct {
+	// Value is the value of the field of the records in the column.
+	Value any
+	Label string
+	Color string
+}
+
+type KanbanCardFunc func(evCtx *web.EventContext, obj any) h.HTMLComponent
+
+type KanbanBuilder struct {
+	lb          *ListingBuilder
+	field       string
+	column      string
+	columnsFunc func(evCtx *web.EventContext) []*KanbanColumn
+	perColumn   int64
+	cardFunc    KanbanCardFunc
+}
+
+// Kanban lists the records as a board grouped by the field, every column is searched with the listing filters and
+// loads more records on its own. The cards could be dragged to other columns, the field is then set to the value
+// of the column through the editing field with its permission, the ValidateFunc and the SaveFunc of the editing.
+func (b *ListingBuilder) Kanban(field string) (r *KanbanBuilder) {
+	if b.kanban == nil {
+		b.kanban = &KanbanBuilder{lb: b, perColumn: kanbanPerColumnDefault}
+		b.dataTableFunc = b.kanban.dataTable
+	}
+	r = b.kanban
+	r.field = field
+	r.column = strcase.ToSnake(field)
+	return
+}
+
+func (b *ListingBuilder) GetKanban() *KanbanBuilder {
+	return b.kanban
+}
+
+// Column is the database column of the field, defaults to the snake case of the field.
+func (b *KanbanBuilder) Column(v string) (r *KanbanBuilder) {
+	b.column = v
+	return b
+}
+
+// Columns returns the columns of the board in order, the records whose values are in none of them are not listed.
+func (b *KanbanBuilder) Columns(v func(evCtx *web.EventContext) []*KanbanColumn) (r *KanbanBuilder) {
+	b.columnsFunc = v
+	return b
+}
+
+func (b *KanbanBuilder) PerColumn(v int64) (r *KanbanBuilder) {
+	b.perColumn = v
+	return b
+}
+
+// CardFunc renders the content of the cards, defaults to the page title of the record.
+func (b *KanbanBuilder) CardFunc(v KanbanCardFunc) (r *KanbanBuilder) {
+	b.cardFunc = v
+	return b
+}
+
+func (b *KanbanBuilder) dataTable(evCtx *web.EventContext, searchParams *SearchParams, _ *SearchResult, _ h.HTMLComponent) h.HTMLComponent {
+	if b.columnsFunc == nil {
+		panic(errors.Errorf("kanban columns of %s are not set", b.field))
+	}
+	c := ListingCompoFromEventContext(evCtx)
+	ctx := web.WrapEventContext(evCtx.R.Context(), evCtx)
+
+	var columns []h.HTMLComponent
+	for _, col := range b.columnsFunc(evCtx) {
+		key := fmt.Sprint(col.Value)
+		page := max(c.KanbanPages[key], 1)
+		result, err := b.lb.Searcher(evCtx, b.columnSearchParams(searchParams, col, page))
+		if err != nil {
+			panic(errors.Wrap(err, "searcher error"))
+		}
+		columns = append(columns, b.columnComponent(ctx, c, col, key, page, result))
+	}
+
+	return web.Scope(
+		h.Div(columns...).Class("d-flex ga-4 pb-4").Style("overflow-x: auto; align-items: flex-start;"),
+	).VSlot("{ locals: kanbanLocals }").Init(`{ dragging: "", over: "" }`)
+}
+
+func (b *KanbanBuilder) columnSearchParams(searchParams *SearchParams, col *KanbanColumn, page int64) *SearchParams {
+	params := *searchParams
+	params.SQLConditions = append(slices.Clone(searchParams.SQLConditions), &SQLCondition{
+		Query: fmt.Sprintf("%s = ?", b.column),
+		Args:  []interface{}{col.Value},
+	})
+	params.Page = 1
+	params.PerPage = b.perColumn * page
+	params.RelayPagination = nil
+	params.RelayPaginateRequest = nil
+	return &params
+}
+
+func (b *KanbanBuilder) columnComponent(ctx context.Context, c *ListingCompo, col *KanbanColumn, key string, page int64, result *SearchResult) h.HTMLComponent {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+
+	var cards []h.HTMLComponent
+	count := 0
+	reflectutils.ForEach(result.Nodes, func(obj any) {
+		count++
+		cards = append(cards, b.cardComponent(evCtx, c, obj))
+	})
+	total := ""
+	if result.TotalCount != nil {
+		total = fmt.Sprint(*result.TotalCount)
+	}
+	hasMore := result.TotalCount != nil && int64(*result.TotalCount) > b.perColumn*page
+
+	return VCard(
+		VCardTitle(
+			h.Span(col.Label),
+			h.If(total != "", VChip(h.Text(total)).Size(SizeSmall).Color(col.Color).Class("ml-2")),
+		).Class("d-flex align-center text-subtitle-1"),
+		VCardText(
+			h.Div(cards...).Class("d-flex flex-column ga-2"),
+			h.If(count == 0, h.Div(h.Text(msgr.ListingNoRecordToShow)).Class("text-caption text-medium-emphasis text-center py-4")),
+			h.If(hasMore, VBtn(msgr.KanbanLoadMore).Variant(VariantText).Size(SizeSmall).Block(true).Class("mt-2").
+				Attr("@click", stateful.ReloadAction(ctx, c, func(target *ListingCompo) {
+					if target.KanbanPages == nil {
+						target.KanbanPages = map[string]int64{}
+					}
+					target.KanbanPages[key] = page + 1
+				}).Go())),
+		),
+	).Variant(VariantTonal).Color(ColorGreyLighten4).Width(300).MinWidth(300).
+		Attr(":class", fmt.Sprintf(`{ "border-primary border-md": kanbanLocals.over === %q }`, key)).
+		Attr("@dragover.prevent", fmt.Sprintf(`kanbanLocals.over = %q`, key)).
+		Attr("@dragleave", `kanbanLocals.over = ""`).
+		Attr("@drop.prevent", fmt.Sprintf(`kanbanLocals.over = ""; kanbanLocals.dragging && %s`,
+			stateful.PostAction(ctx, c, c.MoveKanbanCard, MoveKanbanCardRequest{Value: key},
+				stateful.WithAppendFix(`v.request.id = kanbanLocals.dragging;`),
+			).ThenScript(`kanbanLocals.dragging = ""`).Go()))
+}
+
+func (b *KanbanBuilder) cardComponent(evCtx *web.EventContext, c *ListingCompo, obj any) h.HTMLComponent {
+	id := ObjectID(obj)
+	var content h.HTMLComponent
+	if b.cardFunc != nil {
+		content = b.cardFunc(evCtx, obj)
+	} else {
+		content = h.Div(h.Text(getPageTitle(obj, id))).Class("text-body-2")
+	}
+
+	card := h.Div(VCard(VCardText(content)).Elevation(1).Class("cursor-pointer"))
+	if b.lb.inlineEditIsAllowed(evCtx, obj, b.field) == nil {
+		card.Attr("draggable", "true").
+			Attr("@dragstart", fmt.Sprintf(`$event.dataTransfer.setData("text/plain", %q); kanbanLocals.dragging = %q`, id, id)).
+			Attr("@dragend", `kanbanLocals.over = ""`)
+	}
+	return c.defaultCellWrapperFunc(evCtx, card, id, obj, "")
+}
+
+func (b *KanbanBuilder) isColumn(evCtx *web.EventContext, value string) bool {
+	if b.columnsFunc == nil {
+		return false
+	}
+	for _, col := range b.columnsFunc(evCtx) {
+		if fmt.Sprint(col.Value) == value {
+			return true
+		}
+	}
+	return false
+}
+
+type MoveKanbanCardRequest struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+}
+
+// MoveKanbanCard sets the field of the kanban to the value of the column the card is dropped in,
+// the values out of the columns are rejected.
+func (c *ListingCompo) MoveKanbanCard(ctx context.Context, req MoveKanbanCardRequest) (r web.EventResponse, err error) {
+	evCtx, _ := c.MustGetEventContext(ctx)
+	kb := c.lb.kanban
+	if kb == nil {
+		return r, errors.New("kanban is not enabled")
+	}
+	if c.lb.mb.editing.GetField(kb.field) == nil {
+		return r, errors.Errorf("field %s is not editable", kb.field)
+	}
+	if !kb.isColumn(evCtx, req.Value) {
+		return r, errors.Errorf("%q is not a kanban column of %s", req.Value, kb.field)
+	}
+	evCtx.R.MultipartForm = &multipart.Form{Value: map[string][]string{kb.field: {req.Value}}}
+	return c.updateField(ctx, req.ID, kb.field)
+}
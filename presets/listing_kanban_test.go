@@ -0,0 +1,95 @@
+package presets
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListingKanban(t *testing.T) {
+	type Task struct {
+		ID     uint
+		Title  string
+		Status string
+	}
+
+	tasks := map[string]*Task{
+		"1": {ID: 1, Title: "Write", Status: "todo"},
+		"2": {ID: 2, Title: "Review", Status: "doing"},
+	}
+	var searched []*SearchParams
+	var saved *Task
+
+	pb := New()
+	mb := pb.Model(&Task{})
+	lb := mb.Listing("Title", "Status").SearchFunc(func(ctx *web.EventContext, params *SearchParams) (*SearchResult, error) {
+		searched = append(searched, params)
+		var nodes []*Task
+		for _, id := range []string{"1", "2"} {
+			if cond := params.SQLConditions; len(cond) > 0 && cond[len(cond)-1].Args[0] == tasks[id].Status {
+				nodes = append(nodes, tasks[id])
+			}
+		}
+		total := len(nodes)
+		return &SearchResult{Nodes: nodes, TotalCount: &total}, nil
+	})
+	lb.Kanban("Status").PerColumn(5).Columns(func(evCtx *web.EventContext) []*KanbanColumn {
+		return []*KanbanColumn{{Value: "todo", Label: "To do"}, {Value: "doing", Label: "Doing"}, {Value: "done", Label: "Done"}}
+	})
+	mb.Editing("Title", "Status").
+		FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (interface{}, error) {
+			cp := *tasks[id]
+			return &cp, nil
+		}).
+		SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) error {
+			saved = obj.(*Task)
+			return nil
+		}).
+		ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+			if obj.(*Task).Status == "doing" && obj.(*Task).Title == "Write" {
+				err.FieldError("Status", "tasks could not be started before the review")
+			}
+			return
+		})
+
+	c := &ListingCompo{lb: lb, ID: "tasks", KanbanPages: map[string]int64{"todo": 2}}
+	newCtx := func() context.Context {
+		r := httptest.NewRequest("POST", "/tasks", nil)
+		evCtx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+		evCtx.WithContextValue(ctxKeyListingCompo{}, c)
+		return web.WrapEventContext(evCtx.R.Context(), evCtx)
+	}
+
+	ctx := newCtx()
+	evCtx := web.MustGetEventContext(ctx)
+	board := lb.dataTableFunc(evCtx, &SearchParams{Model: &Task{}, PerPage: 50, Page: 3}, &SearchResult{}, nil)
+	html, err := board.MarshalHTML(ctx)
+	require.NoError(t, err)
+	require.Len(t, searched, 3)
+	assert.Equal(t, int64(10), searched[0].PerPage)
+	assert.Equal(t, int64(5), searched[1].PerPage)
+	assert.Equal(t, int64(1), searched[1].Page)
+	assert.Equal(t, "status = ?", searched[2].SQLConditions[0].Query)
+	assert.Contains(t, string(html), "To do")
+	assert.Contains(t, string(html), `kanbanLocals.dragging = "2"`)
+	assert.Contains(t, string(html), "MoveKanbanCard")
+
+	_, err = c.MoveKanbanCard(newCtx(), MoveKanbanCardRequest{ID: "1", Value: "archived"})
+	require.Error(t, err)
+	assert.Nil(t, saved)
+
+	_, err = c.MoveKanbanCard(newCtx(), MoveKanbanCardRequest{ID: "1", Value: "doing"})
+	require.NoError(t, err)
+	assert.Nil(t, saved)
+
+	r, err := c.MoveKanbanCard(newCtx(), MoveKanbanCardRequest{ID: "1", Value: "done"})
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, "done", saved.Status)
+	assert.Equal(t, "Write", saved.Title)
+	assert.NotEmpty(t, r.RunScript)
+}
@@ -0,0 +1,294 @@
+package presets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/web/v3/stateful"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/samber/lo"
+	h "github.com/theplant/htmlgo"
+)
+
+const (
+	paramListingViewName       = "listing_view_name"
+	paramListingViewSharedRole = "listing_view_shared_role"
+	paramListingViewPinned     = "listing_view_pinned"
+
+	listingViewTabPrefix = "view_"
+)
+
+// ListingView is a named combination of keyword, filter, columns and sort of a listing,
+// views without UserID are seeded ones which are visible to everyone or the SharedRole.
+type ListingView struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID     string `gorm:"index:idx_listing_views_user_model;not null" json:"user_id"`
+	ModelName  string `gorm:"index:idx_listing_views_user_model;not null" json:"model_name"`
+	Name       string `gorm:"not null" json:"name"`
+	SharedRole string `gorm:"index" json:"shared_role,omitempty"`
+	Pinned     bool   `json:"pinned"`
+
+	Keyword        string           `json:"keyword,omitempty"`
+	FilterQuery    string           `json:"filter_query,omitempty"`
+	DisplayColumns []*DisplayColumn `gorm:"serializer:json" json:"display_columns,omitempty"`
+	OrderBys       []ColOrderBy     `gorm:"serializer:json" json:"order_bys,omitempty"`
+}
+
+// ListingViewStore persists the listing views, see gorm2op.ListingViewStore.
+type ListingViewStore interface {
+	// List returns the views of the user, the views shared to any of the roles and the seeded views.
+	List(ctx context.Context, modelName string, userID string, roles []string) ([]*ListingView, error)
+	// Save creates the view if its ID is 0, otherwise updates the view owned by view.UserID.
+	Save(ctx context.Context, view *ListingView) error
+	Delete(ctx context.Context, modelName string, userID string, id uint) error
+	// Seed creates the views which don't exist yet by model name, user and name, existing ones are kept as is.
+	Seed(ctx context.Context, views ...*ListingView) error
+}
+
+type ListingViewsBuilder struct {
+	lb         *ListingBuilder
+	store      ListingViewStore
+	userIDFunc func(r *http.Request) string
+	rolesFunc  func(r *http.Request) []string
+}
+
+// Views enables editors to save the current keyword, filter, columns and sort of the listing as named views.
+func (b *ListingBuilder) Views(store ListingViewStore) (r *ListingViewsBuilder) {
+	if b.views == nil {
+		b.views = &ListingViewsBuilder{lb: b}
+	}
+	r = b.views
+	r.store = store
+	return
+}
+
+func (b *ListingBuilder) GetViews() *ListingViewsBuilder {
+	return b.views
+}
+
+// UserIDFunc is required, the views are disabled if there is no current user.
+func (b *ListingViewsBuilder) UserIDFunc(v func(r *http.Request) string) (r *ListingViewsBuilder) {
+	b.userIDFunc = v
+	return b
+}
+
+// RolesFunc returns the roles the views could be shared with,
+// defaults to the subjects of the permission builder.
+func (b *ListingViewsBuilder) RolesFunc(v func(r *http.Request) []string) (r *ListingViewsBuilder) {
+	b.rolesFunc = v
+	return b
+}
+
+// Seed creates the default views of the model if they don't exist,
+// views with SharedRole are only visible to the role, otherwise to everyone.
+func (b *ListingViewsBuilder) Seed(ctx context.Context, views ...*ListingView) error {
+	for _, v := range views {
+		v.ID = 0
+		v.UserID = ""
+		v.ModelName = b.lb.mb.uriName
+	}
+	return b.store.Seed(ctx, views...)
+}
+
+func (b *ListingViewsBuilder) userID(r *http.Request) string {
+	if b.userIDFunc == nil {
+		return ""
+	}
+	return b.userIDFunc(r)
+}
+
+func (b *ListingViewsBuilder) roles(r *http.Request) []string {
+	if b.rolesFunc != nil {
+		return b.rolesFunc(r)
+	}
+	if pb := b.lb.mb.p.GetPermission(); pb != nil && pb.GetSubjectsFunc() != nil {
+		return pb.GetSubjectsFunc()(r)
+	}
+	return nil
+}
+
+func (b *ListingViewsBuilder) list(r *http.Request) ([]*ListingView, error) {
+	userID := b.userID(r)
+	if userID == "" {
+		return nil, nil
+	}
+	return b.store.List(r.Context(), b.lb.mb.uriName, userID, b.roles(r))
+}
+
+func listingViewTabID(v *ListingView) string {
+	return fmt.Sprintf("%s%d", listingViewTabPrefix, v.ID)
+}
+
+func (*ListingCompo) applyView(target *ListingCompo, v *ListingView) {
+	target.Page = 0
+	target.After, target.Before = nil, nil
+	target.ActiveFilterTab = listingViewTabID(v)
+	target.Keyword = v.Keyword
+	target.FilterQuery = v.FilterQuery
+	target.DisplayColumns = v.DisplayColumns
+	target.OrderBys = v.OrderBys
+}
+
+func (c *ListingCompo) viewsMenu(ctx context.Context, views []*ListingView) h.HTMLComponent {
+	vb := c.lb.views
+	if vb == nil {
+		return nil
+	}
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	userID := vb.userID(evCtx.R)
+	if userID == "" {
+		return nil
+	}
+
+	items := []h.HTMLComponent{
+		VListItem(
+			VListItemTitle(h.Text(msgr.ListingViewSave)),
+		).PrependIcon("mdi-content-save-outline").
+			Attr("@click", stateful.PostAction(ctx, c, c.OpenSaveViewDialog, OpenSaveViewDialogRequest{}).Go()),
+	}
+	if len(views) > 0 {
+		items = append(items, VDivider())
+	}
+	for _, v := range views {
+		items = append(items, VListItem(
+			VListItemTitle(h.Text(v.Name)),
+			h.Iff(v.UserID == userID, func() h.HTMLComponent {
+				return web.Slot(
+					VBtn("").Icon("mdi-delete-outline").Variant(VariantText).Size(SizeSmall).
+						Attr("@click.stop", stateful.PostAction(ctx, c, c.DeleteView, DeleteViewRequest{ID: v.ID}).Go()),
+				).Name("append")
+			}),
+		).Active(c.ActiveFilterTab == listingViewTabID(v)).
+			PrependIcon(lo.Ternary(v.Pinned, "mdi-pin", "mdi-eye-outline")).
+			Attr("@click", stateful.ReloadAction(ctx, c, func(target *ListingCompo) {
+				c.applyView(target, v)
+			}).ThenScript(c.JsScrollToTop()).Go()))
+	}
+
+	return VMenu().Children(
+		web.Slot().Name("activator").Scope("{ props }").Children(
+			VBtn(msgr.ListingViews).Attr("v-bind", "props").
+				Variant(VariantText).PrependIcon("mdi-view-list-outline").Class("ml-2"),
+		),
+		VList(items...).Density(DensityCompact),
+	)
+}
+
+type OpenSaveViewDialogRequest struct{}
+
+func (c *ListingCompo) OpenSaveViewDialog(ctx context.Context, _ OpenSaveViewDialogRequest) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	vb := c.lb.views
+	if vb == nil || vb.userID(evCtx.R) == "" {
+		return r, errors.New("listing views are not available")
+	}
+
+	roles := vb.roles(evCtx.R)
+	c.dialog(&r, VCard(
+		VCardTitle(h.Text(msgr.ListingViewSave)),
+		VCardText(
+			VTextField().Label(msgr.ListingViewName).Variant(VariantOutlined).Density(DensityCompact).
+				Attr(web.VField(paramListingViewName, "")...),
+			h.Iff(len(roles) > 0, func() h.HTMLComponent {
+				return VSelect().Label(msgr.ListingViewSharedRole).Items(roles).Clearable(true).
+					Variant(VariantOutlined).Density(DensityCompact).
+					Attr(web.VField(paramListingViewSharedRole, "")...)
+			}),
+			VCheckbox().Label(msgr.ListingViewPinned).Density(DensityCompact).
+				Attr(web.VField(paramListingViewPinned, false)...),
+		),
+		VCardActions(
+			VSpacer(),
+			VBtn(msgr.Cancel).Variant(VariantFlat).Class("ml-2").Attr("@click", c.closeActionDialog()),
+			VBtn(msgr.Save).Color(ColorPrimary).Variant(VariantFlat).Theme(ThemeDark).
+				Attr("@click", stateful.PostAction(ctx, c, c.SaveView, SaveViewRequest{}).Go()),
+		),
+	), "480")
+	return r, nil
+}
+
+type SaveViewRequest struct{}
+
+// SaveView saves the current state of the listing as a view of the current user.
+func (c *ListingCompo) SaveView(ctx context.Context, _ SaveViewRequest) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	vb := c.lb.views
+	if vb == nil {
+		return r, errors.New("listing views are not enabled")
+	}
+	userID := vb.userID(evCtx.R)
+	if userID == "" {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+
+	name := strings.TrimSpace(evCtx.R.FormValue(paramListingViewName))
+	if name == "" {
+		ShowMessage(&r, msgr.ListingViewNameRequired, ColorError)
+		return r, nil
+	}
+	sharedRole := evCtx.R.FormValue(paramListingViewSharedRole)
+	if sharedRole != "" && !lo.Contains(vb.roles(evCtx.R), sharedRole) {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+
+	view := &ListingView{
+		UserID:         userID,
+		ModelName:      c.lb.mb.uriName,
+		Name:           name,
+		SharedRole:     sharedRole,
+		Pinned:         evCtx.R.FormValue(paramListingViewPinned) == "true",
+		Keyword:        c.Keyword,
+		FilterQuery:    c.FilterQuery,
+		DisplayColumns: c.DisplayColumns,
+		OrderBys:       c.OrderBys,
+	}
+	if err = vb.store.Save(ctx, view); err != nil {
+		return r, err
+	}
+
+	web.AppendRunScripts(&r,
+		c.closeActionDialog(),
+		stateful.ReloadAction(ctx, c, func(target *ListingCompo) {
+			target.ActiveFilterTab = listingViewTabID(view)
+		}).Go(),
+	)
+	ShowMessage(&r, msgr.SuccessfullyCreated, "")
+	return r, nil
+}
+
+type DeleteViewRequest struct {
+	ID uint `json:"id"`
+}
+
+func (c *ListingCompo) DeleteView(ctx context.Context, req DeleteViewRequest) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	vb := c.lb.views
+	if vb == nil {
+		return r, errors.New("listing views are not enabled")
+	}
+	userID := vb.userID(evCtx.R)
+	if userID == "" {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+
+	if err = vb.store.Delete(ctx, c.lb.mb.uriName, userID, req.ID); err != nil {
+		return r, err
+	}
+	web.AppendRunScripts(&r, stateful.ReloadAction(ctx, c, func(target *ListingCompo) {
+		if target.ActiveFilterTab == listingViewTabID(&ListingView{ID: req.ID}) {
+			target.ActiveFilterTab = ""
+		}
+	}).Go())
+	return r, nil
+}
@@ -14,6 +14,8 @@ type Messages struct {
 	SuccessfullyUpdated                        string
 	SuccessfullyCreated                        string
 	Search                                     string
+	SearchResults                              string
+	SearchNoResults                            string
 	New                                        string
 	Update                                     string
 	Delete                                     string
@@ -21,6 +23,7 @@ type Messages struct {
 	FormTitle                                  string
 	OK                                         string
 	Cancel                                     string
+	Close                                      string
 	Clear                                      string
 	Create                                     string
 	SelectedTemplate                           func(v any) string
@@ -77,6 +80,70 @@ type Messages struct {
 	AddButton                                  string
 	CheckboxTrueLabel                          string
 	CheckboxFalseLabel                         string
+	ListingExport                              string
+	ListingExportJobStarted                    string
+	ListingImport                              string
+	ListingImportFile                          string
+	ListingImportValidate                      string
+	ListingImportNoFile                        string
+	ListingImportUnknownColumnsTemplate        string
+	ListingImportSummaryTemplate               string
+	ListingImportFinishedTemplate              string
+	ListingImportLine                          string
+	ListingImportErrors                        string
+	ListingViews                               string
+	ListingViewSave                            string
+	ListingViewName                            string
+	ListingViewNameRequired                    string
+	ListingViewSharedRole                      string
+	ListingViewPinned                          string
+	ListingImportJobStarted                    string
+	EditingConflict                            string
+	EditingConflictField                       string
+	EditingConflictTheirs                      string
+	EditingConflictMine                        string
+	EditingConflictReload                      string
+	EditingConflictForceSave                   string
+	Trash                                      string
+	TrashRestore                               string
+	TrashPurge                                 string
+	TrashPurgeConfirmationTemplate             string
+	SuccessfullyRestored                       string
+	SuccessfullyPurged                         string
+	Dashboard                                  string
+	DashboardAddWidget                         string
+	DashboardNoWidgets                         string
+	DashboardMoveLeft                          string
+	DashboardMoveRight                         string
+	DashboardResize                            string
+	DashboardRemove                            string
+	EditingDraftFoundTemplate                  string
+	EditingDraftRestore                        string
+	EditingDraftDiscard                        string
+	EditingUndo                                string
+	EditingRedo                                string
+	CompareRecordsCountTemplate                string
+	CompareField                               string
+	CompareOnlyDifferences                     string
+	BulkEditFields                             string
+	BulkEditSelectedRecordsTemplate            string
+	BulkEditNoFields                           string
+	BulkEditFailedTemplate                     string
+	BulkEditFailedRolledBackTemplate           string
+	AggregateSum                               string
+	AggregateAvg                               string
+	AggregateMin                               string
+	AggregateMax                               string
+	AggregateCountDistinct                     string
+	KanbanLoadMore                             string
+	CalendarToday                              string
+	CalendarMonth                              string
+	CalendarWeek                               string
+	CalendarDay                                string
+	CalendarMoreRecordsTemplate                string
+	CalendarWeekdays                           string
+	CalendarMonthTitleLayout                   string
+	CalendarDayTitleLayout                     string
 
 	HumanizeTimeAgo       string
 	HumanizeTimeFromNow   string
@@ -129,6 +196,72 @@ func (msgr *Messages) BulkActionSelectedIdsProcessNotice(ids string) string {
 		Replace(msgr.BulkActionSelectedIdsProcessNoticeTemplate)
 }
 
+func (msgr *Messages) ListingImportUnknownColumns(columns string) string {
+	return strings.NewReplacer("{columns}", columns).
+		Replace(msgr.ListingImportUnknownColumnsTemplate)
+}
+
+func (msgr *Messages) TrashPurgeConfirmation(count int) string {
+	return strings.NewReplacer("{count}", fmt.Sprint(count)).
+		Replace(msgr.TrashPurgeConfirmationTemplate)
+}
+
+func (msgr *Messages) ListingImportSummary(create, update, invalid int) string {
+	return strings.NewReplacer("{create}", fmt.Sprint(create), "{update}", fmt.Sprint(update), "{invalid}", fmt.Sprint(invalid)).
+		Replace(msgr.ListingImportSummaryTemplate)
+}
+
+func (msgr *Messages) ListingImportFinished(create, update, invalid int) string {
+	return strings.NewReplacer("{create}", fmt.Sprint(create), "{update}", fmt.Sprint(update), "{invalid}", fmt.Sprint(invalid)).
+		Replace(msgr.ListingImportFinishedTemplate)
+}
+
+func (msgr *Messages) EditingDraftFound(time string) string {
+	return strings.NewReplacer("{time}", time).
+		Replace(msgr.EditingDraftFoundTemplate)
+}
+
+func (msgr *Messages) CalendarMoreRecords(shown, total int) string {
+	return strings.NewReplacer("{shown}", fmt.Sprint(shown), "{total}", fmt.Sprint(total)).
+		Replace(msgr.CalendarMoreRecordsTemplate)
+}
+
+// CalendarWeekday returns the short name of the weekday from CalendarWeekdays, which lists them from Sunday separated by commas.
+func (msgr *Messages) CalendarWeekday(d time.Weekday) string {
+	names := strings.Split(msgr.CalendarWeekdays, ",")
+	if int(d) >= len(names) {
+		return d.String()[:3]
+	}
+	return names[d]
+}
+
+// CalendarTitle formats the date with the time.Format layout, like CalendarMonthTitleLayout,
+// {weekday} in the layout is replaced with the CalendarWeekday.
+func (msgr *Messages) CalendarTitle(date time.Time, layout string) string {
+	return strings.NewReplacer("{weekday}", msgr.CalendarWeekday(date.Weekday())).
+		Replace(date.Format(layout))
+}
+
+func (msgr *Messages) CompareRecordsCount(max int) string {
+	return strings.NewReplacer("{max}", fmt.Sprint(max)).
+		Replace(msgr.CompareRecordsCountTemplate)
+}
+
+func (msgr *Messages) BulkEditSelectedRecords(count int) string {
+	return strings.NewReplacer("{count}", fmt.Sprint(count)).
+		Replace(msgr.BulkEditSelectedRecordsTemplate)
+}
+
+func (msgr *Messages) BulkEditFailed(count int) string {
+	return strings.NewReplacer("{count}", fmt.Sprint(count)).
+		Replace(msgr.BulkEditFailedTemplate)
+}
+
+func (msgr *Messages) BulkEditFailedRolledBack(count int) string {
+	return strings.NewReplacer("{count}", fmt.Sprint(count)).
+		Replace(msgr.BulkEditFailedRolledBackTemplate)
+}
+
 func (msgr *Messages) FilterBy(filter string) string {
 	return strings.NewReplacer("{filter}", filter).
 		Replace(msgr.FilterByTemplate)
@@ -163,6 +296,8 @@ var Messages_en_US = &Messages{
 	SuccessfullyUpdated: "Successfully Updated",
 	SuccessfullyCreated: "Successfully Created",
 	Search:              "Search",
+	SearchResults:       "Search results",
+	SearchNoResults:     "No results found",
 	New:                 "New",
 	Update:              "Update",
 	Delete:              "Delete",
@@ -170,6 +305,7 @@ var Messages_en_US = &Messages{
 	FormTitle:           "Form",
 	OK:                  "OK",
 	Cancel:              "Cancel",
+	Close:               "Close",
 	Clear:               "Clear",
 	Create:              "Create",
 	SelectedTemplate: func(v any) string {
@@ -230,6 +366,70 @@ var Messages_en_US = &Messages{
 	AddButton:                                  "Add Button",
 	CheckboxTrueLabel:                          "YES",
 	CheckboxFalseLabel:                         "NO",
+	ListingExport:                              "Export",
+	ListingExportJobStarted:                    "The export is running in the background, check the workers for the file.",
+	ListingImport:                              "Import",
+	ListingImportFile:                          "CSV File",
+	ListingImportValidate:                      "Validate",
+	ListingImportNoFile:                        "Please choose a CSV file.",
+	ListingImportUnknownColumnsTemplate:        "These columns will not be imported: {columns}",
+	ListingImportSummaryTemplate:               "{create} to create, {update} to update, {invalid} invalid.",
+	ListingImportFinishedTemplate:              "{create} created, {update} updated, {invalid} skipped.",
+	ListingImportLine:                          "Line",
+	ListingImportErrors:                        "Errors",
+	ListingImportJobStarted:                    "The import is running in the background, check the workers for the progress.",
+	ListingViews:                               "Views",
+	ListingViewSave:                            "Save current view",
+	ListingViewName:                            "Name",
+	ListingViewNameRequired:                    "Name is required",
+	ListingViewSharedRole:                      "Share with role",
+	ListingViewPinned:                          "Pin as tab",
+	EditingConflict:                            "This record was changed by someone else after you opened it.",
+	EditingConflictField:                       "Field",
+	EditingConflictTheirs:                      "Saved value",
+	EditingConflictMine:                        "Your value",
+	EditingConflictReload:                      "Reload",
+	EditingConflictForceSave:                   "Save anyway",
+	Trash:                                      "Trash",
+	TrashRestore:                               "Restore",
+	TrashPurge:                                 "Delete Permanently",
+	TrashPurgeConfirmationTemplate:             "Are you sure you want to permanently delete {count} record(s)? This cannot be undone.",
+	SuccessfullyRestored:                       "Successfully Restored",
+	SuccessfullyPurged:                         "Successfully Deleted Permanently",
+	Dashboard:                                  "Dashboard",
+	DashboardAddWidget:                         "Add widget",
+	DashboardNoWidgets:                         "No widgets yet, add some to your dashboard.",
+	DashboardMoveLeft:                          "Move left",
+	DashboardMoveRight:                         "Move right",
+	DashboardResize:                            "Resize",
+	DashboardRemove:                            "Remove",
+	EditingDraftFoundTemplate:                  "You have an unsaved draft from {time}.",
+	EditingDraftRestore:                        "Restore",
+	EditingDraftDiscard:                        "Discard",
+	EditingUndo:                                "Undo",
+	EditingRedo:                                "Redo",
+	CompareRecordsCountTemplate:                "Select 2 to {max} records to compare.",
+	CompareField:                               "Field",
+	CompareOnlyDifferences:                     "Only differences",
+	BulkEditFields:                             "Fields to change",
+	BulkEditSelectedRecordsTemplate:            "{count} records selected",
+	BulkEditNoFields:                           "Please choose the fields to change",
+	BulkEditFailedTemplate:                     "{count} records failed, the others were saved.",
+	BulkEditFailedRolledBackTemplate:           "{count} records failed, no records were saved.",
+	AggregateSum:                               "Sum",
+	AggregateAvg:                               "Average",
+	AggregateMin:                               "Min",
+	AggregateMax:                               "Max",
+	AggregateCountDistinct:                     "Distinct",
+	KanbanLoadMore:                             "Load more",
+	CalendarToday:                              "Today",
+	CalendarMonth:                              "Month",
+	CalendarWeek:                               "Week",
+	CalendarDay:                                "Day",
+	CalendarMoreRecordsTemplate:                "Only {shown} of {total} records are shown, narrow down the filters to see the rest.",
+	CalendarWeekdays:                           "Sun,Mon,Tue,Wed,Thu,Fri,Sat",
+	CalendarMonthTitleLayout:                   "January 2006",
+	CalendarDayTitleLayout:                     "Monday, January 2, 2006",
 
 	HumanizeTimeAgo:       "ago",
 	HumanizeTimeFromNow:   "from now",
@@ -262,6 +462,8 @@ var Messages_zh_CN = &Messages{
 	SuccessfullyUpdated: "成功更新了",
 	SuccessfullyCreated: "成功创建了",
 	Search:              "搜索",
+	SearchResults:       "搜索结果",
+	SearchNoResults:     "没有找到结果",
 	New:                 "新建",
 	Update:              "更新",
 	Delete:              "删除",
@@ -269,6 +471,7 @@ var Messages_zh_CN = &Messages{
 	FormTitle:           "表单",
 	OK:                  "确定",
 	Cancel:              "取消",
+	Close:               "关闭",
 	Clear:               "清空",
 	Create:              "创建",
 	SelectedTemplate: func(v any) string {
@@ -329,6 +532,70 @@ var Messages_zh_CN = &Messages{
 	AddButton:                                  "新增按钮",
 	CheckboxTrueLabel:                          "是",
 	CheckboxFalseLabel:                         "否",
+	ListingExport:                              "导出",
+	ListingExportJobStarted:                    "导出正在后台运行，请在任务列表中查看文件。",
+	ListingImport:                              "导入",
+	ListingImportFile:                          "CSV 文件",
+	ListingImportValidate:                      "校验",
+	ListingImportNoFile:                        "请选择 CSV 文件。",
+	ListingImportUnknownColumnsTemplate:        "以下列不会被导入：{columns}",
+	ListingImportSummaryTemplate:               "将新建 {create} 条，更新 {update} 条，无效 {invalid} 条。",
+	ListingImportFinishedTemplate:              "已新建 {create} 条，更新 {update} 条，跳过 {invalid} 条。",
+	ListingImportLine:                          "行",
+	ListingImportErrors:                        "错误",
+	ListingImportJobStarted:                    "导入正在后台运行，请在任务列表中查看进度。",
+	ListingViews:                               "视图",
+	ListingViewSave:                            "保存当前视图",
+	ListingViewName:                            "名称",
+	ListingViewNameRequired:                    "名称不能为空",
+	ListingViewSharedRole:                      "共享给角色",
+	ListingViewPinned:                          "固定为标签页",
+	EditingConflict:                            "该记录在您打开后已被他人修改。",
+	EditingConflictField:                       "字段",
+	EditingConflictTheirs:                      "已保存的值",
+	EditingConflictMine:                        "您的值",
+	EditingConflictReload:                      "重新加载",
+	EditingConflictForceSave:                   "仍然保存",
+	Trash:                                      "回收站",
+	TrashRestore:                               "恢复",
+	TrashPurge:                                 "永久删除",
+	TrashPurgeConfirmationTemplate:             "你确定要永久删除这 {count} 条记录吗？此操作无法撤销。",
+	SuccessfullyRestored:                       "成功恢复",
+	SuccessfullyPurged:                         "成功永久删除",
+	Dashboard:                                  "仪表盘",
+	DashboardAddWidget:                         "添加组件",
+	DashboardNoWidgets:                         "还没有组件，请添加到你的仪表盘。",
+	DashboardMoveLeft:                          "左移",
+	DashboardMoveRight:                         "右移",
+	DashboardResize:                            "调整宽度",
+	DashboardRemove:                            "移除",
+	EditingDraftFoundTemplate:                  "你有一份{time}未保存的草稿。",
+	EditingDraftRestore:                        "恢复",
+	EditingDraftDiscard:                        "丢弃",
+	EditingUndo:                                "撤销",
+	EditingRedo:                                "重做",
+	CompareRecordsCountTemplate:                "请选择 2 到 {max} 条记录进行对比。",
+	CompareField:                               "字段",
+	CompareOnlyDifferences:                     "仅显示差异",
+	BulkEditFields:                             "要修改的字段",
+	BulkEditSelectedRecordsTemplate:            "已选择 {count} 条记录",
+	BulkEditNoFields:                           "请选择要修改的字段",
+	BulkEditFailedTemplate:                     "{count} 条记录失败，其他记录已保存。",
+	BulkEditFailedRolledBackTemplate:           "{count} 条记录失败，所有记录均未保存。",
+	AggregateSum:                               "合计",
+	AggregateAvg:                               "平均",
+	AggregateMin:                               "最小",
+	AggregateMax:                               "最大",
+	AggregateCountDistinct:                     "去重计数",
+	KanbanLoadMore:                             "加载更多",
+	CalendarToday:                              "今天",
+	CalendarMonth:                              "月",
+	CalendarWeek:                               "周",
+	CalendarDay:                                "日",
+	CalendarMoreRecordsTemplate:                "仅显示了{total}条记录中的{shown}条，请缩小筛选范围查看其余记录。",
+	CalendarWeekdays:                           "日,一,二,三,四,五,六",
+	CalendarMonthTitleLayout:                   "2006年1月",
+	CalendarDayTitleLayout:                     "2006年1月2日 星期{weekday}",
 
 	HumanizeTimeAgo:       "前",
 	HumanizeTimeFromNow:   "后",
@@ -361,6 +628,8 @@ var Messages_ja_JP = &Messages{
 	SuccessfullyUpdated: "更新に成功しました",
 	SuccessfullyCreated: "作成に成功しました",
 	Search:              "検索",
+	SearchResults:       "検索結果",
+	SearchNoResults:     "結果が見つかりません",
 	New:                 "作成する",
 	Update:              "更新",
 	Delete:              "削除",
@@ -368,6 +637,7 @@ var Messages_ja_JP = &Messages{
 	FormTitle:           "フォーム",
 	OK:                  "OK",
 	Cancel:              "キャンセル",
+	Close:               "閉じる",
 	Clear:               "消去する",
 	Create:              "作成する",
 	SelectedTemplate: func(v any) string {
@@ -428,6 +698,70 @@ var Messages_ja_JP = &Messages{
 	AddButton:                                  "ボタンを追加",
 	CheckboxTrueLabel:                          "選択済み",
 	CheckboxFalseLabel:                         "未選択",
+	ListingExport:                              "エクスポート",
+	ListingExportJobStarted:                    "エクスポートはバックグラウンドで実行中です。ファイルはワーカーで確認してください。",
+	ListingImport:                              "インポート",
+	ListingImportFile:                          "CSV ファイル",
+	ListingImportValidate:                      "検証",
+	ListingImportNoFile:                        "CSV ファイルを選択してください。",
+	ListingImportUnknownColumnsTemplate:        "次の列はインポートされません：{columns}",
+	ListingImportSummaryTemplate:               "新規作成 {create} 件、更新 {update} 件、無効 {invalid} 件。",
+	ListingImportFinishedTemplate:              "新規作成 {create} 件、更新 {update} 件、スキップ {invalid} 件。",
+	ListingImportLine:                          "行",
+	ListingImportErrors:                        "エラー",
+	ListingImportJobStarted:                    "インポートはバックグラウンドで実行中です。進捗はワーカーで確認してください。",
+	ListingViews:                               "ビュー",
+	ListingViewSave:                            "現在のビューを保存",
+	ListingViewName:                            "名前",
+	ListingViewNameRequired:                    "名前は必須です",
+	ListingViewSharedRole:                      "共有するロール",
+	ListingViewPinned:                          "タブとして固定",
+	EditingConflict:                            "このレコードは開いた後に他のユーザーによって変更されました。",
+	EditingConflictField:                       "フィールド",
+	EditingConflictTheirs:                      "保存済みの値",
+	EditingConflictMine:                        "あなたの値",
+	EditingConflictReload:                      "再読み込み",
+	EditingConflictForceSave:                   "強制的に保存",
+	Trash:                                      "ゴミ箱",
+	TrashRestore:                               "復元",
+	TrashPurge:                                 "完全に削除",
+	TrashPurgeConfirmationTemplate:             "{count} 件のレコードを完全に削除してもよろしいですか？この操作は元に戻せません。",
+	SuccessfullyRestored:                       "復元に成功しました",
+	SuccessfullyPurged:                         "完全に削除しました",
+	Dashboard:                                  "ダッシュボード",
+	DashboardAddWidget:                         "ウィジェットを追加",
+	DashboardNoWidgets:                         "ウィジェットがありません。ダッシュボードに追加してください。",
+	DashboardMoveLeft:                          "左へ移動",
+	DashboardMoveRight:                         "右へ移動",
+	DashboardResize:                            "サイズ変更",
+	DashboardRemove:                            "削除",
+	EditingDraftFoundTemplate:                  "{time}の未保存の下書きがあります。",
+	EditingDraftRestore:                        "復元",
+	EditingDraftDiscard:                        "破棄",
+	EditingUndo:                                "元に戻す",
+	EditingRedo:                                "やり直す",
+	CompareRecordsCountTemplate:                "比較するレコードを 2 件から {max} 件まで選択してください。",
+	CompareField:                               "フィールド",
+	CompareOnlyDifferences:                     "差分のみ表示",
+	BulkEditFields:                             "変更するフィールド",
+	BulkEditSelectedRecordsTemplate:            "{count} 件のレコードを選択中",
+	BulkEditNoFields:                           "変更するフィールドを選択してください",
+	BulkEditFailedTemplate:                     "{count} 件のレコードが失敗しました。その他のレコードは保存されました。",
+	BulkEditFailedRolledBackTemplate:           "{count} 件のレコードが失敗しました。レコードは保存されていません。",
+	AggregateSum:                               "合計",
+	AggregateAvg:                               "平均",
+	AggregateMin:                               "最小",
+	AggregateMax:                               "最大",
+	AggregateCountDistinct:                     "ユニーク数",
+	KanbanLoadMore:                             "さらに読み込む",
+	CalendarToday:                              "今日",
+	CalendarMonth:                              "月",
+	CalendarWeek:                               "週",
+	CalendarDay:                                "日",
+	CalendarMoreRecordsTemplate:                "{total}件中{shown}件のみ表示しています。残りを表示するにはフィルターを絞り込んでください。",
+	CalendarWeekdays:                           "日,月,火,水,木,金,土",
+	CalendarMonthTitleLayout:                   "2006年1月",
+	CalendarDayTitleLayout:                     "2006年1月2日({weekday})",
 
 	HumanizeTimeAgo:       "前",
 	HumanizeTimeFromNow:   "今後",
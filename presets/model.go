@@ -36,6 +36,11 @@ type ModelBuilder struct {
 	detailing           *DetailingBuilder
 	editing             *EditingBuilder
 	creating            *EditingBuilder
+	importing           *ImportBuilder
+	trash               *TrashBuilder
+	rowScope            RowScopeFunc
+	globalSearchOff     bool
+	globalSearchHitFunc GlobalSearchHitFunc
 	writeFields         *FieldsBuilder
 	hasDetailing        bool
 	rightDrawerWidth    string
@@ -112,6 +117,9 @@ func (mb *ModelBuilder) registerDefaultEventFuncs() {
 	mb.RegisterEventFunc(actions.Validate, mb.editing.doValidate)
 	mb.RegisterEventFunc(actions.Update, mb.editing.defaultUpdate)
 	mb.RegisterEventFunc(actions.DoDelete, mb.editing.doDelete)
+	mb.RegisterEventFunc(actions.SaveDraft, mb.doSaveDraft)
+	mb.RegisterEventFunc(actions.RestoreDraft, mb.editing.doRestoreDraft)
+	mb.RegisterEventFunc(actions.DiscardDraft, mb.doDiscardDraft)
 
 	mb.RegisterEventFunc(actions.Action, mb.detailing.openActionDialog)
 	mb.RegisterEventFunc(actions.DoAction, mb.detailing.doAction)
@@ -0,0 +1,548 @@
+package presets
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"strings"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/web/v3/stateful"
+	"github.com/qor5/x/v3/i18n"
+	"github.com/qor5/x/v3/perm"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	vx "github.com/qor5/x/v3/ui/vuetifyx"
+	"github.com/samber/lo"
+	h "github.com/theplant/htmlgo"
+)
+
+const (
+	paramImportFile = "import_file"
+
+	importDefaultBatchSize = 100
+	importMaxReportRows    = 100
+)
+
+// ImportJobFunc hands the import over to a background runner, see ImportBuilder.JobHandoff.
+type ImportJobFunc func(evCtx *web.EventContext, req *ImportRequest, r *web.EventResponse) (err error)
+
+// ImportTransactFunc runs f in a transaction which is passed to the savers through the context of txCtx,
+// see ImportBuilder.Transact.
+type ImportTransactFunc func(evCtx *web.EventContext, f func(txCtx *web.EventContext) error) (err error)
+
+var errImportRowInvalid = errors.New("import row invalid")
+
+// ImportRequest is the parsed csv, it is serializable so that a worker job can resume it.
+type ImportRequest struct {
+	// Fields are the editing fields of the csv columns, ParamID for the primary key/slug column and empty for skipped columns
+	Fields  []string   `json:"fields"`
+	Records [][]string `json:"records"`
+}
+
+type ImportRowResult struct {
+	// Line is the line in the csv file, the header is line 1
+	Line   int      `json:"line"`
+	ID     string   `json:"id,omitempty"`
+	Create bool     `json:"create"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+type ImportReport struct {
+	Create  int                `json:"create"`
+	Update  int                `json:"update"`
+	Invalid []*ImportRowResult `json:"invalid,omitempty"`
+}
+
+func (r *ImportReport) add(rows ...*ImportRowResult) {
+	for _, row := range rows {
+		switch {
+		case len(row.Errors) > 0:
+			r.Invalid = append(r.Invalid, row)
+		case row.Create:
+			r.Create++
+		default:
+			r.Update++
+		}
+	}
+}
+
+type ImportBuilder struct {
+	mb           *ModelBuilder
+	fields       []string
+	idColumn     string
+	batchSize    int
+	jobThreshold int
+	jobFunc      ImportJobFunc
+	transact     ImportTransactFunc
+}
+
+// Import enables importing csv files into the model, the columns are matched with the fields by name or label.
+// All the editing fields which are not nested are importable if no fields given.
+// Rows are upserted by the ID column, rows whose ID is empty or not found are created.
+func (mb *ModelBuilder) Import(fields ...string) (r *ImportBuilder) {
+	if mb.importing == nil {
+		mb.importing = &ImportBuilder{
+			mb:        mb,
+			idColumn:  "ID",
+			batchSize: importDefaultBatchSize,
+		}
+	}
+	r = mb.importing
+	if len(fields) > 0 {
+		r.fields = fields
+	}
+	return
+}
+
+func (mb *ModelBuilder) GetImport() *ImportBuilder {
+	return mb.importing
+}
+
+func (b *ImportBuilder) GetModelBuilder() *ModelBuilder {
+	return b.mb
+}
+
+// IDColumn is the header of the csv column which holds the primary key or slug, "ID" by default.
+func (b *ImportBuilder) IDColumn(v string) (r *ImportBuilder) {
+	b.idColumn = v
+	return b
+}
+
+func (b *ImportBuilder) BatchSize(v int) (r *ImportBuilder) {
+	if v <= 0 {
+		v = importDefaultBatchSize
+	}
+	b.batchSize = v
+	return b
+}
+
+// Transact saves every batch in a transaction of f, f is called again inside it for every row,
+// so it should nest the transaction (a savepoint) to keep an invalid row from aborting the batch.
+func (b *ImportBuilder) Transact(f ImportTransactFunc) (r *ImportBuilder) {
+	b.transact = f
+	return b
+}
+
+func (b *ImportBuilder) GetTransact() ImportTransactFunc {
+	return b.transact
+}
+
+// JobHandoff calls f instead of importing in the current request when the csv has more than threshold rows,
+// a threshold <= 0 hands off every import.
+func (b *ImportBuilder) JobHandoff(threshold int, f ImportJobFunc) (r *ImportBuilder) {
+	b.jobThreshold = threshold
+	b.jobFunc = f
+	return b
+}
+
+func (b *ImportBuilder) isAllowed(evCtx *web.EventContext) error {
+	if b.mb.Info().Verifier().Do(PermImport).WithReq(evCtx.R).IsAllowed() != nil {
+		return perm.PermissionDenied
+	}
+	if b.mb.Info().Verifier().Do(PermCreate).WithReq(evCtx.R).IsAllowed() != nil &&
+		b.mb.Info().Verifier().Do(PermUpdate).WithReq(evCtx.R).IsAllowed() != nil {
+		return perm.PermissionDenied
+	}
+	return nil
+}
+
+func (b *ImportBuilder) fieldNames() []string {
+	if len(b.fields) > 0 {
+		return b.fields
+	}
+	var names []string
+	for _, f := range b.mb.editing.fields {
+		if f.nestedFieldsBuilder != nil {
+			continue
+		}
+		names = append(names, f.name)
+	}
+	return names
+}
+
+func (b *ImportBuilder) matchField(evCtx *web.EventContext, column string) string {
+	if strings.EqualFold(column, b.idColumn) {
+		return ParamID
+	}
+	for _, name := range b.fieldNames() {
+		label := b.mb.getLabel(NameLabel{name: name})
+		if f := b.mb.editing.GetField(name); f != nil {
+			label = b.mb.editing.getLabel(f.NameLabel)
+		}
+		if strings.EqualFold(column, name) || strings.EqualFold(column, label) ||
+			strings.EqualFold(column, i18n.PT(evCtx.R, ModelsI18nModuleKey, b.mb.label, label)) {
+			return name
+		}
+	}
+	return ""
+}
+
+// ParseCSV reads the csv with a header line, unknown returns the headers which don't match any field.
+func (b *ImportBuilder) ParseCSV(evCtx *web.EventContext, r io.Reader) (req *ImportRequest, unknown []string, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("csv has no header")
+		}
+		return nil, nil, err
+	}
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req = &ImportRequest{
+		Fields:  make([]string, len(header)),
+		Records: records,
+	}
+	for i, column := range header {
+		if i == 0 {
+			column = strings.TrimPrefix(column, "\uFEFF")
+		}
+		column = strings.TrimSpace(column)
+		req.Fields[i] = b.matchField(evCtx, column)
+		if req.Fields[i] == "" {
+			unknown = append(unknown, column)
+		}
+	}
+	return req, unknown, nil
+}
+
+func (*ImportBuilder) rowContext(evCtx *web.EventContext, req *ImportRequest, record []string) (rowCtx *web.EventContext, id string) {
+	vals := url.Values{}
+	for i, name := range req.Fields {
+		if name == "" || i >= len(record) {
+			continue
+		}
+		if name == ParamID {
+			id = strings.TrimSpace(record[i])
+			continue
+		}
+		vals.Set(name, record[i])
+	}
+
+	r := evCtx.R.Clone(evCtx.R.Context())
+	r.Form = vals
+	r.PostForm = vals
+	r.MultipartForm = &multipart.Form{Value: vals}
+	return &web.EventContext{R: r, W: evCtx.W, Injector: evCtx.Injector}, id
+}
+
+// importFieldsBuilder only keeps the fields of the csv columns,
+// so that the other fields of the updated records are not cleared.
+func (b *ImportBuilder) importFieldsBuilder(eb *EditingBuilder, req *ImportRequest) *FieldsBuilder {
+	var names []any
+	for _, name := range req.Fields {
+		if name == "" || name == ParamID || eb.GetField(name) == nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return &FieldsBuilder{}
+	}
+	return eb.FieldsBuilder.Only(names...)
+}
+
+func (b *ImportBuilder) editingBuilder(create bool) *EditingBuilder {
+	if create && b.mb.creating != nil {
+		return b.mb.creating
+	}
+	return b.mb.editing
+}
+
+func (b *ImportBuilder) runRow(evCtx *web.EventContext, req *ImportRequest, index int, fbs map[bool]*FieldsBuilder, save bool) (res *ImportRowResult) {
+	rowCtx, id := b.rowContext(evCtx, req, req.Records[index])
+	res = &ImportRowResult{Line: index + 2, ID: id, Create: id == ""}
+
+	obj := b.mb.NewModel()
+	if id != "" {
+		fetched, err := b.mb.editing.Fetcher(obj, id, rowCtx)
+		switch {
+		case err == nil:
+			obj = fetched
+		case errors.Is(err, ErrRecordNotFound):
+			res.Create = true
+			obj = b.mb.NewModel()
+		default:
+			res.Errors = append(res.Errors, err.Error())
+			return
+		}
+	}
+
+	verb := PermUpdate
+	if res.Create {
+		verb = PermCreate
+		id = ""
+	}
+	if b.mb.Info().Verifier().Do(verb).ObjectOn(obj).WithReq(rowCtx.R).IsAllowed() != nil {
+		res.Errors = append(res.Errors, perm.PermissionDenied.Error())
+		return
+	}
+
+	eb := b.editingBuilder(res.Create)
+	if eb.Setter != nil {
+		eb.Setter(obj, rowCtx)
+	}
+	vErr := fbs[res.Create].Unmarshal(obj, b.mb.Info(), false, rowCtx)
+	if eb.Validator != nil {
+		vErrValidator := eb.Validator(obj, rowCtx)
+		_ = vErr.Merge(&vErrValidator)
+	}
+	if vErr.HaveErrors() {
+		res.Errors = append(res.Errors, validationErrorMessages(&vErr)...)
+		return
+	}
+	if !save {
+		return
+	}
+
+	if err := eb.Saver(obj, id, rowCtx); err != nil {
+		res.Errors = append(res.Errors, err.Error())
+		return
+	}
+	res.ID = vx.ObjectID(obj)
+	return
+}
+
+func (b *ImportBuilder) fieldsBuilders(req *ImportRequest) map[bool]*FieldsBuilder {
+	return map[bool]*FieldsBuilder{
+		true:  b.importFieldsBuilder(b.editingBuilder(true), req),
+		false: b.importFieldsBuilder(b.editingBuilder(false), req),
+	}
+}
+
+// DryRun runs every row through the setters and validators without saving.
+func (b *ImportBuilder) DryRun(evCtx *web.EventContext, req *ImportRequest) (report *ImportReport) {
+	report = &ImportReport{}
+	fbs := b.fieldsBuilders(req)
+	for i := range req.Records {
+		report.add(b.runRow(evCtx, req, i, fbs, false))
+	}
+	return
+}
+
+// Run saves the rows from the from index on in batches, invalid rows are skipped and reported.
+// Each batch is saved in a transaction if Transact is set.
+// checkpoint is called after each batch with the index of the next row, so the import can be resumed from there.
+func (b *ImportBuilder) Run(evCtx *web.EventContext, req *ImportRequest, from int, checkpoint func(next int, rows []*ImportRowResult) error) (report *ImportReport, err error) {
+	report = &ImportReport{}
+	fbs := b.fieldsBuilders(req)
+	transact := b.transact
+	if transact == nil {
+		transact = func(evCtx *web.EventContext, f func(txCtx *web.EventContext) error) error {
+			return f(evCtx)
+		}
+	}
+	for start := from; start < len(req.Records); start += b.batchSize {
+		if err = evCtx.R.Context().Err(); err != nil {
+			return
+		}
+		end := min(start+b.batchSize, len(req.Records))
+		rows := make([]*ImportRowResult, 0, end-start)
+		err = transact(evCtx, func(txCtx *web.EventContext) error {
+			for i := start; i < end; i++ {
+				var row *ImportRowResult
+				err := transact(txCtx, func(rowCtx *web.EventContext) error {
+					row = b.runRow(rowCtx, req, i, fbs, true)
+					if len(row.Errors) > 0 {
+						return errImportRowInvalid
+					}
+					return nil
+				})
+				if err != nil && !errors.Is(err, errImportRowInvalid) {
+					return err
+				}
+				rows = append(rows, row)
+			}
+			return nil
+		})
+		if err != nil {
+			return
+		}
+		report.add(rows...)
+		if checkpoint != nil {
+			if err = checkpoint(end, rows); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (c *ListingCompo) importButton(ctx context.Context) h.HTMLComponent {
+	ib := c.lb.mb.importing
+	if ib == nil {
+		return nil
+	}
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	if ib.isAllowed(evCtx) != nil {
+		return nil
+	}
+	return VBtn(msgr.ListingImport).
+		Variant(VariantFlat).Color(ColorSecondary).Class("ml-2").
+		PrependIcon("mdi-upload").
+		Attr("@click", stateful.PostAction(ctx, c, c.OpenImportDialog, OpenImportDialogRequest{}).Go())
+}
+
+func (c *ListingCompo) importPanel(ctx context.Context, unknown []string, report *ImportReport) h.HTMLComponent {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+
+	var errCompo h.HTMLComponent
+	if vErr, ok := evCtx.Flash.(*web.ValidationErrors); ok {
+		if gErr := vErr.GetGlobalError(); gErr != "" {
+			errCompo = VAlert(h.Text(gErr)).Border("left").Type("error").Elevation(2).Class("mb-4")
+		}
+	}
+
+	var reportCompo h.HTMLComponent
+	if report != nil {
+		invalid := report.Invalid
+		if len(invalid) > importMaxReportRows {
+			invalid = invalid[:importMaxReportRows]
+		}
+		reportCompo = h.Div(
+			h.If(len(unknown) > 0,
+				VAlert(h.Text(msgr.ListingImportUnknownColumns(strings.Join(unknown, ", ")))).
+					Type("warning").Density(DensityCompact).Class("mb-2"),
+			),
+			VAlert(h.Text(msgr.ListingImportSummary(report.Create, report.Update, len(report.Invalid)))).
+				Type(lo.Ternary(len(report.Invalid) > 0, "warning", "success")).Density(DensityCompact).Class("mb-2"),
+			h.If(len(invalid) > 0,
+				VTable(
+					h.Thead(h.Tr(
+						h.Th(msgr.ListingImportLine),
+						h.Th("ID"),
+						h.Th(msgr.ListingImportErrors),
+					)),
+					h.Tbody(lo.Map(invalid, func(row *ImportRowResult, _ int) h.HTMLComponent {
+						return h.Tr(
+							h.Td(h.Text(fmt.Sprint(row.Line))),
+							h.Td(h.Text(row.ID)),
+							h.Td(h.Text(strings.Join(row.Errors, "; "))),
+						)
+					})...),
+				).Density(DensityCompact),
+			),
+		)
+	}
+
+	return VCard(
+		VCardTitle(h.Text(msgr.ListingImport)),
+		VCardText(
+			errCompo,
+			VFileInput().Label(msgr.ListingImportFile).Attr("accept", ".csv").
+				Density(DensityCompact).Variant(VariantOutlined).
+				Attr("@update:model-value", fmt.Sprintf("form.%s = [].concat($event || [])", paramImportFile)),
+			reportCompo,
+		),
+		VCardActions(
+			VSpacer(),
+			VBtn(msgr.Cancel).Variant(VariantFlat).Class("ml-2").Attr("@click", c.closeActionDialog()),
+			VBtn(msgr.ListingImportValidate).Variant(VariantFlat).Color(ColorSecondary).Class("ml-2").
+				Attr("@click", stateful.PostAction(ctx, c, c.DoImport, DoImportRequest{DryRun: true}).Go()),
+			VBtn(msgr.ListingImport).Color(ColorPrimary).Variant(VariantFlat).Theme(ThemeDark).Class("ml-2").
+				Attr(":disabled", h.JSONString(report == nil)).
+				Attr("@click", stateful.PostAction(ctx, c, c.DoImport, DoImportRequest{}).Go()),
+		),
+	)
+}
+
+type OpenImportDialogRequest struct{}
+
+func (c *ListingCompo) OpenImportDialog(ctx context.Context, _ OpenImportDialogRequest) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	ib := c.lb.mb.importing
+	if ib == nil {
+		return r, errors.New("import is not enabled")
+	}
+	if err = ib.isAllowed(evCtx); err != nil {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+
+	c.dialog(&r, c.importPanel(ctx, nil, nil), "")
+	return r, nil
+}
+
+type DoImportRequest struct {
+	DryRun bool `json:"dry_run"`
+}
+
+func (c *ListingCompo) DoImport(ctx context.Context, req DoImportRequest) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	ib := c.lb.mb.importing
+	if ib == nil {
+		return r, errors.New("import is not enabled")
+	}
+	if err = ib.isAllowed(evCtx); err != nil {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+
+	updatePanel := func(unknown []string, report *ImportReport) {
+		r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+			Name: c.actionDialogContentPortalName(),
+			Body: c.importPanel(ctx, unknown, report),
+		})
+	}
+	showError := func(msg string) {
+		vErr := &web.ValidationErrors{}
+		vErr.GlobalError(msg)
+		evCtx.Flash = vErr
+		updatePanel(nil, nil)
+	}
+
+	var files []*multipart.FileHeader
+	if evCtx.R.MultipartForm != nil {
+		files = evCtx.R.MultipartForm.File[paramImportFile]
+	}
+	if len(files) == 0 {
+		showError(msgr.ListingImportNoFile)
+		return r, nil
+	}
+	f, err := files[0].Open()
+	if err != nil {
+		return r, err
+	}
+	defer f.Close()
+
+	importReq, unknown, err := ib.ParseCSV(evCtx, f)
+	if err != nil {
+		showError(err.Error())
+		return r, nil
+	}
+
+	if req.DryRun {
+		updatePanel(unknown, ib.DryRun(evCtx, importReq))
+		return r, nil
+	}
+
+	if ib.jobFunc != nil && len(importReq.Records) > ib.jobThreshold {
+		if err = ib.jobFunc(evCtx, importReq, &r); err != nil {
+			return r, err
+		}
+		ShowMessage(&r, msgr.ListingImportJobStarted, "")
+		web.AppendRunScripts(&r, c.closeActionDialog())
+		return r, nil
+	}
+
+	report, err := ib.Run(evCtx, importReq, 0, nil)
+	if err != nil {
+		return r, err
+	}
+	ShowMessage(&r, msgr.ListingImportFinished(report.Create, report.Update, len(report.Invalid)), lo.Ternary(len(report.Invalid) > 0, ColorWarning, ""))
+	if len(report.Invalid) > 0 {
+		updatePanel(unknown, report)
+	} else {
+		web.AppendRunScripts(&r, c.closeActionDialog())
+	}
+	web.AppendRunScripts(&r, stateful.ReloadAction(ctx, c, nil).Go())
+	return r, nil
+}
@@ -0,0 +1,105 @@
+package presets
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportBuilder(t *testing.T) {
+	type Product struct {
+		ID    uint
+		Name  string
+		Code  string
+		Price int
+	}
+
+	existing := map[string]*Product{
+		"1": {ID: 1, Name: "Apple", Code: "A1", Price: 3},
+	}
+	saved := map[string]*Product{}
+
+	pb := New()
+	mb := pb.Model(&Product{})
+	mb.Editing("Name", "Code", "Price").
+		FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (interface{}, error) {
+			p, ok := existing[id]
+			if !ok {
+				return nil, ErrRecordNotFound
+			}
+			cp := *p
+			return &cp, nil
+		}).
+		SaveFunc(func(obj interface{}, id string, ctx *web.EventContext) error {
+			saved[obj.(*Product).Name] = obj.(*Product)
+			return nil
+		}).
+		ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+			if obj.(*Product).Name == "" {
+				err.FieldError("Name", "name is required")
+			}
+			return
+		})
+	ib := mb.Import().BatchSize(2)
+
+	evCtx := &web.EventContext{R: httptest.NewRequest("POST", "/products", nil)}
+	req, unknown, err := ib.ParseCSV(evCtx, strings.NewReader(
+		"\uFEFFID,name,Price,Color\n"+
+			"1,Apple Updated,5,red\n"+
+			",Banana,2,yellow\n"+
+			"9,,1,green\n",
+	))
+	require.NoError(t, err)
+	assert.Equal(t, []string{ParamID, "Name", "Price", ""}, req.Fields)
+	assert.Equal(t, []string{"Color"}, unknown)
+
+	report := ib.DryRun(evCtx, req)
+	assert.Equal(t, 1, report.Create)
+	assert.Equal(t, 1, report.Update)
+	require.Len(t, report.Invalid, 1)
+	assert.Equal(t, 4, report.Invalid[0].Line)
+	assert.Equal(t, []string{"Name: name is required"}, report.Invalid[0].Errors)
+	assert.Empty(t, saved)
+
+	var checkpoints []int
+	report, err = ib.Run(evCtx, req, 1, func(next int, rows []*ImportRowResult) error {
+		checkpoints = append(checkpoints, next)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, checkpoints)
+	assert.Equal(t, 1, report.Create)
+	assert.Len(t, report.Invalid, 1)
+	require.Contains(t, saved, "Banana")
+	assert.Equal(t, 2, saved["Banana"].Price)
+	assert.NotContains(t, saved, "Apple Updated")
+
+	_, err = ib.Run(evCtx, req, 0, nil)
+	require.NoError(t, err)
+	require.Contains(t, saved, "Apple Updated")
+	// the columns not in the csv are kept
+	assert.Equal(t, "A1", saved["Apple Updated"].Code)
+	assert.Equal(t, 5, saved["Apple Updated"].Price)
+
+	// batches are run in transactions and the rows in the nested ones, an invalid row only fails its own
+	var (
+		depth int
+		txs   []string
+	)
+	ib.Transact(func(evCtx *web.EventContext, f func(txCtx *web.EventContext) error) error {
+		depth++
+		defer func() { depth-- }()
+		err := f(evCtx)
+		txs = append(txs, fmt.Sprintf("%d:%t", depth, err == nil))
+		return err
+	})
+	report, err = ib.Run(evCtx, req, 0, nil)
+	require.NoError(t, err)
+	assert.Len(t, report.Invalid, 1)
+	assert.Equal(t, []string{"2:true", "2:true", "1:true", "2:false", "1:true"}, txs)
+}
@@ -0,0 +1,145 @@
+package presets
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+)
+
+// RowScopeFunc returns the condition of the records the current user could access, like
+// `brand_id IN ?` with the brands of the user's roles, nil for all the records.
+type RowScopeFunc func(evCtx *web.EventContext) (*SQLCondition, error)
+
+type ctxKeyRowScope struct {
+	modelType reflect.Type
+}
+
+func modelTypeOf(model any) reflect.Type {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// WithRowScope limits the records of the model which the DataOperator searches, fetches, saves and deletes to the condition.
+func WithRowScope(ctx context.Context, model any, cond *SQLCondition) context.Context {
+	return context.WithValue(ctx, ctxKeyRowScope{modelTypeOf(model)}, cond)
+}
+
+// RowScopeFromContext returns the condition of the model set by WithRowScope. DataOperators should report
+// ErrRecordNotFound for the records out of the scope, and perm.PermissionDenied for saving a record out of it.
+func RowScopeFromContext(ctx context.Context, model any) (*SQLCondition, bool) {
+	cond, ok := ctx.Value(ctxKeyRowScope{modelTypeOf(model)}).(*SQLCondition)
+	return cond, ok && cond != nil
+}
+
+// RowScope limits the records the current user could access to the condition returned by f,
+// it wraps the search, fetch, save and delete funcs, including the ones set after it, the selected ids
+// of the bulk actions and the trash actions are limited to the records in the scope too.
+func (mb *ModelBuilder) RowScope(f RowScopeFunc) (r *ModelBuilder) {
+	mb.rowScope = f
+
+	mb.listing.Searcher = mb.rowScopedSearchFunc(mb.listing.Searcher)
+	mb.editing.Fetcher = mb.rowScopedFetchFunc(mb.editing.Fetcher)
+	mb.editing.Saver = mb.rowScopedSaveFunc(mb.editing.Saver)
+	mb.editing.Deleter = mb.rowScopedDeleteFunc(mb.editing.Deleter)
+	mb.detailing.fetcher = mb.rowScopedFetchFunc(mb.detailing.fetcher)
+	return mb
+}
+
+// The rowScoped funcs apply the row scope before calling the func if the model has one,
+// the setters of the funcs call them so that the funcs set after RowScope are limited too.
+
+func (mb *ModelBuilder) rowScopedSearchFunc(in SearchFunc) SearchFunc {
+	if mb.rowScope == nil || in == nil {
+		return in
+	}
+	return func(evCtx *web.EventContext, params *SearchParams) (result *SearchResult, err error) {
+		if err = mb.applyRowScope(evCtx); err != nil {
+			return
+		}
+		return in(evCtx, params)
+	}
+}
+
+func (mb *ModelBuilder) rowScopedFetchFunc(in FetchFunc) FetchFunc {
+	if mb.rowScope == nil || in == nil {
+		return in
+	}
+	return func(obj interface{}, id string, evCtx *web.EventContext) (r interface{}, err error) {
+		if err = mb.applyRowScope(evCtx); err != nil {
+			return
+		}
+		return in(obj, id, evCtx)
+	}
+}
+
+func (mb *ModelBuilder) rowScopedSaveFunc(in SaveFunc) SaveFunc {
+	if mb.rowScope == nil || in == nil {
+		return in
+	}
+	return func(obj interface{}, id string, evCtx *web.EventContext) (err error) {
+		if err = mb.applyRowScope(evCtx); err != nil {
+			return
+		}
+		return in(obj, id, evCtx)
+	}
+}
+
+func (mb *ModelBuilder) rowScopedDeleteFunc(in DeleteFunc) DeleteFunc {
+	if mb.rowScope == nil || in == nil {
+		return in
+	}
+	return func(obj interface{}, id string, evCtx *web.EventContext) (err error) {
+		if err = mb.applyRowScope(evCtx); err != nil {
+			return
+		}
+		return in(obj, id, evCtx)
+	}
+}
+
+// RowScopeCondition returns the condition of the row scope for the request, nil if the model has no row scope,
+// it is for the queries of the model which don't go through the DataOperator.
+func (mb *ModelBuilder) RowScopeCondition(evCtx *web.EventContext) (*SQLCondition, error) {
+	if mb.rowScope == nil {
+		return nil, nil
+	}
+	return mb.rowScope(evCtx)
+}
+
+func (mb *ModelBuilder) applyRowScope(evCtx *web.EventContext) error {
+	if mb.rowScope == nil {
+		return nil
+	}
+	cond, err := mb.rowScope(evCtx)
+	if err != nil {
+		return err
+	}
+	evCtx.WithContextValue(ctxKeyRowScope{modelTypeOf(mb.model)}, cond)
+	return nil
+}
+
+// idsInRowScope filters the ids of the records out of the row scope.
+func (mb *ModelBuilder) idsInRowScope(evCtx *web.EventContext, ids []string) ([]string, error) {
+	if mb.rowScope == nil {
+		return ids, nil
+	}
+	if mb.editing.Fetcher == nil {
+		return nil, errors.New("row scope requires the fetch func of the editing")
+	}
+	var r []string
+	for _, id := range ids {
+		_, err := mb.editing.Fetcher(mb.NewModel(), id, evCtx)
+		if errors.Is(err, ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, id)
+	}
+	return r, nil
+}
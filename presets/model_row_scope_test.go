@@ -0,0 +1,80 @@
+package presets
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelRowScope(t *testing.T) {
+	type Product struct {
+		ID      string
+		BrandID int
+	}
+	products := map[string]*Product{"1": {ID: "1", BrandID: 1}, "2": {ID: "2", BrandID: 2}, "3": {ID: "3", BrandID: 1}}
+
+	mb := New().Model(&Product{})
+	mb.Editing().FetchFunc(func(obj interface{}, id string, ctx *web.EventContext) (interface{}, error) {
+		p, ok := products[id]
+		if !ok {
+			return nil, ErrRecordNotFound
+		}
+		if cond, ok := RowScopeFromContext(ctx.R.Context(), obj); ok && cond.Args[0] != p.BrandID {
+			return nil, ErrRecordNotFound
+		}
+		return p, nil
+	})
+	mb.RowScope(func(evCtx *web.EventContext) (*SQLCondition, error) {
+		if evCtx.R.URL.Query().Get("admin") != "" {
+			return nil, nil
+		}
+		return &SQLCondition{Query: "brand_id = ?", Args: []any{1}}, nil
+	})
+
+	evCtx := &web.EventContext{R: httptest.NewRequest("GET", "/products", nil)}
+	_, err := mb.Editing().Fetcher(&Product{}, "2", evCtx)
+	assert.ErrorIs(t, err, ErrRecordNotFound)
+	_, ok := RowScopeFromContext(evCtx.R.Context(), &Product{})
+	assert.True(t, ok)
+
+	ids, err := mb.idsInRowScope(evCtx, []string{"1", "2", "3", "4"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, ids)
+
+	evCtx = &web.EventContext{R: httptest.NewRequest("GET", "/products?admin=1", nil)}
+	_, err = mb.Editing().Fetcher(&Product{}, "2", evCtx)
+	require.NoError(t, err)
+	_, ok = RowScopeFromContext(evCtx.R.Context(), &Product{})
+	assert.False(t, ok)
+
+	// the funcs set after RowScope are limited too
+	var searched, saved bool
+	mb.Listing().SearchFunc(func(evCtx *web.EventContext, _ *SearchParams) (*SearchResult, error) {
+		_, searched = RowScopeFromContext(evCtx.R.Context(), &Product{})
+		return &SearchResult{}, nil
+	})
+	mb.Editing().SaveFunc(func(obj interface{}, _ string, evCtx *web.EventContext) error {
+		_, saved = RowScopeFromContext(evCtx.R.Context(), obj)
+		return nil
+	})
+	_, err = mb.Listing().Searcher(&web.EventContext{R: httptest.NewRequest("GET", "/products", nil)}, &SearchParams{})
+	require.NoError(t, err)
+	assert.True(t, searched)
+	require.NoError(t, mb.Editing().Saver(&Product{}, "1", &web.EventContext{R: httptest.NewRequest("POST", "/products", nil)}))
+	assert.True(t, saved)
+
+	cond, err := mb.RowScopeCondition(&web.EventContext{R: httptest.NewRequest("GET", "/", nil)})
+	require.NoError(t, err)
+	assert.Equal(t, &SQLCondition{Query: "brand_id = ?", Args: []any{1}}, cond)
+	cond, err = New().Model(&Product{}).RowScopeCondition(evCtx)
+	require.NoError(t, err)
+	assert.Nil(t, cond)
+
+	noFetcher := New().Model(&Product{})
+	noFetcher.RowScope(func(*web.EventContext) (*SQLCondition, error) { return nil, nil })
+	_, err = noFetcher.idsInRowScope(evCtx, []string{"1"})
+	assert.Error(t, err)
+}
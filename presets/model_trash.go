@@ -0,0 +1,258 @@
+package presets
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/web/v3/stateful"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	vx "github.com/qor5/x/v3/ui/vuetifyx"
+	h "github.com/theplant/htmlgo"
+)
+
+// TrashFilterTabID is the filter tab listing the soft deleted records.
+const TrashFilterTabID = "trash"
+
+// TrashDataOperator is implemented by data operators which support soft delete, see gorm2op.DataOperatorBuilder.
+type TrashDataOperator interface {
+	// Restore undeletes the soft deleted record.
+	Restore(obj interface{}, id string, ctx *web.EventContext) (err error)
+	// Purge deletes the soft deleted record permanently.
+	Purge(obj interface{}, id string, ctx *web.EventContext) (err error)
+}
+
+type TrashBuilder struct {
+	mb       *ModelBuilder
+	restorer DeleteFunc
+	purger   DeleteFunc
+}
+
+// Trash adds a Trash filter tab to the listing which lists the soft deleted records,
+// they could be restored or deleted permanently with PermRestore and PermPurge.
+// SearchParams.Trashed is set for the tab, the restore and purge functions default to the TrashDataOperator.
+func (mb *ModelBuilder) Trash() (r *TrashBuilder) {
+	if mb.trash == nil {
+		mb.trash = &TrashBuilder{mb: mb}
+		if op, ok := mb.p.dataOperator.(TrashDataOperator); ok {
+			mb.trash.restorer = op.Restore
+			mb.trash.purger = op.Purge
+		}
+	}
+	return mb.trash
+}
+
+func (mb *ModelBuilder) GetTrash() *TrashBuilder {
+	return mb.trash
+}
+
+func (b *TrashBuilder) RestoreFunc(v DeleteFunc) (r *TrashBuilder) {
+	b.restorer = v
+	return b
+}
+
+func (b *TrashBuilder) PurgeFunc(v DeleteFunc) (r *TrashBuilder) {
+	b.purger = v
+	return b
+}
+
+func (b *TrashBuilder) isAllowed(evCtx *web.EventContext, action string) error {
+	return b.mb.Info().Verifier().Do(action).WithReq(evCtx.R).IsAllowed()
+}
+
+func (b *TrashBuilder) tabIsAllowed(evCtx *web.EventContext) bool {
+	return b.isAllowed(evCtx, PermRestore) == nil || b.isAllowed(evCtx, PermPurge) == nil
+}
+
+// inTrash doesn't trust the active filter tab of the compo state alone, the trash is only listed if the tab is allowed.
+func (c *ListingCompo) inTrash(evCtx *web.EventContext) bool {
+	return c.lb.mb.trash != nil && c.ActiveFilterTab == TrashFilterTabID && c.lb.mb.trash.tabIsAllowed(evCtx)
+}
+
+func (c *ListingCompo) trashTab(ctx context.Context) h.HTMLComponent {
+	_, msgr := c.MustGetEventContext(ctx)
+	return VTab().
+		Attr("@click", stateful.ReloadAction(ctx, c, func(target *ListingCompo) {
+			target.Page = 0
+			target.After, target.Before = nil, nil
+			target.SelectedIds = nil
+			target.ActiveFilterTab = TrashFilterTabID
+			target.FilterQuery = ""
+		}).ThenScript(c.JsScrollToTop()).Go()).
+		Children(
+			VIcon("mdi-delete-outline").Size(SizeSmall).Class("mr-1"),
+			h.Text(msgr.Trash),
+		)
+}
+
+func (c *ListingCompo) trashRowMenuItemFuncs(ctx context.Context) []vx.RowMenuItemFunc {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	tb := c.lb.mb.trash
+
+	var items []vx.RowMenuItemFunc
+	if tb.restorer != nil && tb.isAllowed(evCtx, PermRestore) == nil {
+		items = append(items, func(_ interface{}, id string, _ *web.EventContext) h.HTMLComponent {
+			return VListItem(
+				web.Slot(VIcon("mdi-restore")).Name("prepend"),
+				VListItemTitle(h.Text(msgr.TrashRestore)),
+			).Attr("@click", stateful.PostAction(ctx, c, c.Restore, TrashRequest{IDs: []string{id}}).Go())
+		})
+	}
+	if tb.purger != nil && tb.isAllowed(evCtx, PermPurge) == nil {
+		items = append(items, func(_ interface{}, id string, _ *web.EventContext) h.HTMLComponent {
+			return VListItem(
+				web.Slot(VIcon("mdi-delete-forever")).Name("prepend"),
+				VListItemTitle(h.Text(msgr.TrashPurge)),
+			).Attr("@click", stateful.PostAction(ctx, c, c.OpenPurgeDialog, TrashRequest{IDs: []string{id}}).Go())
+		})
+	}
+	return items
+}
+
+func (c *ListingCompo) trashActionsComponent(ctx context.Context) h.HTMLComponent {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	tb := c.lb.mb.trash
+
+	var buttons []h.HTMLComponent
+	if tb.restorer != nil && tb.isAllowed(evCtx, PermRestore) == nil {
+		buttons = append(buttons, VBtn(msgr.TrashRestore).
+			Color(ColorSecondary).Variant(VariantFlat).Class("ml-2").
+			Attr("@click", stateful.PostAction(ctx, c, c.Restore, TrashRequest{}).Go()))
+	}
+	if tb.purger != nil && tb.isAllowed(evCtx, PermPurge) == nil {
+		buttons = append(buttons, VBtn(msgr.TrashPurge).
+			Color(ColorError).Variant(VariantFlat).Class("ml-2").
+			Attr("@click", stateful.PostAction(ctx, c, c.OpenPurgeDialog, TrashRequest{}).Go()))
+	}
+	return h.Div(buttons...)
+}
+
+// TrashRequest applies to the IDs, or to the selected records if the IDs are empty.
+type TrashRequest struct {
+	IDs []string `json:"ids"`
+}
+
+func (c *ListingCompo) trashIDs(req TrashRequest) []string {
+	if len(req.IDs) > 0 {
+		return req.IDs
+	}
+	return c.SelectedIds
+}
+
+func (c *ListingCompo) Restore(ctx context.Context, req TrashRequest) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	tb := c.lb.mb.trash
+	if tb == nil || tb.restorer == nil {
+		return r, errors.New("trash restore is not enabled")
+	}
+	if tb.isAllowed(evCtx, PermRestore) != nil {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+	ids := c.trashIDs(req)
+	if len(ids) == 0 {
+		ShowMessage(&r, msgr.BulkActionNoRecordsSelected, ColorWarning)
+		return r, nil
+	}
+	if err = c.lb.mb.applyRowScope(evCtx); err != nil {
+		return r, err
+	}
+
+	var models []any
+	for _, id := range ids {
+		if err = tb.restorer(c.lb.mb.NewModel(), id, evCtx); err != nil {
+			ShowMessage(&r, err.Error(), ColorError)
+			return r, nil
+		}
+		obj, err := c.lb.mb.editing.Fetcher(c.lb.mb.NewModel(), id, evCtx)
+		if err != nil {
+			return r, err
+		}
+		models = append(models, obj)
+	}
+
+	r.Emit(c.lb.mb.NotifModelsCreated(), PayloadModelsCreated{Models: models})
+	c.afterTrashAction(ctx, &r)
+	ShowMessage(&r, msgr.SuccessfullyRestored, "")
+	return r, nil
+}
+
+func (c *ListingCompo) OpenPurgeDialog(ctx context.Context, req TrashRequest) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	tb := c.lb.mb.trash
+	if tb == nil || tb.purger == nil {
+		return r, errors.New("trash purge is not enabled")
+	}
+	if tb.isAllowed(evCtx, PermPurge) != nil {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+	ids := c.trashIDs(req)
+	if len(ids) == 0 {
+		ShowMessage(&r, msgr.BulkActionNoRecordsSelected, ColorWarning)
+		return r, nil
+	}
+
+	c.dialog(&r, VCard(
+		VCardTitle(h.Text(msgr.TrashPurgeConfirmation(len(ids)))),
+		VCardActions(
+			VSpacer(),
+			VBtn(msgr.Cancel).Variant(VariantFlat).Class("ml-2").Attr("@click", c.closeActionDialog()),
+			VBtn(msgr.TrashPurge).Color(ColorError).Variant(VariantFlat).Theme(ThemeDark).
+				Attr("@click", stateful.PostAction(ctx, c, c.Purge, TrashRequest{IDs: ids}).Go()),
+		),
+	), "480")
+	return r, nil
+}
+
+func (c *ListingCompo) Purge(ctx context.Context, req TrashRequest) (r web.EventResponse, err error) {
+	evCtx, msgr := c.MustGetEventContext(ctx)
+	tb := c.lb.mb.trash
+	if tb == nil || tb.purger == nil {
+		return r, errors.New("trash purge is not enabled")
+	}
+	if tb.isAllowed(evCtx, PermPurge) != nil {
+		ShowMessage(&r, msgr.PermissionDenied, ColorError)
+		return r, nil
+	}
+	ids := c.trashIDs(req)
+	if err = c.lb.mb.applyRowScope(evCtx); err != nil {
+		return r, err
+	}
+
+	var purged []string
+	for _, id := range ids {
+		if err = tb.purger(c.lb.mb.NewModel(), id, evCtx); err != nil {
+			break
+		}
+		purged = append(purged, id)
+	}
+	if len(purged) > 0 {
+		r.Emit(c.lb.mb.NotifModelsDeleted(), PayloadModelsDeleted{Ids: purged})
+	}
+	if err != nil {
+		ShowMessage(&r, err.Error(), ColorError)
+		return r, nil
+	}
+
+	web.AppendRunScripts(&r, c.closeActionDialog())
+	c.afterTrashAction(ctx, &r)
+	ShowMessage(&r, msgr.SuccessfullyPurged, "")
+	return r, nil
+}
+
+func (c *ListingCompo) afterTrashAction(ctx context.Context, r *web.EventResponse) {
+	if c.lb.disableModelListeners {
+		web.AppendRunScripts(r, stateful.ReloadAction(ctx, c, func(target *ListingCompo) {
+			target.SelectedIds = nil
+		}).Go())
+	}
+}
+
+func (c *ListingCompo) rowMenuItemFuncs(ctx context.Context) []vx.RowMenuItemFunc {
+	evCtx, _ := c.MustGetEventContext(ctx)
+	if c.inTrash(evCtx) {
+		return c.trashRowMenuItemFuncs(ctx)
+	}
+	return c.lb.RowMenu().listingItemFuncs(evCtx)
+}
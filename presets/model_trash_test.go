@@ -0,0 +1,39 @@
+package presets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/perm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListingCompoInTrash(t *testing.T) {
+	type Product struct {
+		ID uint
+	}
+
+	b := New().Permission(perm.New().Policies(
+		perm.PolicyFor(perm.Anybody).WhoAre(perm.Allowed).ToDo(PermList).On(perm.Anything),
+		perm.PolicyFor("editor").WhoAre(perm.Allowed).ToDo(PermRestore).On(perm.Anything),
+	).SubjectsFunc(func(r *http.Request) []string {
+		return []string{r.Header.Get("Role")}
+	}))
+	mb := b.Model(&Product{})
+	mb.Trash()
+
+	evCtx := func(role string) *web.EventContext {
+		r := httptest.NewRequest("GET", "/products", nil)
+		r.Header.Set("Role", role)
+		return &web.EventContext{R: r}
+	}
+	// the trash tab in the compo state is ignored without the permissions of the tab
+	c := &ListingCompo{lb: mb.Listing(), ActiveFilterTab: TrashFilterTabID}
+	assert.False(t, c.inTrash(evCtx("viewer")))
+	assert.True(t, c.inTrash(evCtx("editor")))
+
+	c.ActiveFilterTab = ""
+	assert.False(t, c.inTrash(evCtx("editor")))
+}
@@ -70,6 +70,10 @@ type Builder struct {
 	notFoundHandler                       http.Handler
 	customBuilders                        []*CustomBuilder
 	toolbarFunc                           func(ctx *web.EventContext) h.HTMLComponent
+	globalSearchOff                       bool
+	globalSearchPerModel                  int
+	globalSearchConcurrency               int
+	dashboard                             *DashboardBuilder
 }
 
 type AssetFunc func(ctx *web.EventContext)
@@ -122,6 +126,7 @@ func New() *Builder {
 	}
 	b.menuOrder = NewMenuOrderBuilder(b)
 	b.GetWebBuilder().RegisterEventFunc(OpenConfirmDialog, b.openConfirmDialog)
+	b.GetWebBuilder().RegisterEventFunc(actions.GlobalSearch, b.globalSearchResults)
 	b.layoutFunc = b.defaultLayout
 	b.detailLayoutFunc = b.defaultLayout
 	b.notFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -866,6 +871,7 @@ func (b *Builder) defaultLeftMenuComp(ctx *web.EventContext) h.HTMLComponent {
 		VLayout(
 			VMain(
 				b.toolbarFunc(ctx),
+				b.globalSearchBox(ctx),
 				VCard(
 					menu,
 				).Class("menu-content mt-2 mb-4 ml-4 pr-4").Variant(VariantText),
@@ -1111,6 +1117,9 @@ func (b *Builder) getHomePageFunc() web.PageFunc {
 	if b.homePageFunc != nil {
 		return b.homePageFunc
 	}
+	if b.dashboard != nil && len(b.dashboard.widgets) > 0 {
+		return b.dashboard.pageFunc
+	}
 	return b.defaultHomePageFunc
 }
 
@@ -1210,6 +1219,12 @@ func (b *Builder) initMux() {
 		)
 		log.Printf("mounted url: %s\n", routePath)
 
+		if m.listing.exporting != nil {
+			exportPath := fmt.Sprintf("GET %s/export/{format}", info.ListingHref())
+			mux.Handle(exportPath, b.wrapHandler(http.HandlerFunc(m.listing.exporting.serveHTTP)))
+			log.Printf("mounted url: %s\n", exportPath)
+		}
+
 		if m.hasDetailing {
 			routePath = fmt.Sprintf("%s/%s/{id}", b.prefix, pluralUri)
 			mux.Handle(
@@ -1363,13 +1378,15 @@ func (b *Builder) wrapInner(f func(p *web.PageBuilder), pf web.PageFunc) http.Ha
 		}
 	})
 
-	handlers := b.GetI18n().EnsureLanguage(
-		p,
-	)
+	return b.wrapHandler(p)
+}
+
+// wrapHandler applies the language detection and the handlers added by AddWrapHandler
+func (b *Builder) wrapHandler(in http.Handler) http.Handler {
+	handlers := b.GetI18n().EnsureLanguage(in)
 	for _, wrapHandler := range b.wrapHandlers {
 		handlers = wrapHandler(handlers)
 	}
-
 	return handlers
 }
 
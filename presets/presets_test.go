@@ -92,6 +92,41 @@ func TestLookUpModelBuilder(t *testing.T) {
 	assert.Equal(t, mb3, pb.LookUpModelBuilder(mb3.Info().URIName()))
 }
 
+type testKeywordSearch struct{}
+
+func (testKeywordSearch) KeywordSearchName() string { return "test" }
+
+func TestListingKeywordSearchParams(t *testing.T) {
+	type Product struct {
+		ID    uint
+		Name  string
+		Price int
+	}
+
+	mb := New().Model(&Product{})
+	lb := mb.Listing("Name", "Price").KeywordSearch(testKeywordSearch{}).
+		OrderableFields([]*OrderableField{{FieldName: "Price"}})
+	evCtx := &web.EventContext{R: httptest.NewRequest("GET", "/products", nil)}
+
+	params := lb.NewSearchParams(evCtx, "apple", nil, nil)
+	assert.Equal(t, testKeywordSearch{}, params.KeywordSearch)
+	assert.True(t, params.OrderByRelevance)
+
+	params = lb.NewSearchParams(evCtx, "apple", nil, []ColOrderBy{{FieldName: "Name", OrderBy: OrderByASC}})
+	assert.True(t, params.OrderByRelevance, "Name is not orderable")
+
+	params = lb.NewSearchParams(evCtx, "apple", nil, []ColOrderBy{{FieldName: "Price", OrderBy: OrderByASC}})
+	assert.False(t, params.OrderByRelevance)
+
+	params = lb.NewSearchParams(evCtx, "", nil, nil)
+	assert.False(t, params.OrderByRelevance)
+
+	lb.KeywordSearchOff(true)
+	params = lb.NewSearchParams(evCtx, "apple", nil, nil)
+	assert.Nil(t, params.KeywordSearch)
+	assert.False(t, params.OrderByRelevance)
+}
+
 func TestCloneFieldsLayout(t *testing.T) {
 	src := []any{
 		"foo",
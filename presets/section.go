@@ -15,6 +15,8 @@ import (
 	. "github.com/qor5/x/v3/ui/vuetify"
 	"github.com/sunfmin/reflectutils"
 	h "github.com/theplant/htmlgo"
+
+	"github.com/qor5/admin/v3/presets/actions"
 )
 
 const (
@@ -442,6 +444,7 @@ func (b *SectionBuilder) registerEvent() {
 	b.mb.RegisterEventFunc(b.EventDelete(), b.DeleteDetailListField)
 	b.mb.RegisterEventFunc(b.EventCreate(), b.CreateDetailListField)
 	b.mb.RegisterEventFunc(b.EventReload(), b.ReloadDetailField)
+	b.mb.RegisterEventFunc(b.EventRestoreDraft(), b.RestoreDetailFieldDraft)
 }
 
 func (b *SectionBuilder) EventEdit() string {
@@ -468,6 +471,10 @@ func (b *SectionBuilder) EventReload() string {
 	return fmt.Sprintf("section_reload_%s", b.name)
 }
 
+func (b *SectionBuilder) EventRestoreDraft() string {
+	return fmt.Sprintf("section_restore_draft_%s", b.name)
+}
+
 func (b *SectionBuilder) viewComponent(obj interface{}, field *FieldContext, ctx *web.EventContext) h.HTMLComponent {
 	id := b.getObjectID(ctx, obj)
 	initDataChanged := fmt.Sprintf("if (vars.%s ){vars.%s.section_%s=false};", VarsPresetsDataChanged, VarsPresetsDataChanged, b.name)
@@ -567,12 +574,20 @@ func (b *SectionBuilder) editComponent(obj interface{}, field *FieldContext, ctx
 		)
 	}
 
+	// the sections in the editing form are saved in the draft of the form
+	var draftNotice h.HTMLComponent
+	var saveDraftScript string
+	if !b.isEdit {
+		draftNotice, saveDraftScript = b.mb.draftNotice(ctx, id, b.name, b.EventRestoreDraft(), actions.DiscardDraft)
+	}
+
 	disableSaveBtn := !b.saveBtnFunc(obj, ctx)
 	if b.componentEditFunc != nil {
 		content.AppendChildren(
 			h.Div(
 				VCard(
 					VCardText(
+						draftNotice,
 						h.Div(
 							// detailFields
 							h.Div(b.componentEditFunc(obj, field, ctx)).
@@ -591,7 +606,7 @@ func (b *SectionBuilder) editComponent(obj interface{}, field *FieldContext, ctx
 		)
 	}
 	operateID := fmt.Sprint(time.Now().UnixNano())
-	onChangeEvent += checkFormChangeScript + setValidateKeysScript +
+	onChangeEvent += checkFormChangeScript + saveDraftScript + setValidateKeysScript +
 		web.Plaid().URL(ctx.R.URL.Path).
 			BeforeScript(fmt.Sprintf(`dash.__ValidateOperateID=%q`, operateID)).
 			EventFunc(b.EventValidate()).
@@ -1047,6 +1062,9 @@ func (b *SectionBuilder) SaveDetailField(ctx *web.EventContext) (r web.EventResp
 	}
 
 	if isCancel {
+		if err = b.mb.discardDraft(ctx, id, b.name); err != nil {
+			return
+		}
 		r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
 			Name: b.FieldPortalName(),
 			Body: b.viewComponent(obj, field, ctx),
@@ -1119,6 +1137,10 @@ func (b *SectionBuilder) SaveDetailField(ctx *web.EventContext) (r web.EventResp
 		return
 	}
 
+	if err = b.mb.discardDraft(ctx, id, b.name); err != nil {
+		return
+	}
+
 	r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
 		Name: b.FieldPortalName(),
 		Body: b.viewComponent(obj, field, ctx),
@@ -1131,6 +1153,42 @@ func (b *SectionBuilder) SaveDetailField(ctx *web.EventContext) (r web.EventResp
 	return r, nil
 }
 
+// RestoreDetailFieldDraft EventFunc: click restore button of the draft notice
+func (b *SectionBuilder) RestoreDetailFieldDraft(ctx *web.EventContext) (r web.EventResponse, err error) {
+	id := ctx.Param(ParamID)
+	if err = b.mb.loadDraft(ctx, id, b.name); err != nil {
+		return
+	}
+
+	obj := b.mb.NewModel()
+	obj, err = b.mb.editing.Fetcher(obj, id, ctx)
+	if err != nil {
+		return
+	}
+	if b.mb.editing.Setter != nil {
+		b.mb.editing.Setter(obj, ctx)
+	}
+	if vErr := b.editingFB.Unmarshal(obj, b.mb.Info(), false, ctx); vErr.HaveGlobalErrors() {
+		return r, &vErr
+	}
+	if b.setter != nil {
+		if err = b.setter(obj, ctx); err != nil {
+			return
+		}
+	}
+
+	r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+		Name: b.FieldPortalName(),
+		Body: b.editComponent(obj, &FieldContext{
+			ModelInfo: b.mb.modelInfo,
+			FormKey:   b.name,
+			Name:      b.name,
+			Label:     b.label,
+		}, ctx),
+	})
+	return
+}
+
 func (b *SectionBuilder) ValidateDetailField(ctx *web.EventContext) (r web.EventResponse, err error) {
 	var (
 		id        = ctx.Param(ParamID)
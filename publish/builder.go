@@ -49,10 +49,20 @@ type Builder struct {
 	nonVersionPublishModels map[string]interface{}
 	versionPublishModels    map[string]interface{}
 	listPublishModels       map[string]interface{}
+	scheduleModelBuilders   []*presets.ModelBuilder
 
 	publish              PublishFunc
 	unpublish            UnPublishFunc
 	disablementCheckFunc DisablementCheckFunc
+	reviewersFunc        ReviewersFunc
+	currentReviewerFunc  CurrentReviewerFunc
+	dependencies         map[reflect.Type][]*dependencyRule
+	dependentModels      map[string]reflect.Type
+	republishJobFunc     RepublishJobFunc
+	releases             bool
+	releaseModelBuilders map[string]*presets.ModelBuilder
+	releaseModelBuilder  *presets.ModelBuilder
+	versionFields        map[reflect.Type][]*VersionField
 }
 
 type ContextValueFunc func(ctx context.Context) context.Context
@@ -64,6 +74,10 @@ func New(db *gorm.DB, storage oss.StorageInterface) *Builder {
 		nonVersionPublishModels: make(map[string]interface{}),
 		versionPublishModels:    make(map[string]interface{}),
 		listPublishModels:       make(map[string]interface{}),
+		dependencies:            make(map[reflect.Type][]*dependencyRule),
+		dependentModels:         make(map[string]reflect.Type),
+		releaseModelBuilders:    make(map[string]*presets.ModelBuilder),
+		versionFields:           make(map[reflect.Type][]*VersionField),
 	}
 	b.publish = b.defaultPublish
 	b.unpublish = b.defaultUnPublish
@@ -108,6 +122,10 @@ func (b *Builder) ModelInstall(pb *presets.Builder, m *presets.ModelBuilder) err
 	_ = obj.(presets.SlugEncoder)
 	_ = obj.(presets.SlugDecoder)
 
+	if _, ok := obj.(ScheduleInterface); ok {
+		b.scheduleModelBuilders = append(b.scheduleModelBuilders, m)
+	}
+
 	if model, ok := obj.(VersionInterface); ok {
 		if schedulePublishModel, ok := model.(ScheduleInterface); ok {
 			b.versionPublishModels[m.Info().URIName()] = reflect.ValueOf(schedulePublishModel).Elem().Interface()
@@ -127,6 +145,10 @@ func (b *Builder) ModelInstall(pb *presets.Builder, m *presets.ModelBuilder) err
 	}
 
 	if _, ok := obj.(StatusInterface); ok {
+		if b.releases {
+			b.releaseModelBuilders[m.Info().URIName()] = m
+			m.Listing().RowMenu().RowMenuItem("AddToRelease").ComponentFunc(b.addToReleaseRowMenuItem(m))
+		}
 		m.Editing().WrapSaveFunc(func(in presets.SaveFunc) presets.SaveFunc {
 			return func(obj interface{}, id string, ctx *web.EventContext) (err error) {
 				if status := EmbedStatus(obj); status.Status == "" {
@@ -155,6 +177,17 @@ func (b *Builder) ModelInstall(pb *presets.Builder, m *presets.ModelBuilder) err
 		}
 	}
 
+	if _, ok := obj.(ReviewInterface); ok {
+		m.Editing().WrapSaveFunc(func(in presets.SaveFunc) presets.SaveFunc {
+			return func(obj interface{}, id string, ctx *web.EventContext) error {
+				if !reviewKept(ctx) {
+					ResetReview(obj)
+				}
+				return in(obj, id, ctx)
+			}
+		})
+	}
+
 	registerEventFuncsForResource(db, m, b)
 	return nil
 }
@@ -378,6 +411,10 @@ func (b *Builder) Install(pb *presets.Builder) error {
 		RegisterForModule(language.Japanese, I18nPublishKey, Messages_ja_JP)
 
 	utils.Install(pb)
+	b.installDashboardWidgets(pb)
+	if b.releases {
+		b.installReleases(pb)
+	}
 	for _, f := range b.afterInstallFuncs {
 		f()
 	}
@@ -511,7 +548,7 @@ func (b *Builder) Publish(ctx context.Context, record any) (err error) {
 
 // 幂等
 func (b *Builder) defaultPublish(ctx context.Context, record any) (err error) {
-	err = utils.Transact(b.db, func(tx *gorm.DB) (err error) {
+	err = b.transact(ctx, func(tx *gorm.DB) (err error) {
 		// publish content
 		var objs []*PublishAction
 		if objs, err = b.getPublishActions(ctx, record); err != nil {
@@ -561,7 +598,7 @@ func (b *Builder) defaultPublish(ctx context.Context, record any) (err error) {
 			}
 		}
 
-		if err = UploadOrDelete(ctx, objs, b.storage); err != nil {
+		if err = b.uploadOrDelete(ctx, objs); err != nil {
 			return
 		}
 
@@ -588,7 +625,7 @@ func (b *Builder) UnPublish(ctx context.Context, record any) (err error) {
 
 // 幂等
 func (b *Builder) defaultUnPublish(ctx context.Context, record any) (err error) {
-	err = utils.Transact(b.db, func(tx *gorm.DB) (err error) {
+	err = b.transact(ctx, func(tx *gorm.DB) (err error) {
 		// unpublish content
 		var objs []*PublishAction
 		objs, err = b.getUnPublishActions(ctx, record)
@@ -616,7 +653,7 @@ func (b *Builder) defaultUnPublish(ctx context.Context, record any) (err error)
 			}
 		}
 
-		if err = UploadOrDelete(ctx, objs, b.storage); err != nil {
+		if err = b.uploadOrDelete(ctx, objs); err != nil {
 			return
 		}
 
@@ -0,0 +1,142 @@
+package publish
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/i18n"
+	v "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/samber/lo"
+	h "github.com/theplant/htmlgo"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+const (
+	DashboardWidgetScheduled = "publish_scheduled"
+	DashboardWidgetJobs      = "publish_jobs"
+)
+
+type scheduledItem struct {
+	mb        *presets.ModelBuilder
+	obj       any
+	unpublish bool
+	at        time.Time
+}
+
+func (b *Builder) installDashboardWidgets(pb *presets.Builder) {
+	pb.Dashboard().Widget(DashboardWidgetScheduled).
+		TitleFunc(func(evCtx *web.EventContext) string {
+			return i18n.MustGetModuleMessages(evCtx.R, I18nPublishKey, Messages_en_US).(*Messages).DashboardScheduled
+		}).
+		Icon("mdi-calendar-clock").
+		ComponentFunc(b.scheduledWidget)
+	pb.Dashboard().Widget(DashboardWidgetJobs).
+		TitleFunc(func(evCtx *web.EventContext) string {
+			return i18n.MustGetModuleMessages(evCtx.R, I18nPublishKey, Messages_en_US).(*Messages).DashboardPublishJobs
+		}).
+		Icon("mdi-timer-cog-outline").
+		DefaultOff(true).
+		ComponentFunc(b.jobsWidget)
+}
+
+// scheduledWidget lists the records of the models the current user could list which are scheduled to be published or unpublished in 7 days,
+// limited to the row scopes of the models.
+func (b *Builder) scheduledWidget(evCtx *web.EventContext) (h.HTMLComponent, error) {
+	msgr := i18n.MustGetModuleMessages(evCtx.R, I18nPublishKey, Messages_en_US).(*Messages)
+
+	from := b.db.NowFunc()
+	to := from.AddDate(0, 0, 7)
+	var items []*scheduledItem
+	for _, mb := range b.scheduleModelBuilders {
+		if mb.Info().Verifier().Do(presets.PermList).WithReq(evCtx.R).IsAllowed() != nil {
+			continue
+		}
+		db := b.db.WithContext(evCtx.R.Context()).
+			Where(b.db.Where("scheduled_start_at BETWEEN ? AND ?", from, to).
+				Or("scheduled_end_at BETWEEN ? AND ?", from, to))
+		scope, err := mb.RowScopeCondition(evCtx)
+		if err != nil {
+			return nil, err
+		}
+		if scope != nil {
+			db = db.Where(scope.Query, scope.Args...)
+		}
+		records := reflect.New(reflect.SliceOf(reflect.TypeOf(mb.NewModel())))
+		if err = db.Find(records.Interface()).Error; err != nil {
+			return nil, err
+		}
+		for i := 0; i < records.Elem().Len(); i++ {
+			obj := records.Elem().Index(i).Interface()
+			schedule := EmbedSchedule(obj)
+			if at := schedule.ScheduledStartAt; at != nil && !at.Before(from) && !at.After(to) {
+				items = append(items, &scheduledItem{mb: mb, obj: obj, at: *at})
+			}
+			if at := schedule.ScheduledEndAt; at != nil && !at.Before(from) && !at.After(to) {
+				items = append(items, &scheduledItem{mb: mb, obj: obj, unpublish: true, at: *at})
+			}
+		}
+	}
+	if len(items) == 0 {
+		return h.Div(h.Text(msgr.DashboardNoScheduled)).Class("text-medium-emphasis"), nil
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].at.Before(items[j].at)
+	})
+
+	return v.VList(lo.Map(items, func(item *scheduledItem, _ int) h.HTMLComponent {
+		info := item.mb.Info()
+		id := presets.ObjectID(item.obj)
+		title := fmt.Sprintf("%s %s", info.LabelName(evCtx, true), id)
+		if pt, ok := item.obj.(interface{ PageTitle() string }); ok {
+			title = pt.PageTitle()
+		}
+		if version := EmbedVersion(item.obj); version != nil && version.VersionName != "" {
+			title = fmt.Sprintf("%s (%s)", title, version.VersionName)
+		}
+		href := info.ListingHref()
+		if info.HasDetailing() {
+			href = info.DetailingHref(id)
+		}
+		return v.VListItem(
+			v.VListItemTitle(h.Text(title)),
+			v.VListItemSubtitle(h.Text(fmt.Sprintf("%s · %s",
+				lo.Ternary(item.unpublish, msgr.Unpublish, msgr.Publish),
+				ScheduleTimeString(&item.at)))),
+		).PrependIcon(lo.Ternary(item.unpublish, "mdi-calendar-remove", "mdi-calendar-check")).
+			Href(href)
+	})...).Density(v.DensityCompact), nil
+}
+
+// jobsWidget shows the status of the jobs of the publisher scheduler, which may run in other processes.
+func (b *Builder) jobsWidget(evCtx *web.EventContext) (h.HTMLComponent, error) {
+	msgr := i18n.MustGetModuleMessages(evCtx.R, I18nPublishKey, Messages_en_US).(*Messages)
+
+	var jobs []*PublishJob
+	if b.db.Migrator().HasTable(&PublishJob{}) {
+		var err error
+		if jobs, err = NewScheduler(b.db).Statuses(evCtx.R.Context()); err != nil {
+			return nil, err
+		}
+	}
+	if len(jobs) == 0 {
+		return h.Div(h.Text(msgr.DashboardNoPublishJobs)).Class("text-medium-emphasis"), nil
+	}
+
+	return v.VList(lo.Map(jobs, func(job *PublishJob, _ int) h.HTMLComponent {
+		subtitle := fmt.Sprintf("%s: %s · %s: %s", msgr.PublishJobLastRun, ScheduleTimeString(job.LastRunAt),
+			msgr.PublishJobNextRun, ScheduleTimeString(job.NextRunAt))
+		if job.LastRunAt != nil {
+			subtitle = fmt.Sprintf("%s (%d ms)", subtitle, job.LastSpentMs)
+		}
+		return v.VListItem(
+			v.VListItemTitle(h.Text(job.Name)),
+			v.VListItemSubtitle(h.Text(subtitle)),
+			h.If(job.LastError != "", h.Div(h.Text(job.LastError)).Class("text-caption text-error")),
+		).PrependIcon(lo.Ternary(job.LastError != "", "mdi-alert-circle", "mdi-check-circle")).
+			BaseColor(lo.Ternary(job.LastError != "", v.ColorError, ""))
+	})...).Density(v.DensityCompact), nil
+}
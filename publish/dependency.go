@@ -0,0 +1,176 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/i18n"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/admin/v3/utils"
+)
+
+// DependentsFunc returns the records of the dependent model whose published output depends on the dependency record.
+type DependentsFunc func(ctx context.Context, db *gorm.DB, dependency any) (dependents []any, err error)
+
+// RepublishJobFunc hands the republish of the dependents off to a background job, see worker.Builder.RepublishDependentsJob.
+type RepublishJobFunc func(evCtx *web.EventContext, req *RepublishRequest) error
+
+// Dependent is an online record to be republished because a record it depends on is published or unpublished.
+type Dependent struct {
+	Model string `json:"model"`
+	Slug  string `json:"slug"`
+	URL   string `json:"url,omitempty"`
+}
+
+type RepublishRequest struct {
+	Dependents []*Dependent `json:"dependents"`
+}
+
+type dependencyRule struct {
+	dependent reflect.Type
+	f         DependentsFunc
+}
+
+// DependsOn declares that the published output of the dependent model depends on the records of the dependency model,
+// so that publishing or unpublishing a dependency record republishes the online records returned by the DependentsFunc,
+// and the dependents of them in turn.
+func (b *Builder) DependsOn(dependent, dependency any, f DependentsFunc) (r *Builder) {
+	dt := modelType(dependent)
+	b.dependencies[modelType(dependency)] = append(b.dependencies[modelType(dependency)], &dependencyRule{dependent: dt, f: f})
+	b.dependentModels[dt.String()] = dt
+	return b
+}
+
+// RepublishJobFunc republishes the dependents in a background job, they are republished in the request if it is not set.
+func (b *Builder) RepublishJobFunc(v RepublishJobFunc) (r *Builder) {
+	b.republishJobFunc = v
+	return b
+}
+
+func (b *Builder) HasDependents(obj any) bool {
+	return len(b.dependencies[modelType(obj)]) > 0
+}
+
+// FindDependents returns the online records depending on the obj directly or transitively, in the order to republish.
+func (b *Builder) FindDependents(ctx context.Context, obj any) (r []*Dependent, err error) {
+	visited := map[string]bool{dependentKey(obj): true}
+	queue := []any{obj}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, rule := range b.dependencies[modelType(current)] {
+			records, err := rule.f(ctx, b.db.WithContext(ctx), current)
+			if err != nil {
+				return nil, err
+			}
+			for _, record := range records {
+				status := EmbedStatus(record)
+				if status == nil || status.Status != StatusOnline {
+					continue
+				}
+				key := dependentKey(record)
+				if visited[key] {
+					continue
+				}
+				visited[key] = true
+				r = append(r, &Dependent{
+					Model: rule.dependent.String(),
+					Slug:  record.(presets.SlugEncoder).PrimarySlug(),
+					URL:   status.OnlineUrl,
+				})
+				queue = append(queue, record)
+			}
+		}
+	}
+	return
+}
+
+// RepublishDependents republishes the dependents which are still online, progress is called after each of them.
+// The dependents whose reviews are not approved, like the ones edited after they are published, are skipped and
+// returned, so that they don't go live without approval.
+func (b *Builder) RepublishDependents(ctx context.Context, dependents []*Dependent, progress func(dependent *Dependent, done int, skipped bool)) (skipped []*Dependent, err error) {
+	reqCtx := b.WithContextValues(ctx)
+	for i, dependent := range dependents {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		t, ok := b.dependentModels[dependent.Model]
+		if !ok {
+			return skipped, fmt.Errorf("dependent model %s is not registered", dependent.Model)
+		}
+		obj := reflect.New(t).Interface()
+		err = utils.PrimarySluggerWhere(b.db.WithContext(ctx), obj, dependent.Slug).First(obj).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return
+		}
+		// the dependent may be deleted or unpublished after it is found
+		unapproved := false
+		if err == nil && EmbedStatus(obj).Status == StatusOnline {
+			if unapproved = !ReviewApproved(obj); unapproved {
+				skipped = append(skipped, dependent)
+			} else if err = b.Publish(reqCtx, obj); err != nil {
+				return skipped, fmt.Errorf("republish %s %s: %w", dependent.Model, dependent.Slug, err)
+			}
+		}
+		err = nil
+		if progress != nil {
+			progress(dependent, i+1, unapproved)
+		}
+	}
+	return
+}
+
+// republishDependents republishes the dependents of the obj after it is published or unpublished, through the
+// RepublishJobFunc if it is set, which logs the skipped ones itself. The evCtx is nil if the obj is published
+// by the scheduler. The dependents skipped since they are not approved are logged and returned.
+func (b *Builder) republishDependents(ctx context.Context, evCtx *web.EventContext, obj any) (skipped []*Dependent, err error) {
+	if !b.HasDependents(obj) {
+		return
+	}
+	dependents, err := b.FindDependents(ctx, obj)
+	if err != nil || len(dependents) == 0 {
+		return
+	}
+	if evCtx != nil && b.republishJobFunc != nil {
+		return nil, b.republishJobFunc(evCtx, &RepublishRequest{Dependents: dependents})
+	}
+	skipped, err = b.RepublishDependents(ctx, dependents, nil)
+	for _, dependent := range skipped {
+		log.Printf("republish dependents of %s: skipped %s %s, the review is not approved\n", dependentKey(obj), dependent.Model, dependent.Slug)
+	}
+	return
+}
+
+// republishDependentsAfterAction republishes the dependents once the obj is published or unpublished by an action,
+// the failure and the skipped dependents are logged and reported as the warning to show instead of failing the
+// action, as the obj itself is done. The warning is empty if all the dependents are republished.
+func (b *Builder) republishDependentsAfterAction(evCtx *web.EventContext, obj any) (warning string) {
+	msgr := i18n.MustGetModuleMessages(evCtx.R, I18nPublishKey, Messages_en_US).(*Messages)
+	skipped, err := b.republishDependents(evCtx.R.Context(), evCtx, obj)
+	if err != nil {
+		log.Printf("republish dependents of %s error: %v\n", dependentKey(obj), err)
+		return msgr.RepublishDependentsFailed
+	}
+	if len(skipped) > 0 {
+		return msgr.DependentsNotApproved(len(skipped))
+	}
+	return ""
+}
+
+func modelType(v any) reflect.Type {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func dependentKey(obj any) string {
+	return modelType(obj).String() + "/" + obj.(presets.SlugEncoder).PrimarySlug()
+}
@@ -0,0 +1,95 @@
+package publish_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/publish"
+)
+
+type DependencyCategory struct {
+	ID uint
+	publish.Status
+}
+
+func (c *DependencyCategory) PrimarySlug() string { return fmt.Sprint(c.ID) }
+
+type DependencyArticle struct {
+	ID         uint
+	CategoryID uint
+	publish.Status
+}
+
+func (a *DependencyArticle) PrimarySlug() string { return fmt.Sprint(a.ID) }
+
+type DependencyFeed struct {
+	ID uint
+	publish.Status
+}
+
+func (f *DependencyFeed) PrimarySlug() string { return fmt.Sprint(f.ID) }
+
+func TestFindDependents(t *testing.T) {
+	online := func(url string) publish.Status {
+		return publish.Status{Status: publish.StatusOnline, OnlineUrl: url}
+	}
+	category := &DependencyCategory{ID: 1, Status: online("/categories/1")}
+	articles := []any{
+		&DependencyArticle{ID: 1, CategoryID: 1, Status: online("/articles/1")},
+		&DependencyArticle{ID: 2, CategoryID: 1, Status: publish.Status{Status: publish.StatusDraft}},
+		&DependencyArticle{ID: 3, CategoryID: 1, Status: online("/articles/3")},
+	}
+	feed := &DependencyFeed{ID: 1, Status: online("/feed")}
+
+	pb := publish.New(TestDB, nil).
+		DependsOn(&DependencyArticle{}, &DependencyCategory{}, func(_ context.Context, _ *gorm.DB, dependency any) ([]any, error) {
+			return articles, nil
+		}).
+		DependsOn(&DependencyFeed{}, &DependencyArticle{}, func(_ context.Context, _ *gorm.DB, dependency any) ([]any, error) {
+			return []any{feed}, nil
+		}).
+		// cycles are visited once
+		DependsOn(&DependencyCategory{}, &DependencyFeed{}, func(_ context.Context, _ *gorm.DB, dependency any) ([]any, error) {
+			return []any{category}, nil
+		})
+
+	require.True(t, pb.HasDependents(category))
+	dependents, err := pb.FindDependents(context.Background(), category)
+	require.NoError(t, err)
+	require.Equal(t, []*publish.Dependent{
+		{Model: "publish_test.DependencyArticle", Slug: "1", URL: "/articles/1"},
+		{Model: "publish_test.DependencyArticle", Slug: "3", URL: "/articles/3"},
+		{Model: "publish_test.DependencyFeed", Slug: "1", URL: "/feed"},
+	}, dependents)
+}
+
+func TestRepublishDependentsSkipsUnapproved(t *testing.T) {
+	require.NoError(t, TestDB.AutoMigrate(&ReviewProduct{}))
+	approved := &ReviewProduct{ID: 101, Name: "approved", Status: publish.Status{Status: publish.StatusOnline},
+		Review: publish.Review{ReviewStatus: publish.ReviewStatusApproved}}
+	edited := &ReviewProduct{ID: 102, Name: "edited", Status: publish.Status{Status: publish.StatusOnline}}
+	require.NoError(t, TestDB.Save(approved).Error)
+	require.NoError(t, TestDB.Save(edited).Error)
+
+	storage := &MockStorage{Objects: map[string]string{}}
+	pb := publish.New(TestDB, storage).
+		DependsOn(&ReviewProduct{}, &DependencyCategory{}, func(_ context.Context, _ *gorm.DB, _ any) ([]any, error) {
+			return []any{approved, edited}, nil
+		})
+	dependents, err := pb.FindDependents(context.Background(), &DependencyCategory{ID: 1})
+	require.NoError(t, err)
+	require.Len(t, dependents, 2)
+
+	var logs []string
+	skipped, err := pb.RepublishDependents(context.Background(), dependents, func(dependent *publish.Dependent, _ int, skipped bool) {
+		logs = append(logs, fmt.Sprintf("%s %v", dependent.Slug, skipped))
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*publish.Dependent{dependents[1]}, skipped)
+	require.Equal(t, []string{"101 false", "102 true"}, logs)
+	require.Equal(t, map[string]string{"test/review_product/101/index.html": "approved"}, storage.Objects)
+}
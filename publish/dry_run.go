@@ -0,0 +1,164 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// maxDiffSize is the max size of the contents to compute the lines diff.
+const maxDiffSize = 512 * 1024
+
+type PublishDiffKind string
+
+const (
+	PublishDiffAdded     PublishDiffKind = "added"
+	PublishDiffChanged   PublishDiffKind = "changed"
+	PublishDiffDeleted   PublishDiffKind = "deleted"
+	PublishDiffUnchanged PublishDiffKind = "unchanged"
+)
+
+type DiffLineOp string
+
+const (
+	DiffLineEqual  DiffLineOp = " "
+	DiffLineInsert DiffLineOp = "+"
+	DiffLineDelete DiffLineOp = "-"
+)
+
+type DiffLine struct {
+	Op   DiffLineOp
+	Text string
+}
+
+// PublishDiff is the change of the object at the Url of the storage made by the PublishActions.
+type PublishDiff struct {
+	Url  string
+	Kind PublishDiffKind
+	// Live is the content in the storage, Content is the content after the PublishActions are executed.
+	Live    string
+	Content string
+	// Lines is the lines diff of a changed object, it is nil if the contents are larger than maxDiffSize.
+	Lines []*DiffLine
+}
+
+// DryRunPublish computes the PublishActions which Publish would execute and compares them with the live objects
+// of the storage, nothing is uploaded. The record may be modified by the PublishActions the same as Publish.
+func (b *Builder) DryRunPublish(ctx context.Context, record any) ([]*PublishDiff, error) {
+	actions, err := b.getPublishActions(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	return b.DiffActions(ctx, actions)
+}
+
+// DryRunUnPublish is the same as DryRunPublish for the PublishActions of UnPublish.
+func (b *Builder) DryRunUnPublish(ctx context.Context, record any) ([]*PublishDiff, error) {
+	actions, err := b.getUnPublishActions(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	return b.DiffActions(ctx, actions)
+}
+
+// DiffActions returns the diffs of the objects by the Url in the order of the actions,
+// the actions of the same Url are applied in order the same as UploadOrDelete.
+func (b *Builder) DiffActions(ctx context.Context, actions []*PublishAction) (r []*PublishDiff, err error) {
+	type object struct {
+		diff       *PublishDiff
+		liveExists bool
+		exists     bool
+	}
+	objects := map[string]*object{}
+	for _, action := range actions {
+		obj, ok := objects[action.Url]
+		if !ok {
+			obj = &object{diff: &PublishDiff{Url: action.Url}}
+			if obj.diff.Live, obj.liveExists, err = b.getLiveContent(ctx, action.Url); err != nil {
+				return nil, err
+			}
+			obj.exists = obj.liveExists
+			obj.diff.Content = obj.diff.Live
+			objects[action.Url] = obj
+			r = append(r, obj.diff)
+		}
+		if action.IsDelete {
+			obj.exists = false
+			obj.diff.Content = ""
+			continue
+		}
+		obj.exists = true
+		obj.diff.Content = action.Content
+	}
+
+	for _, d := range r {
+		obj := objects[d.Url]
+		switch {
+		case !obj.liveExists && !obj.exists:
+			d.Kind = PublishDiffUnchanged
+		case !obj.liveExists:
+			d.Kind = PublishDiffAdded
+		case !obj.exists:
+			d.Kind = PublishDiffDeleted
+		case d.Live == d.Content:
+			d.Kind = PublishDiffUnchanged
+		default:
+			d.Kind = PublishDiffChanged
+		}
+		if d.Kind != PublishDiffUnchanged && len(d.Live) <= maxDiffSize && len(d.Content) <= maxDiffSize {
+			d.Lines = diffLines(d.Live, d.Content)
+		}
+	}
+	return
+}
+
+func (b *Builder) getLiveContent(ctx context.Context, url string) (content string, exists bool, err error) {
+	if b.storage == nil {
+		return "", false, nil
+	}
+	rc, err := b.storage.GetStream(ctx, url)
+	if err != nil {
+		if isObjectNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
+
+func isObjectNotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	return errors.Is(err, fs.ErrNotExist) || errors.As(err, &noSuchKey) || strings.Contains(err.Error(), "NoSuchKey")
+}
+
+func diffLines(live, content string) (r []*DiffLine) {
+	dmp := diffmatchpatch.New()
+	chars1, chars2, lines := dmp.DiffLinesToChars(live, content)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(chars1, chars2, false), lines)
+	for _, d := range diffs {
+		op := DiffLineEqual
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = DiffLineInsert
+		case diffmatchpatch.DiffDelete:
+			op = DiffLineDelete
+		}
+		for _, line := range strings.SplitAfter(d.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			r = append(r, &DiffLine{Op: op, Text: strings.TrimSuffix(line, "\n")})
+		}
+	}
+	return
+}
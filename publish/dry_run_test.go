@@ -0,0 +1,48 @@
+package publish_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qor5/admin/v3/publish"
+)
+
+func TestDiffActions(t *testing.T) {
+	storage := &MockStorage{Objects: map[string]string{
+		"changed.html":   "a\nb\nc\n",
+		"unchanged.html": "same",
+		"deleted.html":   "gone",
+	}}
+	pb := publish.New(TestDB, storage)
+
+	diffs, err := pb.DiffActions(context.Background(), []*publish.PublishAction{
+		{Url: "added.html", Content: "new"},
+		{Url: "changed.html", Content: "a\nB\nc\n"},
+		{Url: "unchanged.html", Content: "same"},
+		{Url: "deleted.html", IsDelete: true},
+		{Url: "missing.html", IsDelete: true},
+	})
+	require.NoError(t, err)
+
+	kinds := map[string]publish.PublishDiffKind{}
+	for _, d := range diffs {
+		kinds[d.Url] = d.Kind
+	}
+	require.Equal(t, map[string]publish.PublishDiffKind{
+		"added.html":     publish.PublishDiffAdded,
+		"changed.html":   publish.PublishDiffChanged,
+		"unchanged.html": publish.PublishDiffUnchanged,
+		"deleted.html":   publish.PublishDiffDeleted,
+		"missing.html":   publish.PublishDiffUnchanged,
+	}, kinds)
+	require.Equal(t, []*publish.DiffLine{
+		{Op: publish.DiffLineEqual, Text: "a"},
+		{Op: publish.DiffLineDelete, Text: "b"},
+		{Op: publish.DiffLineInsert, Text: "B"},
+		{Op: publish.DiffLineEqual, Text: "c"},
+	}, diffs[1].Lines)
+	// nothing is uploaded
+	require.Len(t, storage.Objects, 3)
+}
@@ -18,12 +18,29 @@ const (
 	eventRenameVersion       = "publish_eventRenameVersion"
 	eventDeleteVersionDialog = "publish_eventDeleteVersionDialog"
 	eventDeleteVersion       = "publish_eventDeleteVersion"
+	eventCompareVersions     = "publish_eventCompareVersions"
+	eventMergeVersion        = "publish_eventMergeVersion"
+
+	eventSubmitReviewDialog = "publish_eventSubmitReviewDialog"
+	eventSubmitReview       = "publish_eventSubmitReview"
+	eventReviewDialog       = "publish_eventReviewDialog"
+	eventReview             = "publish_eventReview"
+
+	eventDependentsPreview  = "publish_eventDependentsPreview"
+	eventPublishDiffPreview = "publish_eventPublishDiffPreview"
 
 	ActivityPublish   = "Publish"
 	ActivityRepublish = "Republish"
 	ActivityUnPublish = "UnPublish"
 
+	ActivitySubmitReview  = "SubmitForReview"
+	ActivityApproveReview = "ApproveReview"
+	ActivityRejectReview  = "RejectReview"
+
+	ActivityMergeVersion = "MergeVersion"
+
 	ParamScriptAfterPublish = "publish_param_script_after_publish"
+	paramPublishAction      = "publish_param_action"
 )
 
 func registerEventFuncsForResource(db *gorm.DB, mb *presets.ModelBuilder, publisher *Builder) {
@@ -34,11 +51,21 @@ func registerEventFuncsForResource(db *gorm.DB, mb *presets.ModelBuilder, publis
 	mb.RegisterEventFunc(EventDuplicateVersion, duplicateVersionAction(mb, db))
 	mb.RegisterEventFunc(eventSchedulePublishDialog, scheduleDialog(db, mb))
 	mb.RegisterEventFunc(eventSchedulePublish, schedule(db, mb))
+
+	mb.RegisterEventFunc(eventSubmitReviewDialog, submitReviewDialog(mb, publisher))
+	mb.RegisterEventFunc(eventSubmitReview, submitReview(mb, publisher))
+	mb.RegisterEventFunc(eventReviewDialog, reviewDialog(mb, publisher))
+	mb.RegisterEventFunc(eventReview, reviewAction(db, mb, publisher))
+
+	mb.RegisterEventFunc(eventDependentsPreview, dependentsPreview(mb, publisher))
+	mb.RegisterEventFunc(eventPublishDiffPreview, publishDiffPreview(mb, publisher))
 }
 
-func registerEventFuncsForVersion(mb *presets.ModelBuilder, db *gorm.DB) {
+func registerEventFuncsForVersion(mb, pm *presets.ModelBuilder, db *gorm.DB, publisher *Builder) {
 	mb.RegisterEventFunc(eventRenameVersionDialog, renameVersionDialog(mb))
 	mb.RegisterEventFunc(eventRenameVersion, renameVersion(mb))
 	mb.RegisterEventFunc(eventDeleteVersionDialog, deleteVersionDialog(mb))
 	mb.RegisterEventFunc(eventDeleteVersion, deleteVersion(mb, db))
+	mb.RegisterEventFunc(eventCompareVersions, compareVersions(mb, pm, publisher))
+	mb.RegisterEventFunc(eventMergeVersion, mergeVersion(mb, pm, publisher))
 }
@@ -49,6 +49,25 @@ type Status struct {
 
 // @snippet_end
 
+// @snippet_begin(PublishReview)
+var (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// Review enables the review workflow, the record has to be submitted for review and approved before it is published.
+type Review struct {
+	ReviewStatus  string
+	SubmitterID   string
+	ReviewerID    string
+	ReviewerName  string
+	ReviewComment string
+	ReviewedAt    *time.Time
+}
+
+// @snippet_end
+
 // @snippet_begin(PublishVersion)
 type Version struct {
 	Version       string `gorm:"primaryKey;size:128;not null;"`
@@ -99,6 +118,22 @@ func EmbedStatus(v any) *Status {
 	return iface.EmbedStatus()
 }
 
+type ReviewInterface interface {
+	EmbedReview() *Review
+}
+
+func (s *Review) EmbedReview() *Review {
+	return s
+}
+
+func EmbedReview(v any) *Review {
+	iface, ok := v.(ReviewInterface)
+	if !ok {
+		return nil
+	}
+	return iface.EmbedReview()
+}
+
 type VersionInterface interface {
 	EmbedVersion() *Version
 }
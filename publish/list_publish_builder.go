@@ -102,15 +102,16 @@ func (b *ListPublishBuilder) Run(ctx context.Context, model interface{}) (err er
 	// Generate a records: []*Product{}
 	records := reflect.MakeSlice(reflect.SliceOf(reflect.New(reflect.TypeOf(model)).Type()), 0, 0).Interface()
 
-	addItems, err := getAddItems(b.db, records)
+	db := b.db.WithContext(ctx)
+	addItems, err := getAddItems(db, records)
 	if err != nil {
 		return
 	}
-	deleteItems, err := getDeleteItems(b.db, records)
+	deleteItems, err := getDeleteItems(db, records)
 	if err != nil {
 		return
 	}
-	republishItems, err := getRepublishItems(b.db, records)
+	republishItems, err := getRepublishItems(db, records)
 	if err != nil {
 		return
 	}
@@ -1,6 +1,9 @@
 package publish
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 type Messages struct {
 	StatusDraft                             string
@@ -60,6 +63,70 @@ type Messages struct {
 
 	HeaderDraftCount string
 	HeaderLive       string
+
+	DashboardScheduled   string
+	DashboardNoScheduled string
+
+	SubmitForReview             string
+	Review                      string
+	Approve                     string
+	Reject                      string
+	Reviewer                    string
+	ReviewComment               string
+	ReviewStatusPending         string
+	ReviewStatusApproved        string
+	ReviewStatusRejected        string
+	ReviewRequired              string
+	ReviewerRequired            string
+	ReviewCommentRequired       string
+	NotAssignedReviewer         string
+	SubmitterCannotReview       string
+	SuccessfullySubmitForReview string
+	SuccessfullyApprove         string
+	SuccessfullyReject          string
+
+	DashboardPublishJobs   string
+	DashboardNoPublishJobs string
+	PublishJobLastRun      string
+	PublishJobNextRun      string
+
+	DependentsToRepublishTemplate string
+	RepublishDependentsFailed     string
+	DependentsNotApprovedTemplate string
+	Releases                      string
+	Release                       string
+	ReleaseItems                  string
+	AddToRelease                  string
+	RemoveFromRelease             string
+	PublishRelease                string
+	ConfirmPublishRelease         string
+	SuccessfullyAddToRelease      string
+	NoDraftRelease                string
+	ReleaseEmpty                  string
+	ReleaseNameRequired           string
+	ReleaseAlreadyPublished       string
+	ReleaseBeingPublished         string
+	ReleaseStatusScheduled        string
+	ReleaseStatusPublishing       string
+	ReleaseStatusPublished        string
+	ReleaseStatusFailed           string
+
+	PublishChanges                  string
+	PublishDiffAdded                string
+	PublishDiffChanged              string
+	PublishDiffDeleted              string
+	PublishDiffUnchanged            string
+	NoPublishChanges                string
+	PublishDiffTooLarge             string
+	PublishDiffSkippedLinesTemplate string
+
+	Compare                  string
+	CompareVersions          string
+	CurrentVersion           string
+	VersionField             string
+	NoVersionDifferences     string
+	MergeIntoDraft           string
+	SuccessfullyMergeVersion string
 }
 
 func (msgr *Messages) DeleteVersionConfirmationText(versionName string) string {
@@ -81,6 +148,21 @@ func (msgr *Messages) ToStatusOffline(versionName, scheduleTime string) string {
 	).Replace(msgr.ToStatusOfflineTemplate)
 }
 
+func (msgr *Messages) PublishDiffSkippedLines(count int) string {
+	return strings.NewReplacer("{Count}", fmt.Sprint(count)).
+		Replace(msgr.PublishDiffSkippedLinesTemplate)
+}
+
+func (msgr *Messages) DependentsToRepublish(count int) string {
+	return strings.NewReplacer("{Count}", fmt.Sprint(count)).
+		Replace(msgr.DependentsToRepublishTemplate)
+}
+
+func (msgr *Messages) DependentsNotApproved(count int) string {
+	return strings.NewReplacer("{Count}", fmt.Sprint(count)).
+		Replace(msgr.DependentsNotApprovedTemplate)
+}
+
 var Messages_en_US = &Messages{
 	StatusDraft:                             "Draft",
 	StatusOnline:                            "Online",
@@ -139,6 +221,70 @@ var Messages_en_US = &Messages{
 
 	HeaderDraftCount: "Draft Count",
 	HeaderLive:       "Live",
+
+	DashboardScheduled:   "Scheduled This Week",
+	DashboardNoScheduled: "Nothing is scheduled this week",
+
+	SubmitForReview:             "Submit for Review",
+	Review:                      "Review",
+	Approve:                     "Approve",
+	Reject:                      "Reject",
+	Reviewer:                    "Reviewer",
+	ReviewComment:               "Comment",
+	ReviewStatusPending:         "In Review",
+	ReviewStatusApproved:        "Approved",
+	ReviewStatusRejected:        "Rejected",
+	ReviewRequired:              "Only approved versions can be published",
+	ReviewerRequired:            "Please select a reviewer",
+	ReviewCommentRequired:       "Please leave a comment for the rejection",
+	NotAssignedReviewer:         "Only the assigned reviewer can review it",
+	SubmitterCannotReview:       "The submitter can not review it",
+	SuccessfullySubmitForReview: "Successfully Submitted for Review",
+	SuccessfullyApprove:         "Successfully Approved",
+	SuccessfullyReject:          "Successfully Rejected",
+
+	DashboardPublishJobs:   "Publish Jobs",
+	DashboardNoPublishJobs: "The publish scheduler has not run yet",
+	PublishJobLastRun:      "Last run",
+	PublishJobNextRun:      "Next run",
+
+	DependentsToRepublishTemplate: "{Count} online pages depending on it will be republished:",
+	RepublishDependentsFailed:     "Published, but the pages depending on it failed to be republished",
+	DependentsNotApprovedTemplate: "Published, but {Count} pages depending on it are not republished as their reviews are not approved",
+	Releases:                      "Releases",
+	Release:                       "Release",
+	ReleaseItems:                  "Items",
+	AddToRelease:                  "Add to Release",
+	RemoveFromRelease:             "Remove from Release",
+	PublishRelease:                "Publish Release",
+	ConfirmPublishRelease:         "All the items of the release will be published together, nothing is published if any of them fails. Continue?",
+	SuccessfullyAddToRelease:      "Added to the release",
+	NoDraftRelease:                "No draft release, please create a release first",
+	ReleaseEmpty:                  "No items in the release",
+	ReleaseNameRequired:           "Name is required",
+	ReleaseAlreadyPublished:       "The release is already published",
+	ReleaseBeingPublished:         "The release is being published or already published",
+	ReleaseStatusScheduled:        "Scheduled",
+	ReleaseStatusPublishing:       "Publishing",
+	ReleaseStatusPublished:        "Published",
+	ReleaseStatusFailed:           "Failed",
+
+	PublishChanges:                  "Changes to the live site",
+	PublishDiffAdded:                "Added",
+	PublishDiffChanged:              "Changed",
+	PublishDiffDeleted:              "Deleted",
+	PublishDiffUnchanged:            "Unchanged",
+	NoPublishChanges:                "No changes to the live site",
+	PublishDiffTooLarge:             "The content is too large to compare",
+	PublishDiffSkippedLinesTemplate: "{Count} unchanged lines",
+
+	Compare:                  "Compare",
+	CompareVersions:          "Compare Versions",
+	CurrentVersion:           "current",
+	VersionField:             "Field",
+	NoVersionDifferences:     "No differences between the versions",
+	MergeIntoDraft:           "Merge into Current Draft",
+	SuccessfullyMergeVersion: "Successfully merged",
 }
 
 var Messages_zh_CN = &Messages{
@@ -199,6 +345,70 @@ var Messages_zh_CN = &Messages{
 
 	HeaderDraftCount: "草稿数",
 	HeaderLive:       "发布状态",
+
+	DashboardScheduled:   "本周排期",
+	DashboardNoScheduled: "本周没有排期",
+
+	SubmitForReview:             "提交审核",
+	Review:                      "审核",
+	Approve:                     "通过",
+	Reject:                      "驳回",
+	Reviewer:                    "审核人",
+	ReviewComment:               "审核意见",
+	ReviewStatusPending:         "审核中",
+	ReviewStatusApproved:        "已通过",
+	ReviewStatusRejected:        "已驳回",
+	ReviewRequired:              "只有审核通过的版本才能发布",
+	ReviewerRequired:            "请选择审核人",
+	ReviewCommentRequired:       "驳回时请填写审核意见",
+	NotAssignedReviewer:         "只有指定的审核人才能审核",
+	SubmitterCannotReview:       "提交人不能审核自己的提交",
+	SuccessfullySubmitForReview: "提交审核成功",
+	SuccessfullyApprove:         "审核通过",
+	SuccessfullyReject:          "已驳回",
+
+	DashboardPublishJobs:   "发布任务",
+	DashboardNoPublishJobs: "发布调度器尚未运行",
+	PublishJobLastRun:      "上次运行",
+	PublishJobNextRun:      "下次运行",
+
+	DependentsToRepublishTemplate: "依赖它的 {Count} 个已发布页面将被重新发布：",
+	RepublishDependentsFailed:     "已发布，但依赖它的页面重新发布失败",
+	DependentsNotApprovedTemplate: "已发布，但依赖它的 {Count} 个页面未通过审核，没有重新发布",
+	Releases:                      "发布包",
+	Release:                       "发布包",
+	ReleaseItems:                  "内容",
+	AddToRelease:                  "加入发布包",
+	RemoveFromRelease:             "从发布包移除",
+	PublishRelease:                "发布发布包",
+	ConfirmPublishRelease:         "发布包中的所有内容将一起发布，任何一项失败都不会发布。是否继续？",
+	SuccessfullyAddToRelease:      "已加入发布包",
+	NoDraftRelease:                "没有草稿发布包，请先创建发布包",
+	ReleaseEmpty:                  "发布包中没有内容",
+	ReleaseNameRequired:           "名称不能为空",
+	ReleaseAlreadyPublished:       "发布包已发布",
+	ReleaseBeingPublished:         "发布包正在发布或已发布",
+	ReleaseStatusScheduled:        "已计划",
+	ReleaseStatusPublishing:       "发布中",
+	ReleaseStatusPublished:        "已发布",
+	ReleaseStatusFailed:           "失败",
+
+	PublishChanges:                  "线上站点的变更",
+	PublishDiffAdded:                "新增",
+	PublishDiffChanged:              "修改",
+	PublishDiffDeleted:              "删除",
+	PublishDiffUnchanged:            "未变更",
+	NoPublishChanges:                "线上站点没有变更",
+	PublishDiffTooLarge:             "内容过大，无法比较",
+	PublishDiffSkippedLinesTemplate: "{Count} 行未变更",
+
+	Compare:                  "比较",
+	CompareVersions:          "比较版本",
+	CurrentVersion:           "当前",
+	VersionField:             "字段",
+	NoVersionDifferences:     "版本之间没有差异",
+	MergeIntoDraft:           "合并到当前草稿",
+	SuccessfullyMergeVersion: "合并成功",
 }
 
 var Messages_ja_JP = &Messages{
@@ -259,4 +469,68 @@ var Messages_ja_JP = &Messages{
 
 	HeaderDraftCount: "下書き数",
 	HeaderLive:       "公開ステータス",
+
+	DashboardScheduled:   "今週の予定",
+	DashboardNoScheduled: "今週の予定はありません",
+
+	SubmitForReview:             "レビュー申請",
+	Review:                      "レビュー",
+	Approve:                     "承認",
+	Reject:                      "差し戻し",
+	Reviewer:                    "レビュアー",
+	ReviewComment:               "コメント",
+	ReviewStatusPending:         "レビュー中",
+	ReviewStatusApproved:        "承認済み",
+	ReviewStatusRejected:        "差し戻し済み",
+	ReviewRequired:              "承認されたバージョンのみ公開できます",
+	ReviewerRequired:            "レビュアーを選択してください",
+	ReviewCommentRequired:       "差し戻しの理由をコメントしてください",
+	NotAssignedReviewer:         "指定されたレビュアーのみレビューできます",
+	SubmitterCannotReview:       "提出者は自分の提出をレビューできません",
+	SuccessfullySubmitForReview: "レビュー申請しました",
+	SuccessfullyApprove:         "承認しました",
+	SuccessfullyReject:          "差し戻しました",
+
+	DashboardPublishJobs:   "公開ジョブ",
+	DashboardNoPublishJobs: "公開スケジューラーはまだ実行されていません",
+	PublishJobLastRun:      "前回の実行",
+	PublishJobNextRun:      "次回の実行",
+
+	DependentsToRepublishTemplate: "これに依存する公開中の {Count} ページが再公開されます：",
+	RepublishDependentsFailed:     "公開しましたが、これに依存するページの再公開に失敗しました",
+	DependentsNotApprovedTemplate: "公開しましたが、これに依存する {Count} ページは承認されていないため再公開されませんでした",
+	Releases:                      "リリース",
+	Release:                       "リリース",
+	ReleaseItems:                  "アイテム",
+	AddToRelease:                  "リリースに追加",
+	RemoveFromRelease:             "リリースから削除",
+	PublishRelease:                "リリースを公開",
+	ConfirmPublishRelease:         "リリースのすべてのアイテムがまとめて公開されます。いずれかが失敗した場合は何も公開されません。続行しますか？",
+	SuccessfullyAddToRelease:      "リリースに追加されました",
+	NoDraftRelease:                "下書きのリリースがありません。先にリリースを作成してください",
+	ReleaseEmpty:                  "リリースにアイテムがありません",
+	ReleaseNameRequired:           "名前は必須です",
+	ReleaseAlreadyPublished:       "リリースはすでに公開されています",
+	ReleaseBeingPublished:         "リリースは公開中か、すでに公開されています",
+	ReleaseStatusScheduled:        "予定済み",
+	ReleaseStatusPublishing:       "公開中",
+	ReleaseStatusPublished:        "公開済み",
+	ReleaseStatusFailed:           "失敗",
+
+	PublishChanges:                  "公開サイトへの変更",
+	PublishDiffAdded:                "追加",
+	PublishDiffChanged:              "変更",
+	PublishDiffDeleted:              "削除",
+	PublishDiffUnchanged:            "変更なし",
+	NoPublishChanges:                "公開サイトへの変更はありません",
+	PublishDiffTooLarge:             "コンテンツが大きすぎるため比較できません",
+	PublishDiffSkippedLinesTemplate: "変更のない {Count} 行",
+
+	Compare:                  "比較",
+	CompareVersions:          "バージョンを比較",
+	CurrentVersion:           "現在",
+	VersionField:             "フィールド",
+	NoVersionDifferences:     "バージョン間に違いはありません",
+	MergeIntoDraft:           "現在の下書きにマージ",
+	SuccessfullyMergeVersion: "マージしました",
 }
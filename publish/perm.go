@@ -12,6 +12,9 @@ const (
 	PermUnpublish = "publish:unpublish"
 	PermSchedule  = "publish:schedule"  // Prerequisite: PermPublish/PermUnpublish
 	PermDuplicate = "publish:duplicate" // Prerequisite: presets.PermUpdate
+
+	PermSubmitReview = "publish:submit_review" // Prerequisite: presets.PermUpdate
+	PermReview       = "publish:review"
 )
 
 func DeniedDo(verifier *perm.Verifier, obj any, r *http.Request, actions ...string) bool {
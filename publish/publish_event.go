@@ -1,11 +1,14 @@
 package publish
 
 import (
+	"fmt"
+
 	"github.com/qor5/admin/v3/presets"
 	"github.com/qor5/web/v3"
 	"github.com/qor5/x/v3/i18n"
 	"github.com/qor5/x/v3/perm"
 	v "github.com/qor5/x/v3/ui/vuetify"
+	h "github.com/theplant/htmlgo"
 	"gorm.io/gorm"
 )
 
@@ -22,6 +25,11 @@ func publishAction(_ *gorm.DB, mb *presets.ModelBuilder, publisher *Builder, act
 		if DeniedDo(mb.Info().Verifier(), obj, ctx.R, PermPublish) {
 			return r, perm.PermissionDenied
 		}
+		if !ReviewApproved(obj) {
+			msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+			presets.ShowMessage(&r, msgr.ReviewRequired, "error")
+			return
+		}
 
 		reqCtx := publisher.WithContextValues(ctx.R.Context())
 		err = publisher.Publish(reqCtx, obj)
@@ -33,16 +41,23 @@ func publishAction(_ *gorm.DB, mb *presets.ModelBuilder, publisher *Builder, act
 				amb.Log(ctx.R.Context(), actionName, obj, nil)
 			}
 		}
-
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		warning := publisher.republishDependentsAfterAction(ctx, obj)
 		if script := ctx.R.FormValue(ParamScriptAfterPublish); script != "" {
+			if warning != "" {
+				presets.ShowMessage(&r, warning, v.ColorWarning)
+			}
 			web.AppendRunScripts(&r, script)
-		} else {
-			msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
-			web.AppendRunScripts(&r, web.Plaid().MergeQuery(true).
-				ThenScript(presets.ShowSnackbarScript(msgr.SuccessfullyPublish, v.ColorSuccess)).
-				Go(),
-			)
+			return
+		}
+		message, color := msgr.SuccessfullyPublish, v.ColorSuccess
+		if warning != "" {
+			message, color = warning, v.ColorWarning
 		}
+		web.AppendRunScripts(&r, web.Plaid().MergeQuery(true).
+			ThenScript(presets.ShowSnackbarScript(message, color)).
+			Go(),
+		)
 		return
 	}
 }
@@ -71,12 +86,172 @@ func unpublishAction(_ *gorm.DB, mb *presets.ModelBuilder, publisher *Builder, a
 				amb.Log(ctx.R.Context(), actionName, obj, nil)
 			}
 		}
-
 		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		message, color := msgr.SuccessfullyUnPublish, v.ColorSuccess
+		if warning := publisher.republishDependentsAfterAction(ctx, obj); warning != "" {
+			message, color = warning, v.ColorWarning
+		}
 		web.AppendRunScripts(&r, web.Plaid().MergeQuery(true).
-			ThenScript(presets.ShowSnackbarScript(msgr.SuccessfullyUnPublish, v.ColorSuccess)).
+			ThenScript(presets.ShowSnackbarScript(message, color)).
 			Go(),
 		)
 		return
 	}
 }
+
+// dependentsPreview lists the dependents to be republished in the confirm dialog of publish/unpublish/republish,
+// only to the users allowed to do the action.
+func dependentsPreview(mb *presets.ModelBuilder, publisher *Builder) web.EventFunc {
+	return func(ctx *web.EventContext) (r web.EventResponse, err error) {
+		r.Body = h.Components()
+		obj := mb.NewModel()
+		obj, err = mb.Editing().Fetcher(obj, ctx.Param(presets.ParamID), ctx)
+		if err != nil || !publisher.HasDependents(obj) {
+			return
+		}
+		permAction := PermPublish
+		if ctx.R.FormValue(paramPublishAction) == EventUnpublish {
+			permAction = PermUnpublish
+		}
+		if DeniedDo(mb.Info().Verifier(), obj, ctx.R, permAction) {
+			return
+		}
+		dependents, err := publisher.FindDependents(ctx.R.Context(), obj)
+		if err != nil || len(dependents) == 0 {
+			return
+		}
+
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		var items []h.HTMLComponent
+		for _, dependent := range dependents {
+			title := dependent.URL
+			if title == "" {
+				title = fmt.Sprintf("%s %s", dependent.Model, dependent.Slug)
+			}
+			items = append(items, v.VListItem(v.VListItemTitle(h.Text(title)).Class("text-body-2")))
+		}
+		r.Body = h.Div(
+			h.Div(h.Text(msgr.DependentsToRepublish(len(dependents)))).Class("text-body-2 mb-2"),
+			v.VList(items...).Density(v.DensityCompact).MaxHeight(200).Class("overflow-y-auto border rounded"),
+		).Class("mt-4")
+		return
+	}
+}
+
+// publishDiffPreview lists the objects of the storage changed by publish/unpublish/republish in the confirm dialog,
+// with the lines diff of the changed contents.
+func publishDiffPreview(mb *presets.ModelBuilder, publisher *Builder) web.EventFunc {
+	return func(ctx *web.EventContext) (r web.EventResponse, err error) {
+		r.Body = h.Components()
+		obj := mb.NewModel()
+		obj, err = mb.Editing().Fetcher(obj, ctx.Param(presets.ParamID), ctx)
+		if err != nil || publisher.storage == nil {
+			return
+		}
+
+		var diffs []*PublishDiff
+		reqCtx := publisher.WithContextValues(ctx.R.Context())
+		if ctx.R.FormValue(paramPublishAction) == EventUnpublish {
+			if DeniedDo(mb.Info().Verifier(), obj, ctx.R, PermUnpublish) {
+				return
+			}
+			diffs, err = publisher.DryRunUnPublish(reqCtx, obj)
+		} else {
+			if DeniedDo(mb.Info().Verifier(), obj, ctx.R, PermPublish) {
+				return
+			}
+			diffs, err = publisher.DryRunPublish(reqCtx, obj)
+		}
+		if err != nil {
+			r.Body = h.Div(h.Text(err.Error())).Class("text-error text-body-2 mt-4")
+			return r, nil
+		}
+		r.Body = publishDiffsComponent(i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages), diffs)
+		return
+	}
+}
+
+func publishDiffsComponent(msgr *Messages, diffs []*PublishDiff) h.HTMLComponent {
+	kinds := map[PublishDiffKind]struct {
+		label string
+		color string
+	}{
+		PublishDiffAdded:   {msgr.PublishDiffAdded, v.ColorSuccess},
+		PublishDiffChanged: {msgr.PublishDiffChanged, v.ColorWarning},
+		PublishDiffDeleted: {msgr.PublishDiffDeleted, v.ColorError},
+	}
+
+	var panels []h.HTMLComponent
+	for _, d := range diffs {
+		kind, ok := kinds[d.Kind]
+		if !ok {
+			continue
+		}
+		var content h.HTMLComponent = h.Div(h.Text(msgr.PublishDiffTooLarge)).Class("text-caption text-medium-emphasis")
+		if d.Lines != nil {
+			content = publishDiffLinesComponent(msgr, d.Lines)
+		}
+		panels = append(panels, v.VExpansionPanel(
+			v.VExpansionPanelTitle(
+				v.VChip(h.Text(kind.label)).Color(kind.color).Size(v.SizeSmall).Variant(v.VariantTonal).Class("mr-2"),
+				h.Span(d.Url).Class("text-body-2 text-truncate"),
+			),
+			v.VExpansionPanelText(content),
+		))
+	}
+
+	var body h.HTMLComponent = h.Div(h.Text(msgr.NoPublishChanges)).Class("text-body-2 text-medium-emphasis")
+	if len(panels) > 0 {
+		body = v.VExpansionPanels(panels...).Variant("accordion").Class("overflow-y-auto").Attr("style", "max-height: 320px;")
+	}
+	return h.Div(
+		h.Div(h.Text(msgr.PublishChanges)).Class("text-body-2 mb-2"),
+		body,
+	).Class("mt-4")
+}
+
+// publishDiffContextLines is the number of the unchanged lines shown around the changed lines.
+const publishDiffContextLines = 3
+
+func publishDiffLinesComponent(msgr *Messages, lines []*DiffLine) h.HTMLComponent {
+	var rows []h.HTMLComponent
+	for i := 0; i < len(lines); i++ {
+		if lines[i].Op == DiffLineEqual {
+			j := i
+			for j < len(lines) && lines[j].Op == DiffLineEqual {
+				j++
+			}
+			head, tail := i+publishDiffContextLines, j-publishDiffContextLines
+			if i == 0 {
+				head = i
+			}
+			if j == len(lines) {
+				tail = j
+			}
+			if tail-head > 1 {
+				for _, line := range lines[i:head] {
+					rows = append(rows, publishDiffLineComponent(line))
+				}
+				rows = append(rows, h.Div(h.Text(msgr.PublishDiffSkippedLines(tail-head))).Class("text-medium-emphasis px-2"))
+				for _, line := range lines[tail:j] {
+					rows = append(rows, publishDiffLineComponent(line))
+				}
+				i = j - 1
+				continue
+			}
+		}
+		rows = append(rows, publishDiffLineComponent(lines[i]))
+	}
+	return h.Tag("pre").Children(rows...).Class("text-caption overflow-x-auto border rounded").Style("white-space: pre;")
+}
+
+func publishDiffLineComponent(line *DiffLine) h.HTMLComponent {
+	class := "px-2"
+	switch line.Op {
+	case DiffLineInsert:
+		class += " bg-green-lighten-5"
+	case DiffLineDelete:
+		class += " bg-red-lighten-5"
+	}
+	return h.Div(h.Text(string(line.Op) + " " + line.Text)).Class(class)
+}
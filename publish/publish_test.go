@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -188,6 +189,14 @@ func (m *MockStorage) Get(ctx context.Context, path string) (f *os.File, err err
 	return
 }
 
+func (m *MockStorage) GetStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	content, exist := m.Objects[path]
+	if !exist {
+		return nil, fmt.Errorf("NoSuchKey: %s", path)
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
 func (m *MockStorage) Put(ctx context.Context, path string, r io.Reader) (*oss.Object, error) {
 	fmt.Println("Calling mock s3 client - Put: ", path)
 	b, err := io.ReadAll(r)
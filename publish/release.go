@@ -0,0 +1,258 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/utils"
+)
+
+const releasePublishJobName = "release-publisher"
+
+var (
+	ReleaseStatusDraft      = "draft"
+	ReleaseStatusPublishing = "publishing"
+	ReleaseStatusPublished  = "published"
+	ReleaseStatusFailed     = "failed"
+)
+
+// errReleaseClaimed is returned when the release is published or being published by another request or replica.
+var errReleaseClaimed = errors.New("the release is being published or already published")
+
+// Release is a bundle of the specific versions of records of different models, which are published together,
+// all the status updates and the uploads of the PublishActions succeed or are rolled back together.
+type Release struct {
+	gorm.Model
+	Name   string
+	Status string `gorm:"default:'draft'"`
+	// ScheduledAt publishes the release by the publisher scheduler.
+	ScheduledAt *time.Time `gorm:"index"`
+	PublishedAt *time.Time
+	Error       string
+}
+
+func (*Release) TableName() string {
+	return "publish_releases"
+}
+
+type ReleaseItem struct {
+	gorm.Model
+	ReleaseID uint `gorm:"index"`
+	// ModelName is the uri name of the model builder.
+	ModelName string
+	// Slug is the primary slug of the record, including the version.
+	Slug  string
+	Label string
+}
+
+func (*ReleaseItem) TableName() string {
+	return "publish_release_items"
+}
+
+func AutoMigrateReleases(db *gorm.DB) error {
+	return db.AutoMigrate(&Release{}, &ReleaseItem{})
+}
+
+// Releases enables the releases, the records of the models could be added to the releases in the listings.
+func (b *Builder) Releases(v bool) (r *Builder) {
+	b.releases = v
+	return b
+}
+
+type ctxKeyRelease struct{}
+
+// releaseTx is the transaction the records of the release are published in, the PublishActions are
+// collected and uploaded once all the records are published.
+type releaseTx struct {
+	tx      *gorm.DB
+	actions []*PublishAction
+}
+
+func (b *Builder) transact(ctx context.Context, f func(tx *gorm.DB) error) error {
+	if rt, ok := ctx.Value(ctxKeyRelease{}).(*releaseTx); ok {
+		return f(rt.tx)
+	}
+	return utils.Transact(b.db, f)
+}
+
+func (b *Builder) uploadOrDelete(ctx context.Context, objs []*PublishAction) error {
+	if rt, ok := ctx.Value(ctxKeyRelease{}).(*releaseTx); ok {
+		rt.actions = append(rt.actions, objs...)
+		return nil
+	}
+	return UploadOrDelete(ctx, objs, b.storage)
+}
+
+// PublishRelease publishes all the items of the release in one transaction, the live objects of the storage are
+// snapshotted before the PublishActions and restored if any of the items fails.
+// The release is marked as failed with the error in that case.
+// Only the draft and failed releases are published, the release is claimed first so that it is published only once.
+func (b *Builder) PublishRelease(ctx context.Context, release *Release) error {
+	_, err := b.publishRelease(ctx, release)
+	return err
+}
+
+// publishedReleaseItem is an item of the release along with the record published for it.
+type publishedReleaseItem struct {
+	item *ReleaseItem
+	obj  any
+}
+
+// claimRelease marks the draft or failed release as publishing, it fails with errReleaseClaimed if another one did.
+func (b *Builder) claimRelease(ctx context.Context, release *Release) error {
+	result := b.db.WithContext(ctx).Model(&Release{}).
+		Where("id = ? AND status IN ?", release.ID, []string{ReleaseStatusDraft, ReleaseStatusFailed}).
+		Update("status", ReleaseStatusPublishing)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errReleaseClaimed
+	}
+	release.Status = ReleaseStatusPublishing
+	return nil
+}
+
+func (b *Builder) publishRelease(ctx context.Context, release *Release) (published []*publishedReleaseItem, err error) {
+	if err = b.claimRelease(ctx, release); err != nil {
+		return nil, err
+	}
+
+	// restores are the actions which put the live objects back, they are run once any action of the release is run
+	var (
+		restores []*PublishAction
+		applied  bool
+	)
+	defer func() {
+		if err == nil {
+			return
+		}
+		published = nil
+		// the ctx may be canceled already
+		ctx := context.WithoutCancel(ctx)
+		if applied {
+			for _, action := range restores {
+				if err2 := UploadOrDelete(ctx, []*PublishAction{action}, b.storage); err2 != nil {
+					log.Printf("release %d: restore %s error: %v\n", release.ID, action.Url, err2)
+				}
+			}
+		}
+		release.Status = ReleaseStatusFailed
+		release.Error = err.Error()
+		if err2 := b.db.WithContext(ctx).Model(release).Updates(map[string]any{
+			"status": release.Status,
+			"error":  release.Error,
+		}).Error; err2 != nil {
+			log.Printf("release %d: update status error: %v\n", release.ID, err2)
+		}
+	}()
+
+	var items []*ReleaseItem
+	if err = b.db.WithContext(ctx).Where("release_id = ?", release.ID).Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("the release has no items")
+	}
+
+	err = utils.Transact(b.db.WithContext(ctx), func(tx *gorm.DB) (err error) {
+		rt := &releaseTx{tx: tx}
+		reqCtx := context.WithValue(b.WithContextValues(ctx), ctxKeyRelease{}, rt)
+		for _, item := range items {
+			obj, err := b.releaseItemObject(tx, item)
+			if err != nil {
+				return err
+			}
+			if !ReviewApproved(obj) {
+				return fmt.Errorf("%s is not approved", item.Label)
+			}
+			if err = b.Publish(reqCtx, obj); err != nil {
+				return fmt.Errorf("publish %s: %w", item.Label, err)
+			}
+			published = append(published, &publishedReleaseItem{item: item, obj: obj})
+		}
+
+		if restores, err = b.liveSnapshots(ctx, rt.actions); err != nil {
+			return err
+		}
+		for _, action := range rt.actions {
+			applied = true
+			if err = UploadOrDelete(ctx, []*PublishAction{action}, b.storage); err != nil {
+				return err
+			}
+		}
+
+		now := tx.NowFunc()
+		release.Status = ReleaseStatusPublished
+		release.PublishedAt = &now
+		release.Error = ""
+		return tx.Model(release).Updates(map[string]any{
+			"status":       release.Status,
+			"published_at": release.PublishedAt,
+			"error":        release.Error,
+		}).Error
+	})
+	return
+}
+
+// liveSnapshots returns the actions restoring the live objects of the urls of the actions,
+// the objects which don't exist are deleted.
+func (b *Builder) liveSnapshots(ctx context.Context, actions []*PublishAction) (r []*PublishAction, err error) {
+	seen := map[string]bool{}
+	for _, action := range actions {
+		if seen[action.Url] {
+			continue
+		}
+		seen[action.Url] = true
+		content, exists, err := b.getLiveContent(ctx, action.Url)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, &PublishAction{Url: action.Url, Content: content, IsDelete: !exists})
+	}
+	return
+}
+
+func (b *Builder) releaseItemObject(db *gorm.DB, item *ReleaseItem) (any, error) {
+	mb, ok := b.releaseModelBuilders[item.ModelName]
+	if !ok {
+		return nil, fmt.Errorf("model %s of the release item is not registered", item.ModelName)
+	}
+	obj := mb.NewModel()
+	if err := utils.PrimarySluggerWhere(db, obj, item.Slug).First(obj).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", item.Label, err)
+	}
+	return obj, nil
+}
+
+// publishScheduledReleases is the job of the publisher scheduler which publishes the releases whose ScheduledAt is passed.
+func (b *Builder) publishScheduledReleases(ctx context.Context) (err error) {
+	var releases []*Release
+	if err = b.db.WithContext(ctx).
+		Where("status = ? AND scheduled_at <= ?", ReleaseStatusDraft, b.db.NowFunc()).
+		Order("scheduled_at").Find(&releases).Error; err != nil {
+		return
+	}
+	for _, release := range releases {
+		published, err2 := b.publishRelease(ctx, release)
+		if errors.Is(err2, errReleaseClaimed) {
+			continue
+		}
+		if err2 != nil {
+			err = multierror.Append(err, fmt.Errorf("release %d: %w", release.ID, err2)).ErrorOrNil()
+			continue
+		}
+		for _, p := range published {
+			if _, err2 = b.republishDependents(ctx, nil, p.obj); err2 != nil {
+				err = multierror.Append(err, err2).ErrorOrNil()
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,452 @@
+package publish
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/i18n"
+	"github.com/qor5/x/v3/perm"
+	v "github.com/qor5/x/v3/ui/vuetify"
+	vx "github.com/qor5/x/v3/ui/vuetifyx"
+	h "github.com/theplant/htmlgo"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+const (
+	eventAddToReleaseDialog = "publish_eventAddToReleaseDialog"
+	eventAddToRelease       = "publish_eventAddToRelease"
+	eventRemoveReleaseItem  = "publish_eventRemoveReleaseItem"
+	eventPublishRelease     = "publish_eventPublishRelease"
+
+	ActivityPublishRelease = "PublishRelease"
+
+	paramReleaseModel  = "publish_param_release_model"
+	paramReleaseID     = "publish_param_release_id"
+	paramReleaseItemID = "publish_param_release_item_id"
+
+	releaseFieldItems = "Items"
+
+	portalReleaseItems = "publish_portalReleaseItems"
+)
+
+// ReleasePublishLogDetail is the detail of the activity log of the published release.
+type ReleasePublishLogDetail struct {
+	Items []string `json:"items"`
+}
+
+func (b *Builder) installReleases(pb *presets.Builder) {
+	mb := pb.Model(&Release{}).URIName("publish-releases").MenuIcon("mdi-package-variant-closed")
+	b.releaseModelBuilder = mb
+	mb.LabelName(func(evCtx *web.EventContext, singular bool) string {
+		msgr := i18n.MustGetModuleMessages(evCtx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		if singular {
+			return msgr.Release
+		}
+		return msgr.Releases
+	})
+
+	mb.RegisterEventFunc(eventAddToReleaseDialog, b.addToReleaseDialog)
+	mb.RegisterEventFunc(eventAddToRelease, b.addToRelease)
+	mb.RegisterEventFunc(eventRemoveReleaseItem, b.removeReleaseItem)
+	mb.RegisterEventFunc(eventPublishRelease, b.publishReleaseAction)
+
+	lb := mb.Listing("ID", "Name", "Status", "ScheduledAt", "PublishedAt")
+	lb.Field("Status").ComponentFunc(func(obj interface{}, _ *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
+		return h.Td(releaseStatusChip(ctx, obj.(*Release)))
+	})
+	lb.Field("ScheduledAt").ComponentFunc(func(obj interface{}, _ *presets.FieldContext, _ *web.EventContext) h.HTMLComponent {
+		return h.Td(h.Text(ScheduleTimeString(obj.(*Release).ScheduledAt)))
+	})
+	lb.Field("PublishedAt").ComponentFunc(func(obj interface{}, _ *presets.FieldContext, _ *web.EventContext) h.HTMLComponent {
+		return h.Td(h.Text(ScheduleTimeString(obj.(*Release).PublishedAt)))
+	})
+
+	eb := mb.Editing("Name", "ScheduledAt")
+	eb.ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		release := obj.(*Release)
+		if release.Name == "" {
+			err.FieldError("Name", msgr.ReleaseNameRequired)
+		}
+		if release.Status == ReleaseStatusPublished {
+			err.GlobalError(msgr.ReleaseAlreadyPublished)
+		}
+		return
+	})
+	eb.Field("ScheduledAt").ComponentFunc(func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
+		return vx.VXDatepicker().Type("datetimepicker").
+			Format("YYYY-MM-DD HH:mm").
+			Clearable(true).
+			Attr(web.VField(field.FormKey, ScheduleTimeString(obj.(*Release).ScheduledAt))...).
+			Label(field.Label)
+	}).SetterFunc(func(obj interface{}, field *presets.FieldContext, ctx *web.EventContext) (err error) {
+		obj.(*Release).ScheduledAt, err = parseScheduleTimeValue(ctx.R.FormValue(field.FormKey))
+		return
+	})
+	eb.WrapSaveFunc(func(in presets.SaveFunc) presets.SaveFunc {
+		return func(obj interface{}, id string, ctx *web.EventContext) (err error) {
+			release := obj.(*Release)
+			// a failed release is retried once it is saved again
+			if release.Status == "" || release.Status == ReleaseStatusFailed {
+				release.Status = ReleaseStatusDraft
+				release.Error = ""
+			}
+			return in(obj, id, ctx)
+		}
+	})
+	eb.WrapDeleteFunc(func(in presets.DeleteFunc) presets.DeleteFunc {
+		return func(obj interface{}, id string, ctx *web.EventContext) (err error) {
+			if err = in(obj, id, ctx); err != nil {
+				return
+			}
+			return b.db.Where("release_id = ?", obj.(*Release).ID).Delete(&ReleaseItem{}).Error
+		}
+	})
+
+	dp := mb.Detailing(releaseFieldItems).Drawer(true)
+	dp.Field(releaseFieldItems).ComponentFunc(func(obj interface{}, _ *presets.FieldContext, ctx *web.EventContext) h.HTMLComponent {
+		return web.Portal(b.releaseItemsComponent(ctx, obj.(*Release))).Name(portalReleaseItems)
+	})
+
+	if b.ab != nil {
+		b.ab.RegisterModel(mb)
+	}
+}
+
+func releaseStatusChip(ctx *web.EventContext, release *Release) h.HTMLComponent {
+	msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+	label, color := msgr.StatusDraft, v.ColorWarning
+	switch {
+	case release.Status == ReleaseStatusPublished:
+		label, color = msgr.ReleaseStatusPublished, v.ColorSuccess
+	case release.Status == ReleaseStatusPublishing:
+		label, color = msgr.ReleaseStatusPublishing, v.ColorInfo
+	case release.Status == ReleaseStatusFailed:
+		label, color = msgr.ReleaseStatusFailed, v.ColorError
+	case release.ScheduledAt != nil:
+		label, color = msgr.ReleaseStatusScheduled, v.ColorPrimary
+	}
+	return v.VChip(h.Text(label)).Color(color).Size(v.SizeSmall).Variant(v.VariantTonal)
+}
+
+func (b *Builder) releaseItemsComponent(ctx *web.EventContext, release *Release) h.HTMLComponent {
+	msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+	cmsgr := i18n.MustGetModuleMessages(ctx.R, presets.CoreI18nModuleKey, Messages_en_US).(*presets.Messages)
+
+	var items []*ReleaseItem
+	if err := b.db.Where("release_id = ?", release.ID).Order("id").Find(&items).Error; err != nil {
+		return h.Text(err.Error())
+	}
+	editable := release.Status != ReleaseStatusPublished
+	href := b.releaseModelBuilder.Info().ListingHref()
+
+	var rows []h.HTMLComponent
+	for _, item := range items {
+		rows = append(rows, v.VListItem(
+			v.VListItemTitle(h.Text(item.Label)),
+			v.VListItemSubtitle(h.Text(item.Slug)),
+			web.Slot(
+				h.If(editable, v.VBtn("").Icon("mdi-close").Variant(v.VariantText).Size(v.SizeSmall).
+					Attr("title", msgr.RemoveFromRelease).
+					Attr("@click", web.Plaid().EventFunc(eventRemoveReleaseItem).
+						Query(paramReleaseItemID, item.ID).
+						URL(href).Go())),
+			).Name("append"),
+		))
+	}
+
+	return h.Div(
+		h.Div(
+			h.Div(releaseStatusChip(ctx, release)),
+			v.VSpacer(),
+			h.If(editable && len(items) > 0, web.Scope(
+				v.VBtn(msgr.PublishRelease).Color(v.ColorPrimary).Variant(v.VariantElevated).
+					Attr("@click", "locals.releaseDialog = true"),
+				vx.VXDialog(h.Span(msgr.ConfirmPublishRelease)).
+					Attr("v-model", "locals.releaseDialog").
+					Title(msgr.PublishRelease).
+					CancelText(cmsgr.Cancel).
+					OkText(cmsgr.OK).
+					Attr(":disable-ok", "isFetching").
+					Attr("@click:ok", fmt.Sprintf(`({isLoading}) => {
+						isLoading.value = isFetching;
+						%s
+					}`, web.Plaid().EventFunc(eventPublishRelease).
+						Query(presets.ParamID, release.ID).
+						URL(href).Go())).
+					MaxWidth(480),
+			).VSlot("{locals}").Init("{releaseDialog: false}")),
+		).Class("d-flex align-center mb-4"),
+		h.If(release.Error != "", v.VAlert(h.Text(release.Error)).Type(v.ColorError).Density(v.DensityCompact).Class("mb-4")),
+		h.Div(h.Text(msgr.ReleaseItems)).Class("text-subtitle-1 mb-2"),
+		h.If(len(items) == 0, h.Div(h.Text(msgr.ReleaseEmpty)).Class("text-medium-emphasis")),
+		h.If(len(items) > 0, v.VList(rows...).Density(v.DensityCompact).Class("border rounded")),
+	)
+}
+
+// addToReleaseRowMenuItem adds the version of the record in the row to one of the draft releases.
+func (b *Builder) addToReleaseRowMenuItem(mb *presets.ModelBuilder) func(obj interface{}, id string, ctx *web.EventContext) h.HTMLComponent {
+	return func(obj interface{}, id string, ctx *web.EventContext) h.HTMLComponent {
+		if b.releaseModelBuilder == nil || DeniedDo(mb.Info().Verifier(), obj, ctx.R, PermPublish) {
+			return nil
+		}
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		return v.VListItem(
+			web.Slot(v.VIcon("mdi-package-variant-plus")).Name("prepend"),
+			v.VListItemTitle(h.Text(msgr.AddToRelease)),
+		).Attr("@click", web.Plaid().EventFunc(eventAddToReleaseDialog).
+			Query(paramReleaseModel, mb.Info().URIName()).
+			Query(presets.ParamID, id).
+			URL(b.releaseModelBuilder.Info().ListingHref()).Go())
+	}
+}
+
+func (b *Builder) fetchReleaseItemObject(ctx *web.EventContext) (mb *presets.ModelBuilder, obj any, err error) {
+	mb, ok := b.releaseModelBuilders[ctx.R.FormValue(paramReleaseModel)]
+	if !ok {
+		return nil, nil, errInvalidObject
+	}
+	obj, err = mb.Editing().Fetcher(mb.NewModel(), ctx.R.FormValue(presets.ParamID), ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if DeniedDo(mb.Info().Verifier(), obj, ctx.R, PermPublish) {
+		return nil, nil, perm.PermissionDenied
+	}
+	return
+}
+
+func (b *Builder) addToReleaseDialog(ctx *web.EventContext) (r web.EventResponse, err error) {
+	if _, _, err = b.fetchReleaseItemObject(ctx); err != nil {
+		return
+	}
+	msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+	cmsgr := i18n.MustGetModuleMessages(ctx.R, presets.CoreI18nModuleKey, Messages_en_US).(*presets.Messages)
+
+	var releases []*Release
+	if err = b.db.Where("status = ?", ReleaseStatusDraft).Order("id DESC").Find(&releases).Error; err != nil {
+		return
+	}
+	if len(releases) == 0 {
+		presets.ShowMessage(&r, msgr.NoDraftRelease, "warning")
+		return
+	}
+
+	r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+		Name: presets.DialogPortalName,
+		Body: web.Scope().VSlot("{locals}").Init("{releaseDialog:true}").Children(
+			vx.VXDialog(
+				v.VSelect().
+					Items(releases).ItemTitle("Name").ItemValue("ID").
+					Label(msgr.Release).
+					Variant(v.FieldVariantOutlined).Density(v.DensityCompact).
+					Attr(web.VField(paramReleaseID, releases[0].ID)...),
+			).Attr("v-model", "locals.releaseDialog").
+				Title(msgr.AddToRelease).
+				CancelText(cmsgr.Cancel).
+				OkText(msgr.AddToRelease).
+				Attr(":disable-ok", "isFetching").
+				Attr("@click:ok", fmt.Sprintf(`({isLoading}) => {
+					isLoading.value = isFetching;
+					%s
+				}`, web.Plaid().EventFunc(eventAddToRelease).
+					Query(paramReleaseModel, ctx.R.FormValue(paramReleaseModel)).
+					Query(presets.ParamID, ctx.R.FormValue(presets.ParamID)).
+					URL(b.releaseModelBuilder.Info().ListingHref()).Go())).
+				MaxWidth(480),
+		),
+	})
+	return
+}
+
+func (b *Builder) addToRelease(ctx *web.EventContext) (r web.EventResponse, err error) {
+	defer func() {
+		if err != nil {
+			presets.ShowMessage(&r, err.Error(), "error")
+			err = nil
+		}
+	}()
+
+	mb, obj, err := b.fetchReleaseItemObject(ctx)
+	if err != nil {
+		return
+	}
+	msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+
+	release := &Release{}
+	if err = b.db.Where("id = ? AND status = ?", ctx.R.FormValue(paramReleaseID), ReleaseStatusDraft).First(release).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New(msgr.NoDraftRelease)
+		}
+		return
+	}
+
+	slug := ctx.R.FormValue(presets.ParamID)
+	item := &ReleaseItem{
+		ReleaseID: release.ID,
+		ModelName: mb.Info().URIName(),
+		Slug:      slug,
+		Label:     releaseItemLabel(ctx, mb, obj, slug),
+	}
+	err = b.db.Transaction(func(tx *gorm.DB) error {
+		var existing []*ReleaseItem
+		if err := tx.Where("release_id = ? AND model_name = ?", release.ID, item.ModelName).Find(&existing).Error; err != nil {
+			return err
+		}
+		for _, e := range existing {
+			// another version of the same record is replaced
+			if !sameRecord(obj, e.Slug, slug) {
+				continue
+			}
+			if err := tx.Delete(e).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(item).Error
+	})
+	if err != nil {
+		return
+	}
+
+	web.AppendRunScripts(&r, "locals.releaseDialog = false")
+	presets.ShowMessage(&r, msgr.SuccessfullyAddToRelease, v.ColorSuccess)
+	return
+}
+
+// sameRecord reports whether the slugs are of the same record, the versions are ignored.
+func sameRecord(obj any, slug1, slug2 string) bool {
+	decoder := obj.(presets.SlugDecoder)
+	values1, values2 := decoder.PrimaryColumnValuesBySlug(slug1), decoder.PrimaryColumnValuesBySlug(slug2)
+	delete(values1, SlugVersion)
+	delete(values2, SlugVersion)
+	return maps.Equal(values1, values2)
+}
+
+func releaseItemLabel(ctx *web.EventContext, mb *presets.ModelBuilder, obj any, slug string) string {
+	title := slug
+	if pt, ok := obj.(interface{ PageTitle() string }); ok {
+		title = pt.PageTitle()
+	}
+	return fmt.Sprintf("%s %s", mb.Info().LabelName(ctx, true), title)
+}
+
+func (b *Builder) removeReleaseItem(ctx *web.EventContext) (r web.EventResponse, err error) {
+	defer func() {
+		if err != nil {
+			presets.ShowMessage(&r, err.Error(), "error")
+			err = nil
+		}
+	}()
+
+	item := &ReleaseItem{}
+	if err = b.db.Where("id = ?", ctx.R.FormValue(paramReleaseItemID)).First(item).Error; err != nil {
+		return
+	}
+	release, err := b.fetchRelease(ctx, fmt.Sprint(item.ReleaseID), presets.PermUpdate)
+	if err != nil {
+		return
+	}
+	if release.Status == ReleaseStatusPublished || release.Status == ReleaseStatusPublishing {
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		return r, errors.New(msgr.ReleaseBeingPublished)
+	}
+	if err = b.db.Delete(item).Error; err != nil {
+		return
+	}
+	r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+		Name: portalReleaseItems,
+		Body: b.releaseItemsComponent(ctx, release),
+	})
+	return
+}
+
+func (b *Builder) fetchRelease(ctx *web.EventContext, id string, action string) (*Release, error) {
+	mb := b.releaseModelBuilder
+	obj, err := mb.Editing().Fetcher(mb.NewModel(), id, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if mb.Info().Verifier().Do(action).ObjectOn(obj).WithReq(ctx.R).IsAllowed() != nil {
+		return nil, perm.PermissionDenied
+	}
+	return obj.(*Release), nil
+}
+
+func (b *Builder) publishReleaseAction(ctx *web.EventContext) (r web.EventResponse, err error) {
+	defer func() {
+		if err != nil {
+			presets.ShowMessage(&r, err.Error(), "error")
+			err = nil
+		}
+	}()
+
+	release, err := b.fetchRelease(ctx, ctx.R.FormValue(presets.ParamID), presets.PermUpdate)
+	if err != nil {
+		return
+	}
+	msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+	if release.Status == ReleaseStatusPublished || release.Status == ReleaseStatusPublishing {
+		return r, errors.New(msgr.ReleaseBeingPublished)
+	}
+
+	var items []*ReleaseItem
+	if err = b.db.Where("release_id = ?", release.ID).Order("id").Find(&items).Error; err != nil {
+		return
+	}
+	for _, item := range items {
+		obj, err := b.releaseItemObject(b.db, item)
+		if err != nil {
+			return r, err
+		}
+		if DeniedDo(b.releaseModelBuilders[item.ModelName].Info().Verifier(), obj, ctx.R, PermPublish) {
+			return r, perm.PermissionDenied
+		}
+	}
+
+	published, err := b.publishRelease(ctx.R.Context(), release)
+	if errors.Is(err, errReleaseClaimed) {
+		return r, errors.New(msgr.ReleaseBeingPublished)
+	}
+	if err != nil {
+		return
+	}
+
+	if b.ab != nil {
+		if amb, exist := b.ab.GetModelBuilder(b.releaseModelBuilder); exist {
+			detail := ReleasePublishLogDetail{}
+			for _, p := range published {
+				detail.Items = append(detail.Items, p.item.Label)
+			}
+			amb.Log(ctx.R.Context(), ActivityPublishRelease, release, detail)
+		}
+		for _, p := range published {
+			if amb, exist := b.ab.GetModelBuilder(b.releaseModelBuilders[p.item.ModelName]); exist {
+				amb.Log(ctx.R.Context(), ActivityPublish, p.obj, nil)
+			}
+		}
+	}
+	var warning string
+	for _, p := range published {
+		warning = cmp.Or(b.republishDependentsAfterAction(ctx, p.obj), warning)
+	}
+
+	r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+		Name: portalReleaseItems,
+		Body: b.releaseItemsComponent(ctx, release),
+	})
+	r.Emit(b.releaseModelBuilder.NotifModelsUpdated(), presets.PayloadModelsUpdated{
+		Ids:    []string{fmt.Sprint(release.ID)},
+		Models: map[string]any{fmt.Sprint(release.ID): release},
+	})
+	if warning != "" {
+		presets.ShowMessage(&r, warning, v.ColorWarning)
+		return
+	}
+	presets.ShowMessage(&r, msgr.SuccessfullyPublish, v.ColorSuccess)
+	return
+}
@@ -0,0 +1,137 @@
+package publish_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/qor5/x/v3/oss"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/admin/v3/presets/gorm2op"
+	"github.com/qor5/admin/v3/publish"
+)
+
+type ReleaseProduct struct {
+	ID      uint
+	Code    string
+	Content string
+	publish.Status
+}
+
+func (p *ReleaseProduct) PrimarySlug() string { return fmt.Sprint(p.ID) }
+
+func (*ReleaseProduct) PrimaryColumnValuesBySlug(slug string) map[string]string {
+	return map[string]string{"id": slug}
+}
+
+func (p *ReleaseProduct) url() string {
+	return fmt.Sprintf("test/release_product/%s/index.html", p.Code)
+}
+
+func (p *ReleaseProduct) GetPublishActions(ctx context.Context, db *gorm.DB, storage oss.StorageInterface) (actions []*publish.PublishAction, err error) {
+	actions = append(actions, &publish.PublishAction{Url: p.url(), Content: p.Content})
+	if p.Status.Status == publish.StatusOnline && p.OnlineUrl != p.url() {
+		actions = append(actions, &publish.PublishAction{Url: p.OnlineUrl, IsDelete: true})
+	}
+	p.OnlineUrl = p.url()
+	return
+}
+
+func (p *ReleaseProduct) GetUnPublishActions(ctx context.Context, db *gorm.DB, storage oss.StorageInterface) (actions []*publish.PublishAction, err error) {
+	return []*publish.PublishAction{{Url: p.OnlineUrl, IsDelete: true}}, nil
+}
+
+// failingStorage fails to put the object of failPath.
+type failingStorage struct {
+	*MockStorage
+	failPath string
+}
+
+func (s *failingStorage) Put(ctx context.Context, path string, r io.Reader) (*oss.Object, error) {
+	if path == s.failPath {
+		return nil, errors.New("put failed")
+	}
+	return s.MockStorage.Put(ctx, path, r)
+}
+
+func TestPublishReleaseFailure(t *testing.T) {
+	require.NoError(t, publish.AutoMigrateReleases(TestDB))
+	storage := &MockStorage{}
+	pb := publish.New(TestDB, storage).Releases(true)
+
+	empty := &publish.Release{Name: "empty"}
+	require.NoError(t, TestDB.Create(empty).Error)
+	require.Error(t, pb.PublishRelease(context.Background(), empty))
+
+	release := &publish.Release{Name: "unknown model"}
+	require.NoError(t, TestDB.Create(release).Error)
+	require.NoError(t, TestDB.Create(&publish.ReleaseItem{ReleaseID: release.ID, ModelName: "unknown", Slug: "1", Label: "Unknown 1"}).Error)
+	require.Error(t, pb.PublishRelease(context.Background(), release))
+
+	for _, r := range []*publish.Release{empty, release} {
+		var saved publish.Release
+		require.NoError(t, TestDB.First(&saved, r.ID).Error)
+		require.Equal(t, publish.ReleaseStatusFailed, saved.Status)
+		require.NotEmpty(t, saved.Error)
+		require.Nil(t, saved.PublishedAt)
+	}
+	require.Empty(t, storage.Objects)
+
+	// the releases published or being published by another replica are not claimed
+	for _, status := range []string{publish.ReleaseStatusPublishing, publish.ReleaseStatusPublished} {
+		claimed := &publish.Release{Name: status, Status: status}
+		require.NoError(t, TestDB.Create(claimed).Error)
+		require.Error(t, pb.PublishRelease(context.Background(), claimed))
+		var saved publish.Release
+		require.NoError(t, TestDB.First(&saved, claimed.ID).Error)
+		require.Equal(t, status, saved.Status)
+		require.Empty(t, saved.Error)
+	}
+}
+
+func TestPublishReleaseRestoresLiveObjects(t *testing.T) {
+	require.NoError(t, publish.AutoMigrateReleases(TestDB))
+	require.NoError(t, TestDB.AutoMigrate(&ReleaseProduct{}))
+
+	// moved is moved to another url, republished is published to its live url again, failed fails to upload
+	moved := &ReleaseProduct{Code: "moved", Content: "moved new", Status: publish.Status{Status: publish.StatusOnline, OnlineUrl: "test/release_product/old/index.html"}}
+	republished := &ReleaseProduct{Code: "republished", Content: "republished new", Status: publish.Status{Status: publish.StatusOnline, OnlineUrl: "test/release_product/republished/index.html"}}
+	failed := &ReleaseProduct{Code: "failed", Content: "failed new", Status: publish.Status{Status: publish.StatusDraft}}
+	require.NoError(t, TestDB.Create([]*ReleaseProduct{moved, republished, failed}).Error)
+
+	live := map[string]string{
+		"test/release_product/old/index.html":         "moved live",
+		"test/release_product/republished/index.html": "republished live",
+	}
+	storage := &failingStorage{MockStorage: &MockStorage{Objects: map[string]string{}}, failPath: failed.url()}
+	for k, v := range live {
+		storage.Objects[k] = v
+	}
+
+	pb := publish.New(TestDB, storage).Releases(true)
+	ppb := presets.New().DataOperator(gorm2op.DataOperator(TestDB))
+	mb := ppb.Model(&ReleaseProduct{})
+	require.NoError(t, pb.ModelInstall(ppb, mb))
+
+	release := &publish.Release{Name: "partial"}
+	require.NoError(t, TestDB.Create(release).Error)
+	for _, p := range []*ReleaseProduct{moved, republished, failed} {
+		require.NoError(t, TestDB.Create(&publish.ReleaseItem{ReleaseID: release.ID, ModelName: mb.Info().URIName(), Slug: p.PrimarySlug(), Label: p.Code}).Error)
+	}
+	require.Error(t, pb.PublishRelease(context.Background(), release))
+
+	// the objects uploaded and deleted by the items before the failed one are restored
+	require.Equal(t, live, storage.Objects)
+
+	var saved []*ReleaseProduct
+	require.NoError(t, TestDB.Order("id").Find(&saved).Error)
+	require.Len(t, saved, 3)
+	require.Equal(t, "test/release_product/old/index.html", saved[0].OnlineUrl)
+	require.Equal(t, publish.StatusOnline, saved[1].Status.Status)
+	require.Equal(t, publish.StatusDraft, saved[2].Status.Status)
+}
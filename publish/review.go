@@ -0,0 +1,144 @@
+package publish
+
+import (
+	"context"
+
+	"github.com/qor5/web/v3"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+type Reviewer struct {
+	ID   string
+	Name string
+}
+
+type (
+	ReviewersFunc       func(ctx context.Context, obj any) ([]*Reviewer, error)
+	CurrentReviewerFunc func(ctx context.Context) (*Reviewer, error)
+)
+
+// ReviewLogDetail is the detail of the activity logs of the review workflow.
+type ReviewLogDetail struct {
+	Reviewer string `json:"reviewer,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// ReviewersFunc returns the reviewers who could be assigned when the record is submitted for review,
+// if it is not set, no reviewer is assigned and anyone with PermReview could review the record.
+func (b *Builder) ReviewersFunc(v ReviewersFunc) (r *Builder) {
+	b.reviewersFunc = v
+	return b
+}
+
+// CurrentReviewerFunc returns the current user, so that only the assigned reviewer could review the record,
+// and the submitter of the review could not approve it.
+func (b *Builder) CurrentReviewerFunc(v CurrentReviewerFunc) (r *Builder) {
+	b.currentReviewerFunc = v
+	return b
+}
+
+// ReviewApproved reports whether the record could be published, records without Review are always approved.
+func ReviewApproved(obj any) bool {
+	review := EmbedReview(obj)
+	return review == nil || review.ReviewStatus == ReviewStatusApproved
+}
+
+// ResetReview withdraws the pending or approved review once the record is changed, the rejected one is kept
+// so that the comment is still shown until the record is submitted again. It reports whether the review is reset.
+// The records are reset on every save through the editing Saver, except the saves of the review workflow itself.
+func ResetReview(obj any) bool {
+	review := EmbedReview(obj)
+	if review == nil || review.ReviewStatus == "" || review.ReviewStatus == ReviewStatusRejected {
+		return false
+	}
+	*review = Review{}
+	return true
+}
+
+type ctxKeyReviewKept struct{}
+
+// withReviewKept marks the saves which don't change the reviewed content, like the review itself or the schedule,
+// so that the Saver doesn't reset the review.
+func withReviewKept(ctx *web.EventContext) *web.EventContext {
+	cp := *ctx
+	cp.R = ctx.R.WithContext(context.WithValue(ctx.R.Context(), ctxKeyReviewKept{}, true))
+	return &cp
+}
+
+func reviewKept(ctx *web.EventContext) bool {
+	if ctx == nil || ctx.R == nil {
+		return false
+	}
+	kept, _ := ctx.R.Context().Value(ctxKeyReviewKept{}).(bool)
+	return kept
+}
+
+// canSubmitReview reports whether the record could be submitted, the online records too, as the edits of them
+// withdraw the approval and have to be reviewed again before they are republished.
+func canSubmitReview(mb *presets.ModelBuilder, obj any, ctx *web.EventContext) bool {
+	review := EmbedReview(obj)
+	if review == nil {
+		return false
+	}
+	if review.ReviewStatus != "" && review.ReviewStatus != ReviewStatusRejected {
+		return false
+	}
+	return !DeniedDo(mb.Info().Verifier(), obj, ctx.R, presets.PermUpdate, PermSubmitReview)
+}
+
+func canReview(mb *presets.ModelBuilder, obj any, ctx *web.EventContext) bool {
+	review := EmbedReview(obj)
+	if review == nil || review.ReviewStatus != ReviewStatusPending {
+		return false
+	}
+	return !DeniedDo(mb.Info().Verifier(), obj, ctx.R, PermReview)
+}
+
+func (b *Builder) isAssignedReviewer(ctx context.Context, review *Review) (bool, error) {
+	if review.ReviewerID == "" || b.currentReviewerFunc == nil {
+		return true, nil
+	}
+	current, err := b.currentReviewerFunc(ctx)
+	if err != nil {
+		return false, err
+	}
+	return current != nil && current.ID == review.ReviewerID, nil
+}
+
+// currentReviewerID returns the id of the current user, empty if CurrentReviewerFunc is not set.
+func (b *Builder) currentReviewerID(ctx context.Context) (string, error) {
+	if b.currentReviewerFunc == nil {
+		return "", nil
+	}
+	current, err := b.currentReviewerFunc(ctx)
+	if err != nil || current == nil {
+		return "", err
+	}
+	return current.ID, nil
+}
+
+// isSubmitter reports whether the current user submitted the review, who could not review it.
+func (b *Builder) isSubmitter(ctx context.Context, review *Review) (bool, error) {
+	if review.SubmitterID == "" {
+		return false, nil
+	}
+	id, err := b.currentReviewerID(ctx)
+	if err != nil {
+		return false, err
+	}
+	return id == review.SubmitterID, nil
+}
+
+func (b *Builder) findReviewer(ctx context.Context, obj any, id string) (*Reviewer, error) {
+	reviewers, err := b.reviewersFunc(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, reviewer := range reviewers {
+		if reviewer.ID == id {
+			return reviewer, nil
+		}
+	}
+	return nil, nil
+}
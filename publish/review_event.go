@@ -0,0 +1,268 @@
+package publish
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/i18n"
+	"github.com/qor5/x/v3/perm"
+	v "github.com/qor5/x/v3/ui/vuetify"
+	vx "github.com/qor5/x/v3/ui/vuetifyx"
+	h "github.com/theplant/htmlgo"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+const (
+	PortalReviewDialog = "publish_PortalReviewDialog"
+
+	paramReviewerID     = "publish_param_reviewer_id"
+	paramReviewComment  = "publish_param_review_comment"
+	paramReviewDecision = "publish_param_review_decision"
+)
+
+func submitReviewDialog(mb *presets.ModelBuilder, publisher *Builder) web.EventFunc {
+	return func(ctx *web.EventContext) (r web.EventResponse, err error) {
+		slug := ctx.Param(presets.ParamID)
+		obj := mb.NewModel()
+		obj, err = mb.Editing().Fetcher(obj, slug, ctx)
+		if err != nil {
+			return r, err
+		}
+		if !canSubmitReview(mb, obj, ctx) {
+			return r, perm.PermissionDenied
+		}
+
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		cmsgr := i18n.MustGetModuleMessages(ctx.R, presets.CoreI18nModuleKey, Messages_en_US).(*presets.Messages)
+
+		var reviewerSelect h.HTMLComponent
+		if publisher.reviewersFunc != nil {
+			reviewers, err := publisher.reviewersFunc(ctx.R.Context(), obj)
+			if err != nil {
+				return r, err
+			}
+			reviewerSelect = v.VSelect().
+				Items(reviewers).ItemTitle("Name").ItemValue("ID").
+				Label(msgr.Reviewer).
+				Variant(v.FieldVariantOutlined).Density(v.DensityCompact).
+				Attr(web.VField(paramReviewerID, EmbedReview(obj).ReviewerID)...)
+		}
+
+		r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+			Name: PortalReviewDialog,
+			Body: web.Scope().VSlot("{locals}").Init("{reviewDialog:true}").Children(
+				vx.VXDialog(
+					reviewerSelect,
+					v.VTextarea().Label(msgr.ReviewComment).Rows(3).
+						Variant(v.FieldVariantOutlined).Density(v.DensityCompact).HideDetails(true).
+						Attr(web.VField(paramReviewComment, "")...),
+				).Attr("v-model", "locals.reviewDialog").
+					Title(msgr.SubmitForReview).
+					CancelText(cmsgr.Cancel).
+					OkText(msgr.SubmitForReview).
+					Attr(":disable-ok", "isFetching").
+					Attr("@click:ok", fmt.Sprintf(`({isLoading}) => {
+						isLoading.value = isFetching;
+						%s
+					}`, web.Plaid().EventFunc(eventSubmitReview).Query(presets.ParamID, slug).URL(mb.Info().ListingHref()).Go())).
+					MaxWidth(480),
+			),
+		})
+		return
+	}
+}
+
+func submitReview(mb *presets.ModelBuilder, publisher *Builder) web.EventFunc {
+	return func(ctx *web.EventContext) (r web.EventResponse, err error) {
+		defer func() {
+			if err != nil {
+				presets.ShowMessage(&r, err.Error(), "error")
+				err = nil
+			}
+		}()
+
+		slug := ctx.Param(presets.ParamID)
+		obj := mb.NewModel()
+		obj, err = mb.Editing().Fetcher(obj, slug, ctx)
+		if err != nil {
+			return r, err
+		}
+		if !canSubmitReview(mb, obj, ctx) {
+			return r, perm.PermissionDenied
+		}
+
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		submitterID, err := publisher.currentReviewerID(ctx.R.Context())
+		if err != nil {
+			return r, err
+		}
+		review := EmbedReview(obj)
+		*review = Review{
+			ReviewStatus:  ReviewStatusPending,
+			SubmitterID:   submitterID,
+			ReviewComment: ctx.R.FormValue(paramReviewComment),
+		}
+		if publisher.reviewersFunc != nil {
+			reviewer, err := publisher.findReviewer(ctx.R.Context(), obj, ctx.R.FormValue(paramReviewerID))
+			if err != nil {
+				return r, err
+			}
+			if reviewer == nil {
+				return r, errors.New(msgr.ReviewerRequired)
+			}
+			review.ReviewerID = reviewer.ID
+			review.ReviewerName = reviewer.Name
+		}
+
+		if err = mb.Editing().Saver(obj, slug, withReviewKept(ctx)); err != nil {
+			return r, err
+		}
+		publisher.logReview(ctx, mb, ActivitySubmitReview, obj)
+
+		web.AppendRunScripts(&r, "locals.reviewDialog = false")
+		r.Emit(mb.NotifModelsUpdated(), presets.PayloadModelsUpdated{
+			Ids:    []string{slug},
+			Models: map[string]any{slug: obj},
+		})
+		web.AppendRunScripts(&r, web.Plaid().MergeQuery(true).
+			ThenScript(presets.ShowSnackbarScript(msgr.SuccessfullySubmitForReview, v.ColorSuccess)).
+			Go(),
+		)
+		return r, nil
+	}
+}
+
+func reviewDialog(mb *presets.ModelBuilder, publisher *Builder) web.EventFunc {
+	return func(ctx *web.EventContext) (r web.EventResponse, err error) {
+		slug := ctx.Param(presets.ParamID)
+		obj := mb.NewModel()
+		obj, err = mb.Editing().Fetcher(obj, slug, ctx)
+		if err != nil {
+			return r, err
+		}
+		if !canReview(mb, obj, ctx) {
+			return r, perm.PermissionDenied
+		}
+
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		cmsgr := i18n.MustGetModuleMessages(ctx.R, presets.CoreI18nModuleKey, Messages_en_US).(*presets.Messages)
+
+		review := EmbedReview(obj)
+		r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+			Name: PortalReviewDialog,
+			Body: web.Scope().VSlot("{locals}").Init("{reviewDialog:true}").Children(
+				vx.VXDialog(
+					h.If(review.ReviewComment != "",
+						h.Div(h.Text(review.ReviewComment)).Class("text-body-2 text-medium-emphasis mb-4").Style("white-space: pre-wrap;"),
+					),
+					v.VRadioGroup(
+						v.VRadio().Label(msgr.Approve).Value(ReviewStatusApproved),
+						v.VRadio().Label(msgr.Reject).Value(ReviewStatusRejected),
+					).Inline(true).HideDetails(true).
+						Attr(web.VField(paramReviewDecision, ReviewStatusApproved)...),
+					v.VTextarea().Label(msgr.ReviewComment).Rows(3).
+						Variant(v.FieldVariantOutlined).Density(v.DensityCompact).HideDetails(true).
+						Attr(web.VField(paramReviewComment, "")...),
+				).Attr("v-model", "locals.reviewDialog").
+					Title(msgr.Review).
+					CancelText(cmsgr.Cancel).
+					OkText(cmsgr.OK).
+					Attr(":disable-ok", "isFetching").
+					Attr("@click:ok", fmt.Sprintf(`({isLoading}) => {
+						isLoading.value = isFetching;
+						%s
+					}`, web.Plaid().EventFunc(eventReview).Query(presets.ParamID, slug).URL(mb.Info().ListingHref()).Go())).
+					MaxWidth(480),
+			),
+		})
+		return
+	}
+}
+
+func reviewAction(db *gorm.DB, mb *presets.ModelBuilder, publisher *Builder) web.EventFunc {
+	return func(ctx *web.EventContext) (r web.EventResponse, err error) {
+		defer func() {
+			if err != nil {
+				presets.ShowMessage(&r, err.Error(), "error")
+				err = nil
+			}
+		}()
+
+		slug := ctx.Param(presets.ParamID)
+		obj := mb.NewModel()
+		obj, err = mb.Editing().Fetcher(obj, slug, ctx)
+		if err != nil {
+			return r, err
+		}
+		if !canReview(mb, obj, ctx) {
+			return r, perm.PermissionDenied
+		}
+
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		review := EmbedReview(obj)
+		assigned, err := publisher.isAssignedReviewer(ctx.R.Context(), review)
+		if err != nil {
+			return r, err
+		}
+		if !assigned {
+			return r, errors.New(msgr.NotAssignedReviewer)
+		}
+		submitter, err := publisher.isSubmitter(ctx.R.Context(), review)
+		if err != nil {
+			return r, err
+		}
+		if submitter {
+			return r, errors.New(msgr.SubmitterCannotReview)
+		}
+
+		comment := ctx.R.FormValue(paramReviewComment)
+		var action, message string
+		switch ctx.R.FormValue(paramReviewDecision) {
+		case ReviewStatusApproved:
+			action, message = ActivityApproveReview, msgr.SuccessfullyApprove
+		case ReviewStatusRejected:
+			if comment == "" {
+				return r, errors.New(msgr.ReviewCommentRequired)
+			}
+			action, message = ActivityRejectReview, msgr.SuccessfullyReject
+		default:
+			return r, errInvalidObject
+		}
+		now := db.NowFunc()
+		review.ReviewStatus = ctx.R.FormValue(paramReviewDecision)
+		review.ReviewComment = comment
+		review.ReviewedAt = &now
+
+		if err = mb.Editing().Saver(obj, slug, withReviewKept(ctx)); err != nil {
+			return r, err
+		}
+		publisher.logReview(ctx, mb, action, obj)
+
+		web.AppendRunScripts(&r, "locals.reviewDialog = false")
+		r.Emit(mb.NotifModelsUpdated(), presets.PayloadModelsUpdated{
+			Ids:    []string{slug},
+			Models: map[string]any{slug: obj},
+		})
+		web.AppendRunScripts(&r, web.Plaid().MergeQuery(true).
+			ThenScript(presets.ShowSnackbarScript(message, v.ColorSuccess)).
+			Go(),
+		)
+		return r, nil
+	}
+}
+
+func (b *Builder) logReview(ctx *web.EventContext, mb *presets.ModelBuilder, action string, obj any) {
+	if b.ab == nil {
+		return
+	}
+	if amb, exist := b.ab.GetModelBuilder(mb); exist {
+		review := EmbedReview(obj)
+		amb.Log(ctx.R.Context(), action, obj, ReviewLogDetail{
+			Reviewer: review.ReviewerName,
+			Comment:  review.ReviewComment,
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package publish_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/web/v3/multipartestutils"
+	"github.com/qor5/x/v3/oss"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/admin/v3/presets/gorm2op"
+	"github.com/qor5/admin/v3/publish"
+)
+
+type ReviewedProduct struct {
+	Product
+	publish.Review
+}
+
+func TestReviewApproved(t *testing.T) {
+	cases := []struct {
+		name string
+		obj  any
+		want bool
+	}{
+		{name: "without review", obj: &Product{}, want: true},
+		{name: "not submitted", obj: &ReviewedProduct{}, want: false},
+		{name: "pending", obj: &ReviewedProduct{Review: publish.Review{ReviewStatus: publish.ReviewStatusPending}}, want: false},
+		{name: "rejected", obj: &ReviewedProduct{Review: publish.Review{ReviewStatus: publish.ReviewStatusRejected}}, want: false},
+		{name: "approved", obj: &ReviewedProduct{Review: publish.Review{ReviewStatus: publish.ReviewStatusApproved}}, want: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := publish.ReviewApproved(c.obj); got != c.want {
+				t.Errorf("ReviewApproved() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+type ReviewProduct struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+
+	publish.Status
+	publish.Review
+}
+
+func (p *ReviewProduct) PrimarySlug() string {
+	return fmt.Sprint(p.ID)
+}
+
+func (*ReviewProduct) PrimaryColumnValuesBySlug(slug string) map[string]string {
+	return map[string]string{"id": slug}
+}
+
+func (p *ReviewProduct) GetPublishActions(ctx context.Context, db *gorm.DB, storage oss.StorageInterface) (actions []*publish.PublishAction, err error) {
+	p.OnlineUrl = fmt.Sprintf("test/review_product/%d/index.html", p.ID)
+	return []*publish.PublishAction{{Url: p.OnlineUrl, Content: p.Name}}, nil
+}
+
+func TestReviewWorkflow(t *testing.T) {
+	require.NoError(t, TestDB.AutoMigrate(&ReviewProduct{}))
+	require.NoError(t, TestDB.Where("1 = 1").Delete(&ReviewProduct{}).Error)
+
+	storage := &MockStorage{Objects: map[string]string{}}
+	pb := publish.New(TestDB, storage).CurrentReviewerFunc(func(ctx context.Context) (*publish.Reviewer, error) {
+		id, _ := ctx.Value(ctxKeyReviewer{}).(string)
+		return &publish.Reviewer{ID: id}, nil
+	})
+	ppb := presets.New().DataOperator(gorm2op.DataOperator(TestDB))
+	mb := ppb.Model(&ReviewProduct{})
+	require.NoError(t, pb.ModelInstall(ppb, mb))
+
+	pending := func(id uint, reviewerID string) *ReviewProduct {
+		p := &ReviewProduct{ID: id, Name: "Apple", Status: publish.Status{Status: publish.StatusDraft},
+			Review: publish.Review{ReviewStatus: publish.ReviewStatusPending, ReviewerID: reviewerID}}
+		require.NoError(t, TestDB.Save(p).Error)
+		return p
+	}
+	load := func(id uint) *ReviewProduct {
+		var p ReviewProduct
+		require.NoError(t, TestDB.First(&p, id).Error)
+		return &p
+	}
+	event := func(name string, id uint, reviewer string, fields map[string]string) string {
+		b := multipartestutils.NewMultipartBuilder().
+			PageURL(mb.Info().ListingHref()).
+			EventFunc(name).
+			Query(presets.ParamID, fmt.Sprint(id))
+		for k, v := range fields {
+			b.AddField(k, v)
+		}
+		r := b.BuildEventFuncRequest()
+		r = r.WithContext(context.WithValue(r.Context(), ctxKeyReviewer{}, reviewer))
+		w := httptest.NewRecorder()
+		ppb.ServeHTTP(w, r)
+		return w.Body.String()
+	}
+	review := func(id uint, reviewer, decision, comment string) string {
+		return event("publish_eventReview", id, reviewer, map[string]string{
+			"publish_param_review_decision": decision,
+			"publish_param_review_comment":  comment,
+		})
+	}
+
+	t.Run("publish gate", func(t *testing.T) {
+		p := pending(1, "")
+		body := event(publish.EventPublish, p.ID, "", nil)
+		require.Contains(t, body, publish.Messages_en_US.ReviewRequired)
+		require.Equal(t, publish.StatusDraft, load(p.ID).Status.Status)
+	})
+
+	t.Run("reset on save", func(t *testing.T) {
+		p := pending(2, "")
+		p.ReviewStatus = publish.ReviewStatusApproved
+		require.NoError(t, TestDB.Save(p).Error)
+
+		p.Name = "Banana"
+		evCtx := &web.EventContext{R: httptest.NewRequest("POST", mb.Info().ListingHref(), nil)}
+		require.NoError(t, mb.Editing().Saver(p, p.PrimarySlug(), evCtx))
+		saved := load(p.ID)
+		require.Equal(t, "Banana", saved.Name)
+		require.Empty(t, saved.ReviewStatus)
+		require.False(t, publish.ReviewApproved(saved))
+
+		// the review workflow keeps the review it saves
+		event("publish_eventSubmitReview", p.ID, "", nil)
+		require.Equal(t, publish.ReviewStatusPending, load(p.ID).ReviewStatus)
+	})
+
+	t.Run("assigned reviewer", func(t *testing.T) {
+		p := pending(3, "alice")
+		body := review(p.ID, "bob", publish.ReviewStatusApproved, "")
+		require.Contains(t, body, publish.Messages_en_US.NotAssignedReviewer)
+		require.Equal(t, publish.ReviewStatusPending, load(p.ID).ReviewStatus)
+
+		review(p.ID, "alice", publish.ReviewStatusApproved, "")
+		require.Equal(t, publish.ReviewStatusApproved, load(p.ID).ReviewStatus)
+		require.NotNil(t, load(p.ID).ReviewedAt)
+	})
+
+	t.Run("republish an edited online record", func(t *testing.T) {
+		p := pending(5, "")
+		p.ReviewStatus = publish.ReviewStatusApproved
+		p.Status.Status = publish.StatusOnline
+		require.NoError(t, TestDB.Save(p).Error)
+
+		p.Name = "Cherry"
+		evCtx := &web.EventContext{R: httptest.NewRequest("POST", mb.Info().ListingHref(), nil)}
+		require.NoError(t, mb.Editing().Saver(p, p.PrimarySlug(), evCtx))
+		require.Contains(t, event(publish.EventRepublish, p.ID, "carol", nil), publish.Messages_en_US.ReviewRequired)
+
+		event("publish_eventSubmitReview", p.ID, "carol", nil)
+		saved := load(p.ID)
+		require.Equal(t, publish.ReviewStatusPending, saved.ReviewStatus)
+		require.Equal(t, "carol", saved.SubmitterID)
+
+		// the submitter could not approve the own submission
+		require.Contains(t, review(p.ID, "carol", publish.ReviewStatusApproved, ""), publish.Messages_en_US.SubmitterCannotReview)
+		require.Equal(t, publish.ReviewStatusPending, load(p.ID).ReviewStatus)
+		review(p.ID, "dave", publish.ReviewStatusApproved, "")
+		require.Equal(t, publish.ReviewStatusApproved, load(p.ID).ReviewStatus)
+
+		event(publish.EventRepublish, p.ID, "carol", nil)
+		require.Equal(t, publish.StatusOnline, load(p.ID).Status.Status)
+		require.Equal(t, "Cherry", storage.Objects["test/review_product/5/index.html"])
+	})
+
+	t.Run("reject without comment", func(t *testing.T) {
+		p := pending(4, "")
+		body := review(p.ID, "", publish.ReviewStatusRejected, "")
+		require.Contains(t, body, publish.Messages_en_US.ReviewCommentRequired)
+		require.Equal(t, publish.ReviewStatusPending, load(p.ID).ReviewStatus)
+
+		review(p.ID, "", publish.ReviewStatusRejected, "too short")
+		saved := load(p.ID)
+		require.Equal(t, publish.ReviewStatusRejected, saved.ReviewStatus)
+		require.Equal(t, "too short", saved.ReviewComment)
+	})
+}
+
+type ctxKeyReviewer struct{}
@@ -123,8 +123,12 @@ func schedule(db *gorm.DB, mb *presets.ModelBuilder) web.EventFunc {
 		if err := setScheduledTimesFromForm(ctx, sc, db, mb); err != nil {
 			return r, err
 		}
+		if sc.EmbedSchedule().ScheduledStartAt != nil && !ReviewApproved(obj) {
+			msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+			return r, errors.New(msgr.ReviewRequired)
+		}
 
-		if err = mb.Editing().Saver(obj, slug, ctx); err != nil {
+		if err = mb.Editing().Saver(obj, slug, withReviewKept(ctx)); err != nil {
 			return r, err
 		}
 
@@ -52,7 +52,7 @@ func (b *SchedulePublishBuilder) Run(ctx context.Context, model interface{}) (er
 
 	{
 		tempRecords := records
-		scope := b.publisher.db
+		scope := b.publisher.db.WithContext(ctx)
 
 		fn, ok := ctx.Value(ctxKeyScheduleRecordsFinder{}).(ScheduleRecordsFinderFunc)
 		if ok && fn != nil {
@@ -79,17 +79,23 @@ func (b *SchedulePublishBuilder) Run(ctx context.Context, model interface{}) (er
 					continue
 				}
 			}
+			if ctx.Err() != nil {
+				return multierror.Append(err, ctx.Err()).ErrorOrNil()
+			}
 			record := needUnpublishReflectValues.Index(i).Interface()
 			if err2 := b.publisher.UnPublish(reqCtx, record); err2 != nil {
 				log.Printf("error: %s\n", err2)
 				err = multierror.Append(err, err2).ErrorOrNil()
+			} else if _, err2 := b.publisher.republishDependents(ctx, nil, record); err2 != nil {
+				log.Printf("error: %s\n", err2)
+				err = multierror.Append(err, err2).ErrorOrNil()
 			}
 		}
 	}
 
 	{
 		tempRecords := records
-		scope := b.publisher.db
+		scope := b.publisher.db.WithContext(ctx)
 
 		fn, ok := ctx.Value(ctxKeyScheduleRecordsFinder{}).(ScheduleRecordsFinderFunc)
 		if ok && fn != nil {
@@ -109,10 +115,20 @@ func (b *SchedulePublishBuilder) Run(ctx context.Context, model interface{}) (er
 
 		needPublishReflectValues := reflect.ValueOf(tempRecords)
 		for i := 0; i < needPublishReflectValues.Len(); i++ {
+			if ctx.Err() != nil {
+				return multierror.Append(err, ctx.Err()).ErrorOrNil()
+			}
 			record := needPublishReflectValues.Index(i).Interface()
+			if !ReviewApproved(record) {
+				// the review is withdrawn once the record is changed after it is scheduled
+				continue
+			}
 			if err2 := b.publisher.Publish(reqCtx, record); err2 != nil {
 				log.Printf("error: %s\n", err2)
 				err = multierror.Append(err, err2).ErrorOrNil()
+			} else if _, err2 := b.publisher.republishDependents(ctx, nil, record); err2 != nil {
+				log.Printf("error: %s\n", err2)
+				err = multierror.Append(err, err2).ErrorOrNil()
 			}
 		}
 	}
@@ -121,6 +137,9 @@ func (b *SchedulePublishBuilder) Run(ctx context.Context, model interface{}) (er
 		if err2 := b.publisher.UnPublish(reqCtx, record); err2 != nil {
 			log.Printf("error: %s\n", err2)
 			err = multierror.Append(err, err2).ErrorOrNil()
+		} else if _, err2 := b.publisher.republishDependents(ctx, nil, record); err2 != nil {
+			log.Printf("error: %s\n", err2)
+			err = multierror.Append(err, err2).ErrorOrNil()
 		}
 	}
 	return
@@ -0,0 +1,258 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qor5/x/v3/oss"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	schedulerIntervalDefault = time.Minute
+	schedulerTimeoutDefault  = 5 * time.Minute
+	// schedulerTickDelay starts the runs a little after the ticks, so that the records scheduled at the tick are included.
+	schedulerTickDelay = time.Second
+	// schedulerLeaseTTL is how long a lease lasts without being renewed, the holder renews it while the run goes on,
+	// so that the lease of a crashed replica expires soon while a slow run is never taken over.
+	schedulerLeaseTTL = 30 * time.Second
+)
+
+// PublishJob is the lease and the status of a scheduler job, shared by all the replicas.
+type PublishJob struct {
+	Name string `gorm:"primaryKey;size:255"`
+	// Holder is the replica which holds the lease until LeaseUntil.
+	Holder      string
+	LeaseUntil  *time.Time
+	LastRunAt   *time.Time
+	LastSpentMs int64
+	LastError   string
+	NextRunAt   *time.Time
+}
+
+type SchedulerJobFunc func(ctx context.Context) error
+
+type schedulerJob struct {
+	name string
+	f    SchedulerJobFunc
+}
+
+// Scheduler runs the jobs every interval on all the replicas, a database lease makes sure each run of a job is
+// done by only one of them. The leases are compared with the time of the database, so the clocks of the replicas
+// don't need to agree. A run is canceled through its context once it exceeds the timeout.
+type Scheduler struct {
+	db       *gorm.DB
+	holder   string
+	interval time.Duration
+	timeout  time.Duration
+	jobs     []*schedulerJob
+}
+
+func NewScheduler(db *gorm.DB) *Scheduler {
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		db:       db,
+		holder:   fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+		interval: schedulerIntervalDefault,
+		timeout:  schedulerTimeoutDefault,
+	}
+}
+
+// NewPublisherScheduler returns the Scheduler with the jobs of the schedule publisher and the list publisher of the models.
+func NewPublisherScheduler(db *gorm.DB, storage oss.StorageInterface, publisher *Builder) *Scheduler {
+	s := NewScheduler(db)
+
+	scheduleP := NewSchedulePublishBuilder(publisher)
+	for name, model := range publisher.nonVersionPublishModels {
+		s.Job(schedulePublishJobNamePrefix+"-"+name, func(ctx context.Context) error {
+			return scheduleP.Run(ctx, model)
+		})
+	}
+	for name, model := range publisher.versionPublishModels {
+		s.Job(schedulePublishJobNamePrefix+"-"+name, func(ctx context.Context) error {
+			return scheduleP.Run(ctx, model)
+		})
+	}
+
+	listP := NewListPublishBuilder(db, storage)
+	for name, model := range publisher.listPublishModels {
+		s.Job(listPublishJobNamePrefix+"-"+name, func(ctx context.Context) error {
+			return listP.Run(ctx, model)
+		})
+	}
+
+	if publisher.releases {
+		s.Job(releasePublishJobName, publisher.publishScheduledReleases)
+	}
+	return s
+}
+
+func (s *Scheduler) Interval(v time.Duration) (r *Scheduler) {
+	s.interval = v
+	return s
+}
+
+// Timeout cancels the context of a run once it is exceeded, the lease is renewed until the run returns.
+func (s *Scheduler) Timeout(v time.Duration) (r *Scheduler) {
+	s.timeout = v
+	return s
+}
+
+// Holder identifies the replica in the leases, defaults to the hostname, the pid and a random suffix.
+func (s *Scheduler) Holder(v string) (r *Scheduler) {
+	s.holder = v
+	return s
+}
+
+func (s *Scheduler) Job(name string, f SchedulerJobFunc) (r *Scheduler) {
+	s.jobs = append(s.jobs, &schedulerJob{name: name, f: f})
+	return s
+}
+
+func (s *Scheduler) AutoMigrate() error {
+	return s.db.AutoMigrate(&PublishJob{})
+}
+
+// Run blocks until the ctx is done, the running jobs are canceled through the ctx and waited for.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if err := s.AutoMigrate(); err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// Statuses returns the jobs run by any of the replicas.
+func (s *Scheduler) Statuses(ctx context.Context) (r []*PublishJob, err error) {
+	err = s.db.WithContext(ctx).Order("name").Find(&r).Error
+	return
+}
+
+func (s *Scheduler) loop(ctx context.Context, job *schedulerJob) {
+	for {
+		timer := time.NewTimer(time.Until(s.nextRunAt(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.runOnce(ctx, job)
+	}
+}
+
+func (s *Scheduler) nextRunAt(now time.Time) time.Time {
+	return now.Truncate(s.interval).Add(s.interval).Add(schedulerTickDelay)
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job *schedulerJob) {
+	start, acquired, err := s.acquire(ctx, job.name)
+	if err != nil {
+		log.Printf("job_name: %s, acquire lease error: %v\n", job.name, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	// the time of the database is followed with the monotonic clock during the run
+	localStart := time.Now()
+	dbNow := func() time.Time { return start.Add(time.Since(localStart)) }
+
+	runCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		s.renew(runCtx, job.name, dbNow)
+	}()
+	err = job.f(runCtx)
+	if err == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		err = runCtx.Err()
+	}
+	cancel()
+	<-renewed
+	stop := dbNow()
+	log.Printf("job_name: %s, started_at: %s, stopped_at: %s, time_spent_ms: %d\n", job.name, start, stop, stop.Sub(start).Milliseconds())
+	if err != nil {
+		log.Printf("job_name: %s, error: %v\n", job.name, err)
+	}
+
+	// the status is saved even if the ctx is canceled by the shutdown
+	if err := s.release(context.WithoutCancel(ctx), job.name, start, stop, err); err != nil {
+		log.Printf("job_name: %s, release lease error: %v\n", job.name, err)
+	}
+}
+
+// acquire takes the lease of the job if it is not held or expired, only one of the replicas succeeds at a time.
+// It returns the time of the database when the lease is taken.
+func (s *Scheduler) acquire(ctx context.Context, name string) (now time.Time, acquired bool, err error) {
+	db := s.db.WithContext(ctx)
+	if err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&PublishJob{Name: name}).Error; err != nil {
+		return
+	}
+	if err = db.Raw("SELECT CURRENT_TIMESTAMP").Scan(&now).Error; err != nil {
+		return
+	}
+	result := db.Model(&PublishJob{}).
+		Where("name = ? AND (lease_until IS NULL OR lease_until <= ?)", name, now).
+		Updates(map[string]any{
+			"holder":      s.holder,
+			"lease_until": now.Add(schedulerLeaseTTL),
+		})
+	return now, result.RowsAffected == 1, result.Error
+}
+
+// renew extends the lease of the job until the ctx is done.
+func (s *Scheduler) renew(ctx context.Context, name string, dbNow func() time.Time) {
+	ticker := time.NewTicker(schedulerLeaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		result := s.db.WithContext(ctx).Model(&PublishJob{}).
+			Where("name = ? AND holder = ?", name, s.holder).
+			Update("lease_until", dbNow().Add(schedulerLeaseTTL))
+		if result.Error != nil && ctx.Err() == nil {
+			log.Printf("job_name: %s, renew lease error: %v\n", name, result.Error)
+		} else if result.Error == nil && result.RowsAffected == 0 {
+			log.Printf("job_name: %s, the lease is lost\n", name)
+		}
+	}
+}
+
+// release holds the lease until the next run, so that the replicas whose ticks are late don't run the job again.
+// The next tick of the local clock is converted to the time of the database.
+func (s *Scheduler) release(ctx context.Context, name string, start, stop time.Time, runErr error) error {
+	next := stop.Add(time.Until(s.nextRunAt(time.Now())))
+	lastError := ""
+	if runErr != nil {
+		lastError = runErr.Error()
+	}
+	return s.db.WithContext(ctx).Model(&PublishJob{}).
+		Where("name = ? AND holder = ?", name, s.holder).
+		Updates(map[string]any{
+			"lease_until":   next.Add(-schedulerTickDelay),
+			"last_run_at":   start,
+			"last_spent_ms": stop.Sub(start).Milliseconds(),
+			"last_error":    lastError,
+			"next_run_at":   next,
+		}).Error
+}
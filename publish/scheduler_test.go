@@ -0,0 +1,91 @@
+package publish_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qor5/admin/v3/publish"
+)
+
+func TestSchedulerRunsEachTickOnce(t *testing.T) {
+	require.NoError(t, TestDB.Migrator().DropTable(&publish.PublishJob{}))
+
+	var count int32
+	job := func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3500*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, holder := range []string{"replica-1", "replica-2"} {
+		s := publish.NewScheduler(TestDB).Holder(holder).Interval(time.Second).Job("test-job", job)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Run(ctx); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 3 ticks at most in 3.5 seconds, every tick would be run twice without the lease
+	require.GreaterOrEqual(t, atomic.LoadInt32(&count), int32(1))
+	require.LessOrEqual(t, atomic.LoadInt32(&count), int32(4))
+
+	statuses, err := publish.NewScheduler(TestDB).Statuses(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	require.Equal(t, "test-job", statuses[0].Name)
+	require.NotNil(t, statuses[0].LastRunAt)
+	require.NotNil(t, statuses[0].NextRunAt)
+	require.Empty(t, statuses[0].LastError)
+}
+
+func TestSchedulerCancelsTimeoutRuns(t *testing.T) {
+	require.NoError(t, TestDB.Migrator().DropTable(&publish.PublishJob{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	s := publish.NewScheduler(TestDB).Interval(time.Second).Timeout(100*time.Millisecond).
+		Job("slow-job", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	require.NoError(t, s.Run(ctx))
+
+	statuses, err := s.Statuses(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	require.Equal(t, context.DeadlineExceeded.Error(), statuses[0].LastError)
+}
+
+func TestSchedulerSkipsHeldLeases(t *testing.T) {
+	require.NoError(t, TestDB.Migrator().DropTable(&publish.PublishJob{}))
+	require.NoError(t, TestDB.AutoMigrate(&publish.PublishJob{}))
+	leaseUntil := time.Now().Add(time.Hour)
+	require.NoError(t, TestDB.Create(&publish.PublishJob{Name: "held-job", Holder: "other", LeaseUntil: &leaseUntil}).Error)
+
+	var count int32
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	s := publish.NewScheduler(TestDB).Interval(time.Second).Job("held-job", func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+	require.NoError(t, s.Run(ctx))
+	require.Zero(t, atomic.LoadInt32(&count))
+
+	statuses, err := s.Statuses(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	require.Equal(t, "other", statuses[0].Holder)
+	require.Nil(t, statuses[0].LastRunAt)
+}
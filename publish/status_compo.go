@@ -167,6 +167,18 @@ func liveChips(status, toStatus string, msgr *Messages) h.HTMLComponent {
 	return statusChip(status, msgr)
 }
 
+func GetReviewStatusLabelColor(status string, msgr *Messages) (label, color string) {
+	switch status {
+	case ReviewStatusPending:
+		return msgr.ReviewStatusPending, ColorInfo
+	case ReviewStatusApproved:
+		return msgr.ReviewStatusApproved, ColorSuccess
+	case ReviewStatusRejected:
+		return msgr.ReviewStatusRejected, ColorError
+	}
+	return status, ColorSecondary
+}
+
 func GetStatusLabelColor(status string, msgr *Messages) (label, color string) {
 	switch status {
 	case StatusOnline:
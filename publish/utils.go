@@ -3,8 +3,6 @@ package publish
 import (
 	"context"
 	"log"
-	"os"
-	"time"
 
 	"github.com/qor5/web/v3"
 	"github.com/qor5/x/v3/i18n"
@@ -19,67 +17,15 @@ const (
 	listPublishJobNamePrefix     = "list-publisher"
 )
 
+// RunPublisher runs the publisher scheduler in the background until the ctx is done, use NewPublisherScheduler
+// to change its options or to wait for the running jobs on shutdown.
 func RunPublisher(ctx context.Context, db *gorm.DB, storage oss.StorageInterface, publisher *Builder) {
-	{ // schedule publisher
-		scheduleP := NewSchedulePublishBuilder(publisher)
-
-		for name, model := range publisher.nonVersionPublishModels {
-			go RunJob(schedulePublishJobNamePrefix+"-"+name, time.Minute, time.Minute*5, func() {
-				if err := scheduleP.Run(ctx, model); err != nil {
-					log.Printf("schedule publisher error: %v\n", err)
-				}
-			})
-		}
-
-		for name, model := range publisher.versionPublishModels {
-			go RunJob(schedulePublishJobNamePrefix+"-"+name, time.Minute, time.Minute*5, func() {
-				if err := scheduleP.Run(ctx, model); err != nil {
-					log.Printf("schedule publisher error: %v\n", err)
-				}
-			})
-		}
-	}
-
-	{ // list publisher
-		listP := NewListPublishBuilder(db, storage)
-		for name, model := range publisher.listPublishModels {
-			go RunJob(listPublishJobNamePrefix+"-"+name, time.Minute, time.Minute*5, func() {
-				if err := listP.Run(ctx, model); err != nil {
-					log.Printf("schedule publisher error: %v\n", err)
-				}
-			})
-		}
-	}
-}
-
-func RunJob(jobName string, interval, timeout time.Duration, f func()) {
-	second := 1
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	for now := range ticker.C {
-		targetTime := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute()+1, second, 0, now.Location())
-		time.Sleep(targetTime.Sub(now))
-
-		start := time.Now()
-		done := make(chan struct{})
-
-		go func() {
-			defer func() {
-				stop := time.Now()
-				log.Printf("job_name: %s, started_at: %s, stopped_at: %s, time_spent_ms: %d\n", jobName, start, stop, int64(stop.Sub(start)/time.Millisecond))
-			}()
-			f()
-			done <- struct{}{}
-		}()
-
-		select {
-		case <-done:
-		case <-time.After(timeout):
-			log.Printf("job_name: %s, started_at: %s, timeout: %s\n", jobName, start, time.Now())
-			os.Exit(124)
+	s := NewPublisherScheduler(db, storage, publisher)
+	go func() {
+		if err := s.Run(ctx); err != nil {
+			log.Printf("publish scheduler error: %v\n", err)
 		}
-	}
+	}()
 }
 
 const FilterKeyLive = "live"
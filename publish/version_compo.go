@@ -76,9 +76,28 @@ func DefaultVersionComponentFunc(mb *presets.ModelBuilder, cfg ...VersionCompone
 		div := h.Div().Class("tagList-bar-warp")
 
 		div.AppendChildren(
-			vx.VXDialog().
+			vx.VXDialog(
+				h.Span("{{ locals.message }}"),
+				// the dependents to be republished are previewed before confirming
+				h.Div(
+					web.Portal().Name(PortalDependentsPreview).Loader(web.Plaid().
+						EventFunc(eventDependentsPreview).
+						Query(presets.ParamID, slug).
+						Query(paramPublishAction, web.Var("locals.action")).
+						URL(mb.Info().ListingHref())),
+				).Attr("v-if", fmt.Sprintf("locals.commonConfirmDialog && %s.includes(locals.action)",
+					h.JSONString([]string{EventPublish, EventRepublish, EventUnpublish}))),
+				// what the action changes in the live storage
+				h.Div(
+					web.Portal().Name(PortalPublishDiffPreview).Loader(web.Plaid().
+						EventFunc(eventPublishDiffPreview).
+						Query(presets.ParamID, slug).
+						Query(paramPublishAction, web.Var("locals.action")).
+						URL(mb.Info().ListingHref())),
+				).Attr("v-if", fmt.Sprintf("locals.commonConfirmDialog && %s.includes(locals.action)",
+					h.JSONString([]string{EventPublish, EventRepublish, EventUnpublish}))),
+			).
 				Title(utilsMsgr.ModalTitleConfirm).
-				Attr(":text", "locals.message").
 				HideClose(true).
 				OkText(utilsMsgr.OK).
 				CancelText(utilsMsgr.Cancel).
@@ -100,8 +119,13 @@ func DefaultVersionComponentFunc(mb *presets.ModelBuilder, cfg ...VersionCompone
 					Attr("@click", fmt.Sprintf(`locals.action=%q;locals.commonConfirmDialog = true;locals.message = %q`, EventDuplicateVersion, msgr.ConfirmDuplicate)))
 			}
 		}
+		if _, ok := obj.(ReviewInterface); ok {
+			div.AppendChildren(buildReviewButton(obj, ctx, mb, slug, msgr, phraseHasPresetsDataChanged))
+		}
+
 		verifier := mb.Info().Verifier()
-		deniedPublish := DeniedDo(verifier, obj, ctx.R, PermPublish)
+		// only the approved records could be published once the review workflow is enabled
+		deniedPublish := DeniedDo(verifier, obj, ctx.R, PermPublish) || !ReviewApproved(obj)
 		deniedUnpublish := DeniedDo(verifier, obj, ctx.R, PermUnpublish)
 
 		if _, ok := obj.(StatusInterface); ok {
@@ -220,6 +244,48 @@ func buildPublishButton(obj interface{}, field *presets.FieldContext, ctx *web.E
 	return nil
 }
 
+func buildReviewButton(obj interface{}, ctx *web.EventContext, mb *presets.ModelBuilder, slug string, msgr *Messages, phraseHasPresetsDataChanged string) h.HTMLComponent {
+	review := EmbedReview(obj)
+	if review == nil {
+		return nil
+	}
+
+	var compos h.HTMLComponents
+	if review.ReviewStatus != "" {
+		label, color := GetReviewStatusLabelColor(review.ReviewStatus, msgr)
+		if review.ReviewerName != "" {
+			label = fmt.Sprintf("%s: %s", label, review.ReviewerName)
+		}
+		chip := v.VChip(h.Text(label)).Label(true).Color(color).Attr("style", "height:36px;").Class("ml-2")
+		if review.ReviewComment != "" {
+			chip.Attr("title", review.ReviewComment)
+		}
+		compos = append(compos, chip)
+	}
+
+	var event, text string
+	switch {
+	case canSubmitReview(mb, obj, ctx):
+		event, text = eventSubmitReviewDialog, msgr.SubmitForReview
+	case canReview(mb, obj, ctx):
+		event, text = eventReviewDialog, msgr.Review
+	}
+	if event != "" {
+		compos = append(compos,
+			v.VBtn(text).
+				Height(36).Class("ml-2").Variant(v.VariantOutlined).Color(v.ColorPrimary).
+				Attr(":disabled", phraseHasPresetsDataChanged).
+				Attr("@click", web.Plaid().
+					EventFunc(event).
+					Query(presets.ParamOverlay, actions.Dialog).
+					Query(presets.ParamID, slug).
+					URL(mb.Info().ListingHref()).Go()),
+			web.Portal().Name(PortalReviewDialog),
+		)
+	}
+	return compos
+}
+
 func buildScheduleButton(obj interface{}, ctx *web.EventContext, mb *presets.ModelBuilder, slug string, config VersionComponentConfig, msgr *Messages, phraseHasPresetsDataChanged string, deniedPublish, deniedUnpublish bool) h.HTMLComponent {
 	_, ok := obj.(ScheduleInterface)
 	if !ok {
@@ -336,7 +402,7 @@ func configureVersionListDialog(db *gorm.DB, pb *Builder, b *presets.Builder, pm
 	})
 
 	listingHref := mb.Info().ListingHref()
-	registerEventFuncsForVersion(mb, db)
+	registerEventFuncsForVersion(mb, pm, db, pb)
 	listingFields := []string{"Version", "Status", "StartAt", "EndAt", "Option"}
 	if pb.ab != nil {
 		defer func() {
@@ -465,7 +531,19 @@ func configureVersionListDialog(db *gorm.DB, pb *Builder, b *presets.Builder, pm
 		verifier := mb.Info().Verifier()
 		deniedUpdate := DeniedDo(verifier, obj, ctx.R, presets.PermUpdate)
 		deniedDelete := DeniedDo(verifier, obj, ctx.R, presets.PermDelete)
+		selected := MustFilterQuery(presets.ListingCompoFromEventContext(ctx)).Get(filterKeySelected)
 		return h.Td().Children(
+			h.If(selected != "" && selected != id,
+				v.VBtn(msgr.Compare).PrependIcon("mdi-compare-horizontal").Size(v.SizeXSmall).Color(v.ColorPrimary).Variant(v.VariantText).
+					On("click.stop", web.Plaid().
+						URL(listingHref).
+						EventFunc(eventCompareVersions).
+						Query(presets.ParamOverlay, actions.Dialog).
+						Query(presets.ParamID, id).
+						Query(paramCompareTo, selected).
+						Go(),
+					),
+			),
 			v.VBtn(msgr.Rename).Disabled(disablement.DisabledRename || deniedUpdate).PrependIcon("mdi-rename-box").Size(v.SizeXSmall).Color(v.ColorPrimary).Variant(v.VariantText).
 				On("click.stop", web.Plaid().
 					URL(listingHref).
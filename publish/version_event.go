@@ -2,6 +2,8 @@ package publish
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/qor5/admin/v3/presets"
@@ -9,16 +11,23 @@ import (
 	"github.com/qor5/web/v3"
 	"github.com/qor5/x/v3/i18n"
 	"github.com/qor5/x/v3/perm"
+	v "github.com/qor5/x/v3/ui/vuetify"
 	vx "github.com/qor5/x/v3/ui/vuetifyx"
+	"github.com/samber/lo"
 	"github.com/sunfmin/reflectutils"
+	h "github.com/theplant/htmlgo"
 	"gorm.io/gorm"
 )
 
 const (
 	PortalSchedulePublishDialog = "publish_PortalSchedulePublishDialog"
 	PortalPublishCustomDialog   = "publish_PortalPublishCustomDialog"
+	PortalDependentsPreview     = "publish_PortalDependentsPreview"
+	PortalPublishDiffPreview    = "publish_PortalPublishDiffPreview"
 
 	paramVersionName = "version_name"
+	paramCompareTo   = "publish_param_compare_to"
+	paramMergeFields = "publish_param_merge_fields"
 )
 
 func duplicateVersionAction(mb *presets.ModelBuilder, db *gorm.DB) web.EventFunc {
@@ -64,6 +73,11 @@ func duplicateVersionAction(mb *presets.ModelBuilder, db *gorm.DB) web.EventFunc
 			*sched = Schedule{}
 		}
 
+		review := EmbedReview(obj)
+		if review != nil {
+			*review = Review{}
+		}
+
 		_, err = reflectutils.Get(obj, "CreatedAt")
 		if err == nil {
 			if err = reflectutils.Set(obj, "CreatedAt", time.Time{}); err != nil {
@@ -163,7 +177,7 @@ func renameVersion(mb *presets.ModelBuilder) web.EventFunc {
 			return
 		}
 
-		if err = mb.Editing().Saver(obj, id, ctx); err != nil {
+		if err = mb.Editing().Saver(obj, id, withReviewKept(ctx)); err != nil {
 			return
 		}
 
@@ -256,3 +270,150 @@ func deleteVersion(mb *presets.ModelBuilder, db *gorm.DB) web.EventFunc {
 		return r, nil
 	}
 }
+
+// VersionMergeLogDetail is the detail of the activity log of the merged version.
+type VersionMergeLogDetail struct {
+	FromVersion string   `json:"from_version"`
+	Fields      []string `json:"fields"`
+}
+
+// fetchComparedVersions returns the version of the row and the selected version compared with.
+func fetchComparedVersions(mb *presets.ModelBuilder, ctx *web.EventContext) (from, to any, err error) {
+	for _, slug := range []string{ctx.R.FormValue(presets.ParamID), ctx.R.FormValue(paramCompareTo)} {
+		obj, err := mb.Editing().Fetcher(mb.NewModel(), slug, ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if DeniedDo(mb.Info().Verifier(), obj, ctx.R, presets.PermGet) {
+			return nil, nil, perm.PermissionDenied
+		}
+		if from == nil {
+			from = obj
+		} else {
+			to = obj
+		}
+	}
+	return
+}
+
+// compareVersions shows the fields having different values in the version of the row and the selected version,
+// the checked fields could be merged into the selected version if it is a draft.
+func compareVersions(mb, pm *presets.ModelBuilder, publisher *Builder) web.EventFunc {
+	return func(ctx *web.EventContext) (r web.EventResponse, err error) {
+		from, to, err := fetchComparedVersions(mb, ctx)
+		if err != nil {
+			return
+		}
+		diffs, err := publisher.CompareVersions(ctx.R.Context(), from, to)
+		if err != nil {
+			return
+		}
+
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		utilMsgr := i18n.MustGetModuleMessages(ctx.R, utils.I18nUtilsKey, utils.Messages_en_US).(*utils.Messages)
+		canMerge := len(diffs) > 0 && EmbedStatus(to).Status == StatusDraft &&
+			!DeniedDo(mb.Info().Verifier(), to, ctx.R, presets.PermUpdate)
+
+		var body h.HTMLComponent = h.Div(h.Text(msgr.NoVersionDifferences)).Class("text-medium-emphasis")
+		if len(diffs) > 0 {
+			var rows []h.HTMLComponent
+			for _, d := range diffs {
+				rows = append(rows, h.Tr(
+					h.If(canMerge, h.Td(v.VCheckbox().Value(d.Field).Attr("v-model", "locals.fields").
+						Disabled(!versionFieldUpdatable(pm, to, d.Field, ctx)).
+						HideDetails(true).Density(v.DensityCompact))),
+					h.Td(h.Text(i18n.PT(ctx.R, presets.ModelsI18nModuleKey, pm.Info().Label(), d.Field))).Class("font-weight-medium"),
+					h.Td(versionValueComponent(d.To)),
+					h.Td(versionValueComponent(d.From)),
+				))
+			}
+			body = v.VTable(
+				h.Thead(h.Tr(
+					h.If(canMerge, h.Th("")),
+					h.Th(msgr.VersionField),
+					h.Th(fmt.Sprintf("%s (%s)", to.(VersionInterface).EmbedVersion().VersionName, msgr.CurrentVersion)),
+					h.Th(from.(VersionInterface).EmbedVersion().VersionName),
+				)),
+				h.Tbody(rows...),
+			).Density(v.DensityCompact)
+		}
+
+		r.UpdatePortals = append(r.UpdatePortals, &web.PortalUpdate{
+			Name: presets.DialogPortalName,
+			Body: web.Scope(
+				vx.VXDialog(body).
+					Title(msgr.CompareVersions).
+					CancelText(utilMsgr.Cancel).
+					OkText(msgr.MergeIntoDraft).
+					HideOk(!canMerge).
+					Attr(":disable-ok", "locals.fields.length === 0 || isFetching").
+					Attr("@click:ok", web.Plaid().
+						URL(ctx.R.URL.Path).
+						EventFunc(eventMergeVersion).
+						Query(presets.ParamID, ctx.R.FormValue(presets.ParamID)).
+						Query(paramCompareTo, ctx.R.FormValue(paramCompareTo)).
+						Query(paramMergeFields, web.Var(`locals.fields.join(",")`)).
+						Go()).
+					Attr("v-model", "locals.compareVersionsDialog").
+					MaxWidth(900),
+			).Init("{compareVersionsDialog: true, fields: []}").VSlot("{locals}"),
+		})
+		return
+	}
+}
+
+// versionFieldUpdatable checks the field permission of the draft to merge the field into it.
+func versionFieldUpdatable(pm *presets.ModelBuilder, to any, field string, ctx *web.EventContext) bool {
+	return pm.Info().Verifier().Do(presets.PermUpdate).ObjectOn(to).SnakeOn("f_"+field).WithReq(ctx.R).IsAllowed() == nil
+}
+
+func versionValueComponent(value string) h.HTMLComponent {
+	return h.Div(h.Text(value)).Class("text-body-2 overflow-y-auto").
+		Style("white-space: pre-wrap; word-break: break-word; max-height: 200px;")
+}
+
+func mergeVersion(mb, pm *presets.ModelBuilder, publisher *Builder) web.EventFunc {
+	return func(ctx *web.EventContext) (r web.EventResponse, err error) {
+		defer func() {
+			if err != nil {
+				presets.ShowMessage(&r, err.Error(), "error")
+				err = nil
+			}
+		}()
+
+		from, to, err := fetchComparedVersions(mb, ctx)
+		if err != nil {
+			return
+		}
+		if DeniedDo(mb.Info().Verifier(), to, ctx.R, presets.PermUpdate) {
+			return r, perm.PermissionDenied
+		}
+		fields := lo.Compact(strings.Split(ctx.R.FormValue(paramMergeFields), ","))
+		for _, field := range fields {
+			if !versionFieldUpdatable(pm, to, field, ctx) {
+				return r, perm.PermissionDenied
+			}
+		}
+		if err = publisher.MergeVersion(ctx, pm, from, to, fields); err != nil {
+			return
+		}
+		if publisher.ab != nil {
+			if amb, exist := publisher.ab.GetModelBuilder(pm); exist {
+				amb.Log(ctx.R.Context(), ActivityMergeVersion, to, VersionMergeLogDetail{
+					FromVersion: from.(VersionInterface).EmbedVersion().VersionName,
+					Fields:      fields,
+				})
+			}
+		}
+
+		msgr := i18n.MustGetModuleMessages(ctx.R, I18nPublishKey, Messages_en_US).(*Messages)
+		slug := to.(presets.SlugEncoder).PrimarySlug()
+		web.AppendRunScripts(&r, "locals.compareVersionsDialog = false")
+		r.Emit(pm.NotifModelsUpdated(), presets.PayloadModelsUpdated{
+			Ids:    []string{slug},
+			Models: map[string]any{slug: to},
+		})
+		presets.ShowMessage(&r, msgr.SuccessfullyMergeVersion, v.ColorSuccess)
+		return
+	}
+}
@@ -0,0 +1,226 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/qor5/web/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/admin/v3/presets/gorm2op"
+	"github.com/qor5/admin/v3/utils"
+)
+
+// VersionField is a part of the versions stored outside of the model, like the containers of the pagebuilder pages,
+// it is compared and merged along with the fields of the model.
+type VersionField struct {
+	Name string
+	// ValueFunc returns the value of the version to compare and show.
+	ValueFunc func(ctx context.Context, db *gorm.DB, obj any) (string, error)
+	// MergeFunc replaces the field of the to version with the one of the from version in the transaction of the merge.
+	MergeFunc func(ctx context.Context, tx *gorm.DB, from, to any) error
+}
+
+// VersionFieldDiff is a field having different values in two versions of a record.
+type VersionFieldDiff struct {
+	Field string
+	From  string
+	To    string
+}
+
+// the fields of the embedded structs are not compared, they are managed by the versions themselves
+var versionIgnoredEmbeds = map[reflect.Type]bool{
+	reflect.TypeOf(gorm.Model{}): true,
+	reflect.TypeOf(Status{}):     true,
+	reflect.TypeOf(Schedule{}):   true,
+	reflect.TypeOf(Version{}):    true,
+	reflect.TypeOf(List{}):       true,
+	reflect.TypeOf(Review{}):     true,
+}
+
+// VersionField adds the field to compare and merge for the versions of the model.
+func (b *Builder) VersionField(model any, f *VersionField) (r *Builder) {
+	t := modelType(model)
+	b.versionFields[t] = append(b.versionFields[t], f)
+	return b
+}
+
+// CompareVersions returns the fields having different values in the versions of the same record,
+// in the order of the fields of the model followed by the VersionFields.
+func (b *Builder) CompareVersions(ctx context.Context, from, to any) (r []*VersionFieldDiff, err error) {
+	if err = checkSameRecord(from, to); err != nil {
+		return
+	}
+	names, err := b.versionModelFields(to)
+	if err != nil {
+		return
+	}
+	fromV, toV := reflect.Indirect(reflect.ValueOf(from)), reflect.Indirect(reflect.ValueOf(to))
+	for _, name := range names {
+		fv, tv := fromV.FieldByName(name).Interface(), toV.FieldByName(name).Interface()
+		if reflect.DeepEqual(fv, tv) {
+			continue
+		}
+		r = append(r, &VersionFieldDiff{Field: name, From: formatVersionValue(fv), To: formatVersionValue(tv)})
+	}
+
+	db := b.db.WithContext(ctx)
+	for _, f := range b.versionFields[modelType(to)] {
+		fv, err := f.ValueFunc(ctx, db, from)
+		if err != nil {
+			return nil, err
+		}
+		tv, err := f.ValueFunc(ctx, db, to)
+		if err != nil {
+			return nil, err
+		}
+		if fv != tv {
+			r = append(r, &VersionFieldDiff{Field: f.Name, From: fv, To: tv})
+		}
+	}
+	return
+}
+
+// MergeVersion sets the fields of the draft version to the values of the other version of the same record,
+// then validates and saves it by the editing of the model as the edits do, the review of it is withdrawn.
+// The VersionFields are merged in the transaction of the save, which is passed to the saver by gorm2op.CtxKeyDB.
+func (b *Builder) MergeVersion(evCtx *web.EventContext, mb *presets.ModelBuilder, from, to any, fields []string) (err error) {
+	if err = checkSameRecord(from, to); err != nil {
+		return
+	}
+	if status := EmbedStatus(to); status == nil || status.Status != StatusDraft {
+		return errors.New("only the draft version can be merged into")
+	}
+	names, err := b.versionModelFields(to)
+	if err != nil {
+		return
+	}
+
+	var (
+		columns []string
+		merges  []*VersionField
+	)
+	versionFields := b.versionFields[modelType(to)]
+	for _, field := range fields {
+		if slices.Contains(names, field) {
+			columns = append(columns, field)
+			continue
+		}
+		i := slices.IndexFunc(versionFields, func(f *VersionField) bool { return f.Name == field })
+		if i < 0 {
+			return fmt.Errorf("unknown version field %s", field)
+		}
+		merges = append(merges, versionFields[i])
+	}
+
+	fromV, toV := reflect.Indirect(reflect.ValueOf(from)), reflect.Indirect(reflect.ValueOf(to))
+	for _, name := range columns {
+		toV.FieldByName(name).Set(fromV.FieldByName(name))
+	}
+	ResetReview(to)
+
+	eb := mb.Editing()
+	if eb.Validator != nil {
+		if vErr := eb.Validator(to, evCtx); vErr.HaveErrors() {
+			return &vErr
+		}
+	}
+	slug := to.(presets.SlugEncoder).PrimarySlug()
+	return utils.Transact(b.db.WithContext(evCtx.R.Context()), func(tx *gorm.DB) error {
+		txCtx := *evCtx
+		txCtx.R = evCtx.R.WithContext(context.WithValue(evCtx.R.Context(), gorm2op.CtxKeyDB{}, tx))
+		if err := eb.Saver(to, slug, &txCtx); err != nil {
+			return err
+		}
+		for _, f := range merges {
+			if err := f.MergeFunc(txCtx.R.Context(), tx, from, to); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// versionModelFields returns the names of the fields of the model to compare, the primary keys, the timestamps
+// and the fields of the embedded publish structs are excluded.
+func (b *Builder) versionModelFields(obj any) (names []string, err error) {
+	s, err := schema.Parse(obj, &sync.Map{}, b.db.NamingStrategy)
+	if err != nil {
+		return
+	}
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if f.Anonymous {
+				if f.Type.Kind() == reflect.Struct && !versionIgnoredEmbeds[f.Type] {
+					walk(f.Type)
+				}
+				continue
+			}
+			sf := s.LookUpField(f.Name)
+			if sf == nil || sf.DBName == "" || sf.PrimaryKey || sf.AutoCreateTime > 0 || sf.AutoUpdateTime > 0 ||
+				f.Type == reflect.TypeOf(gorm.DeletedAt{}) {
+				continue
+			}
+			names = append(names, f.Name)
+		}
+	}
+	walk(s.ModelType)
+	return
+}
+
+func checkSameRecord(from, to any) error {
+	if reflect.TypeOf(from) != reflect.TypeOf(to) {
+		return errors.New("the versions are of different models")
+	}
+	if _, ok := to.(VersionInterface); !ok {
+		return errors.New("the model has no versions")
+	}
+	if !sameRecord(to, from.(presets.SlugEncoder).PrimarySlug(), to.(presets.SlugEncoder).PrimarySlug()) {
+		return errors.New("the versions are of different records")
+	}
+	return nil
+}
+
+func formatVersionValue(v any) string {
+	switch vv := v.(type) {
+	case string:
+		return vv
+	case time.Time:
+		if vv.IsZero() {
+			return ""
+		}
+		return vv.Local().Format(time.DateTime)
+	case *time.Time:
+		if vv == nil {
+			return ""
+		}
+		return vv.Local().Format(time.DateTime)
+	case fmt.Stringer:
+		return vv.String()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return ""
+	}
+	if k := reflect.Indirect(rv).Kind(); k != reflect.Struct && k != reflect.Map && k != reflect.Slice {
+		return fmt.Sprint(reflect.Indirect(rv).Interface())
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,98 @@
+package publish_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/admin/v3/presets/gorm2op"
+	"github.com/qor5/admin/v3/publish"
+)
+
+type MergeProduct struct {
+	ID    uint `gorm:"primaryKey"`
+	Name  string
+	Price int
+	Note  string
+
+	publish.Status
+	publish.Version
+	publish.Review
+}
+
+func (p *MergeProduct) PrimarySlug() string {
+	return fmt.Sprintf("%d_%s", p.ID, p.Version.Version)
+}
+
+func (*MergeProduct) PrimaryColumnValuesBySlug(slug string) map[string]string {
+	id, version, _ := strings.Cut(slug, "_")
+	return map[string]string{"id": id, publish.SlugVersion: version}
+}
+
+func TestCompareAndMergeVersions(t *testing.T) {
+	require.NoError(t, TestDB.AutoMigrate(&MergeProduct{}))
+	require.NoError(t, TestDB.Where("1 = 1").Delete(&MergeProduct{}).Error)
+
+	from := &MergeProduct{ID: 1, Name: "Apple", Price: 3, Note: "note", Status: publish.Status{Status: publish.StatusOnline}, Version: publish.Version{Version: "v1"}}
+	to := &MergeProduct{ID: 1, Name: "Apple", Price: 5, Note: "draft", Status: publish.Status{Status: publish.StatusDraft}, Version: publish.Version{Version: "v2"},
+		Review: publish.Review{ReviewStatus: publish.ReviewStatusApproved}}
+	other := &MergeProduct{ID: 2, Name: "Banana", Status: publish.Status{Status: publish.StatusDraft}, Version: publish.Version{Version: "v1"}}
+	require.NoError(t, TestDB.Create([]*MergeProduct{from, to, other}).Error)
+
+	tags := map[string]string{"v1": "fruit", "v2": "sale"}
+	pb := publish.New(TestDB, nil).VersionField(&MergeProduct{}, &publish.VersionField{
+		Name: "Tags",
+		ValueFunc: func(_ context.Context, _ *gorm.DB, obj any) (string, error) {
+			return tags[obj.(*MergeProduct).Version.Version], nil
+		},
+		MergeFunc: func(_ context.Context, _ *gorm.DB, from, to any) error {
+			tags[to.(*MergeProduct).Version.Version] = tags[from.(*MergeProduct).Version.Version]
+			return nil
+		},
+	})
+	mb := presets.New().DataOperator(gorm2op.DataOperator(TestDB)).Model(&MergeProduct{})
+	mb.Editing().ValidateFunc(func(obj interface{}, _ *web.EventContext) (err web.ValidationErrors) {
+		if obj.(*MergeProduct).Note == "note" {
+			err.FieldError("Note", "note is reserved")
+		}
+		return
+	})
+
+	ctx := context.Background()
+	diffs, err := pb.CompareVersions(ctx, from, to)
+	require.NoError(t, err)
+	require.Equal(t, []*publish.VersionFieldDiff{
+		{Field: "Price", From: "3", To: "5"},
+		{Field: "Note", From: "note", To: "draft"},
+		{Field: "Tags", From: "fruit", To: "sale"},
+	}, diffs)
+	_, err = pb.CompareVersions(ctx, other, to)
+	require.Error(t, err)
+
+	evCtx := &web.EventContext{R: httptest.NewRequest("POST", "/merge-products", nil)}
+	// only drafts are merged into
+	require.Error(t, pb.MergeVersion(evCtx, mb, to, from, []string{"Price"}))
+	require.Error(t, pb.MergeVersion(evCtx, mb, from, to, []string{"Unknown"}))
+	// the merged draft is validated by the editing
+	require.Error(t, pb.MergeVersion(evCtx, mb, from, to, []string{"Note"}))
+
+	to = &MergeProduct{}
+	require.NoError(t, TestDB.First(to, "id = ? AND version = ?", 1, "v2").Error)
+	require.NoError(t, pb.MergeVersion(evCtx, mb, from, to, []string{"Price", "Tags"}))
+
+	var saved MergeProduct
+	require.NoError(t, TestDB.First(&saved, "id = ? AND version = ?", 1, "v2").Error)
+	require.Equal(t, 3, saved.Price)
+	require.Equal(t, "draft", saved.Note)
+	require.Equal(t, "fruit", tags["v2"])
+	// the approval of the changed draft is withdrawn
+	require.Empty(t, saved.ReviewStatus)
+	require.False(t, publish.ReviewApproved(&saved))
+}
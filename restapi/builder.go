@@ -0,0 +1,120 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/samber/lo"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/admin/v3/presets/gorm2op"
+)
+
+type (
+	// Builder exposes JSON REST endpoints of the presets models,
+	// the records are loaded and saved by the Search/Fetch/Save/Delete funcs of the models,
+	// with the same permission checks and validations as the admin.
+	Builder struct {
+		pb              *presets.Builder
+		prefix          string
+		title           string
+		version         string
+		relayPagination presets.RelayPagination
+		maxBodyBytes    int64
+		models          []*ModelBuilder
+		handler         http.Handler
+	}
+
+	Handler interface {
+		Handle(pattern string, handler http.Handler)
+	}
+)
+
+func New(pb *presets.Builder) *Builder {
+	return &Builder{
+		pb:              pb,
+		prefix:          "/api",
+		title:           "API",
+		version:         "1.0.0",
+		relayPagination: gorm2op.OffsetBasedPagination(false),
+		maxBodyBytes:    1 << 20,
+	}
+}
+
+func (b *Builder) Prefix(v string) *Builder {
+	b.prefix = v
+	return b
+}
+
+// Info sets the title and version of the OpenAPI document.
+func (b *Builder) Info(title, version string) *Builder {
+	b.title = title
+	b.version = version
+	return b
+}
+
+// RelayPagination is used by the models whose listing has no relay pagination,
+// defaults to the offset based pagination of gorm2op.
+func (b *Builder) RelayPagination(v presets.RelayPagination) *Builder {
+	b.relayPagination = v
+	return b
+}
+
+// MaxBodyBytes limits the size of the create and update bodies, defaults to 1MB.
+func (b *Builder) MaxBodyBytes(v int64) *Builder {
+	b.maxBodyBytes = v
+	return b
+}
+
+// Model exposes the presets model, its endpoints are mounted at the URI name of the model.
+func (b *Builder) Model(mb *presets.ModelBuilder) (r *ModelBuilder) {
+	if r = b.GetModel(mb); r != nil {
+		return
+	}
+	r = &ModelBuilder{p: b, mb: mb}
+	b.models = append(b.models, r)
+	return
+}
+
+func (b *Builder) GetModel(mb *presets.ModelBuilder) *ModelBuilder {
+	r, _ := lo.Find(b.models, func(m *ModelBuilder) bool {
+		return m.mb == mb
+	})
+	return r
+}
+
+func (b *Builder) OpenAPIHref() string {
+	return fmt.Sprintf("%s/openapi.json", b.prefix)
+}
+
+func (b *Builder) Mux(mux Handler) {
+	mns := lo.Map(b.models, func(m *ModelBuilder, _ int) string {
+		return m.mb.Info().URIName()
+	})
+	if len(lo.Uniq(mns)) != len(mns) {
+		panic(fmt.Sprintf("Duplicated model names registered %v", mns))
+	}
+	for _, m := range b.models {
+		mux.Handle(m.href(), m)
+		mux.Handle(m.href()+"/", m)
+	}
+	mux.Handle(b.OpenAPIHref(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, b.OpenAPI())
+	}))
+}
+
+func (b *Builder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if b.handler == nil {
+		mux := http.NewServeMux()
+		b.Mux(mux)
+		b.handler = mux
+	}
+	b.handler.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,408 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/perm"
+	"github.com/samber/lo"
+	"github.com/theplant/relay"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+const (
+	ParamKeyword = "keyword"
+	ParamFilter  = "filter"
+	ParamOrderBy = "order_by"
+	ParamFirst   = "first"
+	ParamAfter   = "after"
+	ParamLast    = "last"
+	ParamBefore  = "before"
+)
+
+type (
+	ModelBuilder struct {
+		p           *Builder
+		mb          *presets.ModelBuilder
+		fields      []string
+		writeFields []string
+	}
+
+	ListResponse struct {
+		Nodes      []map[string]any `json:"nodes"`
+		PageInfo   relay.PageInfo   `json:"page_info"`
+		TotalCount *int             `json:"total_count,omitempty"`
+	}
+
+	// ErrorResponse carries web.ValidationErrors as GlobalErrors and FieldErrors, other errors as Error.
+	ErrorResponse struct {
+		Error        string              `json:"error,omitempty"`
+		GlobalErrors []string            `json:"global_errors,omitempty"`
+		FieldErrors  map[string][]string `json:"field_errors,omitempty"`
+	}
+)
+
+// Fields are the fields in the responses, defaults to ID and the listing fields of the model.
+func (b *ModelBuilder) Fields(vs ...string) *ModelBuilder {
+	b.fields = vs
+	return b
+}
+
+// WriteFields are the fields could be set by create and update, defaults to the editing fields of the model.
+func (b *ModelBuilder) WriteFields(vs ...string) *ModelBuilder {
+	b.writeFields = vs
+	return b
+}
+
+func (b *ModelBuilder) href() string {
+	return fmt.Sprintf("%s/%s", b.p.prefix, b.mb.Info().URIName())
+}
+
+func (b *ModelBuilder) modelType() reflect.Type {
+	return reflect.TypeOf(b.mb.NewModel()).Elem()
+}
+
+// structFields filters the presets fields without a struct field, like the virtual listing columns.
+func (b *ModelBuilder) structFields(names []any) (r []string) {
+	t := b.modelType()
+	for _, n := range names {
+		name := fmt.Sprint(n)
+		if _, ok := t.FieldByName(name); ok {
+			r = append(r, name)
+		}
+	}
+	return
+}
+
+func (b *ModelBuilder) getFields() []string {
+	if len(b.fields) > 0 {
+		return b.fields
+	}
+	fields := b.structFields(b.mb.Listing().FieldNames())
+	if _, ok := b.modelType().FieldByName("ID"); ok && !lo.Contains(fields, "ID") {
+		fields = append([]string{"ID"}, fields...)
+	}
+	return fields
+}
+
+func (b *ModelBuilder) getWriteFields() []string {
+	if len(b.writeFields) > 0 {
+		return b.writeFields
+	}
+	return b.structFields(b.mb.Editing().FieldNames())
+}
+
+func (b *ModelBuilder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	evCtx := &web.EventContext{R: r, W: w}
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, b.href()), "/")
+
+	var (
+		status = http.StatusOK
+		resp   any
+		err    error
+	)
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		resp, err = b.list(evCtx)
+	case id == "" && r.Method == http.MethodPost:
+		status = http.StatusCreated
+		resp, err = b.save(evCtx, "")
+	case id != "" && r.Method == http.MethodGet:
+		resp, err = b.get(evCtx, id)
+	case id != "" && (r.Method == http.MethodPut || r.Method == http.MethodPatch):
+		resp, err = b.save(evCtx, id)
+	case id != "" && r.Method == http.MethodDelete:
+		status = http.StatusNoContent
+		err = b.delete(evCtx, id)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: http.StatusText(http.StatusMethodNotAllowed)})
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	writeJSON(w, status, resp)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var vErr *web.ValidationErrors
+	switch {
+	case errors.As(err, &vErr):
+		writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{
+			GlobalErrors: vErr.GetGlobalErrors(),
+			FieldErrors:  vErr.FieldErrors(),
+		})
+	case errors.Is(err, perm.PermissionDenied):
+		writeJSON(w, http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, presets.ErrRecordNotFound):
+		writeJSON(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, errBadRequest), errors.Is(err, presets.ErrInvalidFilter):
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*http.MaxBytesError)):
+		writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{Error: http.StatusText(http.StatusRequestEntityTooLarge)})
+	default:
+		// the internal errors may carry the details of the database, they are only logged
+		log.Printf("restapi: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: http.StatusText(http.StatusInternalServerError)})
+	}
+}
+
+var errBadRequest = errors.New("bad request")
+
+func (b *ModelBuilder) isAllowed(evCtx *web.EventContext, action string, obj any) error {
+	v := b.mb.Info().Verifier().Do(action)
+	if obj != nil {
+		v = v.ObjectOn(obj)
+	}
+	return v.WithReq(evCtx.R).IsAllowed()
+}
+
+// toJSON returns the fields of the object which the request is allowed to get.
+func (b *ModelBuilder) toJSON(evCtx *web.EventContext, obj any) map[string]any {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	r := make(map[string]any)
+	for _, name := range b.getFields() {
+		if b.mb.Info().Verifier().Do(presets.PermGet).ObjectOn(obj).SnakeOn("f_"+name).WithReq(evCtx.R).IsAllowed() != nil {
+			continue
+		}
+		fv := v.FieldByName(name)
+		if !fv.IsValid() {
+			continue
+		}
+		r[name] = fv.Interface()
+	}
+	return r
+}
+
+func parseOrderBy(v string) (r []presets.ColOrderBy) {
+	for _, f := range strings.Split(v, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		ob := presets.ColOrderBy{FieldName: f, OrderBy: presets.OrderByASC}
+		if strings.HasPrefix(f, "-") {
+			ob = presets.ColOrderBy{FieldName: f[1:], OrderBy: presets.OrderByDESC}
+		}
+		r = append(r, ob)
+	}
+	return
+}
+
+func paramAsIntPtr(r *http.Request, key string) (*int, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i < 0 {
+		return nil, errors.Wrapf(errBadRequest, "invalid %s: %s", key, v)
+	}
+	// the page size is limited like the listing of the admin
+	i = min(i, presets.PerPageMax)
+	return &i, nil
+}
+
+func paramAsStringPtr(r *http.Request, key string) *string {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+// list accepts the keyword, the presets.Filter tree as JSON, the order by like `Name,-CreatedAt`
+// and the relay cursor pagination params first/after or last/before.
+func (b *ModelBuilder) list(evCtx *web.EventContext) (*ListResponse, error) {
+	if err := b.isAllowed(evCtx, presets.PermList, nil); err != nil {
+		return nil, err
+	}
+
+	r := evCtx.R
+	var filter *presets.Filter
+	if v := r.URL.Query().Get(ParamFilter); v != "" {
+		filter = &presets.Filter{}
+		if err := json.Unmarshal([]byte(v), filter); err != nil {
+			return nil, errors.Wrapf(errBadRequest, "invalid filter: %v", err)
+		}
+		if err := b.checkFilterFields(evCtx, filter); err != nil {
+			return nil, err
+		}
+	}
+
+	lb := b.mb.Listing()
+	params := lb.NewSearchParams(evCtx, r.URL.Query().Get(ParamKeyword), filter, parseOrderBy(r.URL.Query().Get(ParamOrderBy)))
+	params.RelayPagination = lb.GetRelayPagination()
+	if params.RelayPagination == nil {
+		params.RelayPagination = b.p.relayPagination
+	}
+
+	req := &relay.PaginateRequest[any]{
+		After:   paramAsStringPtr(r, ParamAfter),
+		Before:  paramAsStringPtr(r, ParamBefore),
+		OrderBy: params.OrderBy,
+	}
+	var err error
+	if req.First, err = paramAsIntPtr(r, ParamFirst); err != nil {
+		return nil, err
+	}
+	if req.Last, err = paramAsIntPtr(r, ParamLast); err != nil {
+		return nil, err
+	}
+	if req.First == nil && req.Last == nil {
+		req.First = lo.ToPtr(int(presets.PerPageDefault))
+	}
+	params.RelayPaginateRequest = req
+
+	result, err := lb.Searcher(evCtx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListResponse{
+		Nodes:      []map[string]any{},
+		PageInfo:   result.PageInfo,
+		TotalCount: result.TotalCount,
+	}
+	nodes := reflect.ValueOf(result.Nodes)
+	for i := 0; i < nodes.Len(); i++ {
+		resp.Nodes = append(resp.Nodes, b.toJSON(evCtx, nodes.Index(i).Interface()))
+	}
+	return resp, nil
+}
+
+// checkFilterFields only allows filtering by the fields in the responses which the request is allowed to list and get,
+// so that the other columns and the relations can't be probed by the conditions.
+func (b *ModelBuilder) checkFilterFields(evCtx *web.EventContext, f *presets.Filter) error {
+	if f == nil {
+		return nil
+	}
+	if c := f.Condition; c != nil {
+		if !lo.Contains(b.getFields(), c.Field) {
+			return errors.Wrapf(presets.ErrInvalidFilter, "field %s is not filterable", c.Field)
+		}
+		for _, action := range []string{presets.PermList, presets.PermGet} {
+			if err := b.mb.Info().Verifier().Do(action).SnakeOn("f_" + c.Field).WithReq(evCtx.R).IsAllowed(); err != nil {
+				return err
+			}
+		}
+	}
+	for _, sub := range append(append([]*presets.Filter{f.Not}, f.And...), f.Or...) {
+		if err := b.checkFilterFields(evCtx, sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *ModelBuilder) get(evCtx *web.EventContext, id string) (map[string]any, error) {
+	obj, err := b.mb.Editing().Fetcher(b.mb.NewModel(), id, evCtx)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.isAllowed(evCtx, presets.PermGet, obj); err != nil {
+		return nil, err
+	}
+	return b.toJSON(evCtx, obj), nil
+}
+
+// unmarshal sets the writable fields in the request body to the object, the body larger than
+// Builder.MaxBodyBytes is rejected with a *http.MaxBytesError.
+func (b *ModelBuilder) unmarshal(evCtx *web.EventContext, obj any, action string) (vErr web.ValidationErrors, err error) {
+	var body map[string]json.RawMessage
+	if err = json.NewDecoder(http.MaxBytesReader(evCtx.W, evCtx.R.Body, b.p.maxBodyBytes)).Decode(&body); err != nil {
+		if errors.As(err, new(*http.MaxBytesError)) {
+			return vErr, err
+		}
+		vErr.GlobalError(fmt.Sprintf("invalid body: %v", err))
+		return vErr, nil
+	}
+
+	writeFields := b.getWriteFields()
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	for name, raw := range body {
+		if !lo.Contains(writeFields, name) {
+			vErr.FieldError(name, "field is not writable")
+			continue
+		}
+		if b.mb.Info().Verifier().Do(action).ObjectOn(obj).SnakeOn("f_"+name).WithReq(evCtx.R).IsAllowed() != nil {
+			vErr.FieldError(name, perm.PermissionDenied.Error())
+			continue
+		}
+		fv := v.FieldByName(name)
+		if !fv.IsValid() || !fv.CanSet() {
+			vErr.FieldError(name, "field is not writable")
+			continue
+		}
+		if err := json.Unmarshal(raw, fv.Addr().Interface()); err != nil {
+			vErr.FieldError(name, err.Error())
+		}
+	}
+	return vErr, nil
+}
+
+func (b *ModelBuilder) save(evCtx *web.EventContext, id string) (map[string]any, error) {
+	eb := b.mb.Editing()
+	action := presets.PermCreate
+	obj := b.mb.NewModel()
+	if id != "" {
+		action = presets.PermUpdate
+		var err error
+		if obj, err = eb.Fetcher(obj, id, evCtx); err != nil {
+			return nil, err
+		}
+		if err = b.isAllowed(evCtx, action, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	if eb.Setter != nil {
+		eb.Setter(obj, evCtx)
+	}
+	vErr, err := b.unmarshal(evCtx, obj, action)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		if err := b.isAllowed(evCtx, action, obj); err != nil {
+			return nil, err
+		}
+	}
+	if eb.Validator != nil {
+		vErrValidator := eb.Validator(obj, evCtx)
+		_ = vErr.Merge(&vErrValidator)
+	}
+	if vErr.HaveErrors() {
+		return nil, &vErr
+	}
+
+	if err := eb.Saver(obj, id, evCtx); err != nil {
+		return nil, err
+	}
+	return b.toJSON(evCtx, obj), nil
+}
+
+func (b *ModelBuilder) delete(evCtx *web.EventContext, id string) error {
+	eb := b.mb.Editing()
+	obj, err := eb.Fetcher(b.mb.NewModel(), id, evCtx)
+	if err != nil {
+		return err
+	}
+	if err = b.isAllowed(evCtx, presets.PermDelete, obj); err != nil {
+		return err
+	}
+	return eb.Deleter(obj, id, evCtx)
+}
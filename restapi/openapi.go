@@ -0,0 +1,222 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/theplant/relay"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaOf describes the Go type as an OpenAPI schema, types with custom JSON encoding are left open.
+func schemaOf(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]any{"type": "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		properties := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := f.Name
+			if tag := f.Tag.Get("json"); tag != "" {
+				if tag == "-" {
+					continue
+				}
+				if n, _, _ := strings.Cut(tag, ","); n != "" {
+					name = n
+				}
+			}
+			properties[name] = schemaOf(f.Type, seen)
+		}
+		return map[string]any{"type": "object", "properties": properties}
+	}
+	return map[string]any{}
+}
+
+// fieldsSchema describes the object with the fields as in the responses, which are keyed by the Go field names.
+func (b *ModelBuilder) fieldsSchema(fields []string) map[string]any {
+	t := b.modelType()
+	properties := map[string]any{}
+	for _, name := range fields {
+		f, ok := t.FieldByName(name)
+		if !ok {
+			continue
+		}
+		properties[name] = schemaOf(f.Type, map[reflect.Type]bool{})
+	}
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+func (b *ModelBuilder) schemaName() string {
+	return b.modelType().Name()
+}
+
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+func queryParam(name, typ, description string) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"in":          "query",
+		"description": description,
+		"schema":      map[string]any{"type": typ},
+	}
+}
+
+func errorResponses(codes ...string) map[string]any {
+	r := map[string]any{}
+	for _, code := range codes {
+		r[code] = map[string]any{
+			"description": "Error",
+			"content":     jsonContent(ref("Error")),
+		}
+	}
+	return r
+}
+
+func mergeResponses(ms ...map[string]any) map[string]any {
+	r := map[string]any{}
+	for _, m := range ms {
+		for k, v := range m {
+			r[k] = v
+		}
+	}
+	return r
+}
+
+func (b *ModelBuilder) paths() map[string]any {
+	name := b.schemaName()
+	tags := []string{b.mb.Info().Label()}
+	idParam := map[string]any{"name": "id", "in": "path", "required": true, "schema": map[string]any{"type": "string"}}
+	body := map[string]any{"required": true, "content": jsonContent(ref(name + "Input"))}
+	single := map[string]any{"description": name, "content": jsonContent(ref(name))}
+
+	return map[string]any{
+		b.href(): map[string]any{
+			"get": map[string]any{
+				"tags":        tags,
+				"operationId": "list" + name,
+				"parameters": []any{
+					queryParam(ParamKeyword, "string", "Keyword searched in the search columns of the listing"),
+					queryParam(ParamFilter, "string", "presets.Filter tree as JSON"),
+					queryParam(ParamOrderBy, "string", "Comma separated fields, prefixed with - for descending order"),
+					queryParam(ParamFirst, "integer", fmt.Sprintf("Relay pagination, at most %d", presets.PerPageMax)),
+					queryParam(ParamAfter, "string", "Relay pagination"),
+					queryParam(ParamLast, "integer", fmt.Sprintf("Relay pagination, at most %d", presets.PerPageMax)),
+					queryParam(ParamBefore, "string", "Relay pagination"),
+				},
+				"responses": mergeResponses(map[string]any{
+					"200": map[string]any{
+						"description": name + " list",
+						"content": jsonContent(map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"nodes":       map[string]any{"type": "array", "items": ref(name)},
+								"page_info":   ref("PageInfo"),
+								"total_count": map[string]any{"type": "integer"},
+							},
+						}),
+					},
+				}, errorResponses("400", "403")),
+			},
+			"post": map[string]any{
+				"tags":        tags,
+				"operationId": "create" + name,
+				"requestBody": body,
+				"responses":   mergeResponses(map[string]any{"201": single}, errorResponses("403", "422")),
+			},
+		},
+		b.href() + "/{id}": map[string]any{
+			"parameters": []any{idParam},
+			"get": map[string]any{
+				"tags":        tags,
+				"operationId": "get" + name,
+				"responses":   mergeResponses(map[string]any{"200": single}, errorResponses("403", "404")),
+			},
+			"put": map[string]any{
+				"tags":        tags,
+				"operationId": "update" + name,
+				"requestBody": body,
+				"responses":   mergeResponses(map[string]any{"200": single}, errorResponses("403", "404", "422")),
+			},
+			"delete": map[string]any{
+				"tags":        tags,
+				"operationId": "delete" + name,
+				"responses":   mergeResponses(map[string]any{"204": map[string]any{"description": "Deleted"}}, errorResponses("403", "404")),
+			},
+		},
+	}
+}
+
+// OpenAPI generates the OpenAPI 3 document of the endpoints from the fields of the models.
+func (b *Builder) OpenAPI() map[string]any {
+	paths := map[string]any{}
+	schemas := map[string]any{
+		"PageInfo": schemaOf(reflect.TypeOf(relay.PageInfo{}), map[reflect.Type]bool{}),
+		"Error":    schemaOf(reflect.TypeOf(ErrorResponse{}), map[reflect.Type]bool{}),
+	}
+	for _, m := range b.models {
+		for k, v := range m.paths() {
+			paths[k] = v
+		}
+		schemas[m.schemaName()] = m.fieldsSchema(m.getFields())
+		schemas[m.schemaName()+"Input"] = m.fieldsSchema(m.getWriteFields())
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   b.title,
+			"version": b.version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+		},
+	}
+}
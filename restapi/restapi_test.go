@@ -0,0 +1,139 @@
+package restapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/qor5/web/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/admin/v3/presets/gorm2op"
+	"github.com/qor5/admin/v3/restapi"
+)
+
+type Product struct {
+	ID    uint
+	Name  string
+	Code  string
+	Price int
+}
+
+func TestREST(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}))
+	for _, p := range []*Product{{Name: "Apple", Code: "A", Price: 3}, {Name: "Banana", Code: "B", Price: 2}, {Name: "Cherry", Code: "C", Price: 5}} {
+		require.NoError(t, db.Create(p).Error)
+	}
+
+	pb := presets.New().DataOperator(gorm2op.DataOperator(db))
+	mb := pb.Model(&Product{})
+	mb.Listing("Name", "Price").SearchColumns("name").
+		OrderableFields([]*presets.OrderableField{{FieldName: "Price"}})
+	mb.Editing("Name", "Price").ValidateFunc(func(obj interface{}, ctx *web.EventContext) (err web.ValidationErrors) {
+		if obj.(*Product).Name == "" {
+			err.FieldError("Name", "name is required")
+		}
+		return
+	})
+
+	api := restapi.New(pb)
+	api.Model(mb)
+
+	do := func(method, target, body string) (*httptest.ResponseRecorder, map[string]any) {
+		w := httptest.NewRecorder()
+		api.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		var resp map[string]any
+		if w.Body.Len() > 0 {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w, resp
+	}
+
+	w, resp := do("GET", "/api/products?order_by=-Price&first=2", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	nodes := resp["nodes"].([]any)
+	require.Len(t, nodes, 2)
+	assert.Equal(t, map[string]any{"ID": float64(3), "Name": "Cherry", "Price": float64(5)}, nodes[0])
+	pageInfo := resp["page_info"].(map[string]any)
+	assert.Equal(t, true, pageInfo["hasNextPage"])
+
+	w, resp = do("GET", "/api/products?order_by=-Price&first=2&after="+pageInfo["endCursor"].(string), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	nodes = resp["nodes"].([]any)
+	require.Len(t, nodes, 1)
+	assert.Equal(t, "Banana", nodes[0].(map[string]any)["Name"])
+
+	_, resp = do("GET", "/api/products?keyword=an", "")
+	assert.Len(t, resp["nodes"], 1)
+
+	_, resp = do("GET", "/api/products?filter="+url.QueryEscape(`{"condition":{"field":"Price","operator":"Gte","value":3}}`), "")
+	assert.Len(t, resp["nodes"], 2)
+	w, _ = do("GET", "/api/products?filter="+url.QueryEscape(`{"condition":{"field":"Secret","operator":"Eq","value":1}}`), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	// the columns and the relations which are not in the responses can't be filtered by
+	for _, field := range []string{"Code", "code", "Category.Name"} {
+		w, _ = do("GET", "/api/products?filter="+url.QueryEscape(`{"and":[{"condition":{"field":"Price","operator":"Gte","value":3}},{"not":{"condition":{"field":"`+field+`","operator":"StartsWith","value":"A"}}}]}`), "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, field)
+	}
+
+	w, _ = do("GET", "/api/products?first=x", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, _ = do("GET", "/api/products?last=-1", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w, resp = do("GET", "/api/products?first=100000", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, resp["nodes"], 3)
+
+	w, resp = do("GET", "/api/products/1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Apple", resp["Name"])
+	w, _ = do("GET", "/api/products/100", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w, resp = do("POST", "/api/products", `{"Name": "", "Code": "D"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, map[string]any{
+		"Name": []any{"name is required"},
+		"Code": []any{"field is not writable"},
+	}, resp["field_errors"])
+
+	w, resp = do("POST", "/api/products", `{"Name": "Durian", "Price": 9}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, float64(4), resp["ID"])
+
+	w, resp = do("PUT", "/api/products/4", `{"Price": 8}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "Durian", resp["Name"])
+	assert.Equal(t, float64(8), resp["Price"])
+
+	api.MaxBodyBytes(32)
+	w, _ = do("PUT", "/api/products/4", `{"Name": "`+strings.Repeat("x", 32)+`"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	api.MaxBodyBytes(1 << 20)
+
+	w, _ = do("DELETE", "/api/products/4", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w, _ = do("GET", "/api/products/4", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w, resp = do("GET", "/api/openapi.json", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, resp["paths"], "/api/products/{id}")
+	schemas := resp["components"].(map[string]any)["schemas"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"Name":  map[string]any{"type": "string"},
+			"Price": map[string]any{"type": "integer"},
+		},
+	}, schemas["ProductInput"])
+}
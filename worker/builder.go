@@ -34,6 +34,7 @@ type Builder struct {
 	jbs                  []*JobBuilder
 	mb                   *presets.ModelBuilder
 	getCurrentUserIDFunc func(r *http.Request) string
+	operatorContextFunc  func(ctx context.Context, operator string) (context.Context, error)
 	ab                   *activity.Builder
 }
 
@@ -119,6 +120,14 @@ func (b *Builder) GetCurrentUserIDFunc(f func(r *http.Request) string) *Builder
 	return b
 }
 
+// OperatorContextFunc rebuilds the identity of the operator, the id returned by GetCurrentUserIDFunc,
+// into the context of the requests which the jobs handed off from the listings run with,
+// so that the permissions and row scopes are evaluated for the operator.
+func (b *Builder) OperatorContextFunc(f func(ctx context.Context, operator string) (context.Context, error)) *Builder {
+	b.operatorContextFunc = f
+	return b
+}
+
 // Activity sets Activity Builder to log activities
 func (b *Builder) Activity(ab *activity.Builder) *Builder {
 	b.ab = ab
@@ -191,6 +200,7 @@ func (b *Builder) Install(pb *presets.Builder) error {
 		MenuIcon("mdi-briefcase")
 
 	b.mb = mb
+	b.installDashboardWidgets(pb)
 	mb.RegisterEventFunc("worker_selectJob", b.eventSelectJob)
 	mb.RegisterEventFunc("worker_abortJob", b.eventAbortJob)
 	mb.RegisterEventFunc("worker_rerunJob", b.eventRerunJob)
@@ -470,6 +480,11 @@ func (b *Builder) createJob(ctx *web.EventContext, qorJob *QorJob) (j *QorJob, e
 		return
 	}
 
+	return b.addJob(ctx, jb, args)
+}
+
+// addJob creates the QorJob with its first instance and adds it to the queue
+func (b *Builder) addJob(ctx *web.EventContext, jb *JobBuilder, args interface{}) (j *QorJob, err error) {
 	// encode context
 	context := make(map[string]interface{})
 	for key, v := range DefaultOriginalPageContextHandler(ctx) {
@@ -484,7 +499,7 @@ func (b *Builder) createJob(ctx *web.EventContext, qorJob *QorJob) (j *QorJob, e
 
 	err = b.db.Transaction(func(tx *gorm.DB) error {
 		j = &QorJob{
-			Job:    qorJob.Job,
+			Job:    jb.name,
 			Status: JobStatusNew,
 		}
 		err = tx.Create(j).Error
@@ -492,7 +507,7 @@ func (b *Builder) createJob(ctx *web.EventContext, qorJob *QorJob) (j *QorJob, e
 			return err
 		}
 		var inst *QorJobInstance
-		inst, err = jb.newJobInstance(ctx.R, j.ID, qorJob.Job, args, context)
+		inst, err = jb.newJobInstance(ctx.R, j.ID, jb.name, args, context)
 		if err != nil {
 			return err
 		}
@@ -599,7 +614,8 @@ func (b *Builder) eventRerunJob(ctx *web.EventContext) (er web.EventResponse, er
 	if err != nil {
 		return er, err
 	}
-	if old.Status != JobStatusDone {
+	resume := jb.canResume(old.Status)
+	if old.Status != JobStatusDone && !resume {
 		return er, errors.New("job is not done")
 	}
 
@@ -607,6 +623,11 @@ func (b *Builder) eventRerunJob(ctx *web.EventContext) (er web.EventResponse, er
 	if err != nil {
 		return er, err
 	}
+	if resume && old.Checkpoint != "" {
+		if err = inst.SetCheckpoint(old.Checkpoint); err != nil {
+			return er, err
+		}
+	}
 	err = b.setStatus(qorJobID, JobStatusNew)
 	if err != nil {
 		return er, err
@@ -819,6 +840,10 @@ func (b *Builder) jobProgressing(
 		}
 	}
 	inRefresh := status == JobStatusNew || status == JobStatusRunning
+	canResume := false
+	if jb := b.getJobBuilder(job); jb != nil {
+		canResume = jb.canResume(status)
+	}
 	eURL := path.Join(b.mb.Info().ListingHref(), fmt.Sprint(id))
 
 	// Set refresh interval based on job status
@@ -883,6 +908,15 @@ func (b *Builder) jobProgressing(
 							Query("job", job).
 							Go()),
 				),
+				If(canResume,
+					VBtn(msgr.ActionResumeJob).Color("primary").
+						Attr("@click", web.Plaid().
+							URL(eURL).
+							EventFunc("worker_rerunJob").
+							Query("jobID", fmt.Sprintf("%d", id)).
+							Query("job", job).
+							Go()),
+				),
 			),
 		),
 	)
@@ -960,3 +994,26 @@ func (b *Builder) jobEditingContent(
 	}
 	return jb.rmb.Editing().ToComponent(jb.rmb.Info(), argsObj, ctx)
 }
+
+// checkOperatorHandoff refuses to hand off the events of the model to the jobs if permissions are configured
+// but the identity of the operator can't be rebuilt in the job, see OperatorContextFunc.
+func (b *Builder) checkOperatorHandoff(mb *presets.ModelBuilder) error {
+	if mb.GetPresetsBuilder().GetPermission() == nil {
+		return nil
+	}
+	if b.getCurrentUserIDFunc == nil || b.operatorContextFunc == nil {
+		return errors.New("GetCurrentUserIDFunc and OperatorContextFunc of the worker are required to run the jobs with permissions")
+	}
+	return nil
+}
+
+// operatorRequest returns the request of the operator of the job for the handed off events.
+func (b *Builder) operatorRequest(ctx context.Context, method, url, operator string) (*http.Request, error) {
+	if b.operatorContextFunc != nil {
+		var err error
+		if ctx, err = b.operatorContextFunc(ctx, operator); err != nil {
+			return nil, err
+		}
+	}
+	return http.NewRequestWithContext(ctx, method, url, http.NoBody)
+}
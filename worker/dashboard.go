@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/i18n"
+	. "github.com/qor5/x/v3/ui/vuetify"
+	"github.com/samber/lo"
+	. "github.com/theplant/htmlgo"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+const (
+	DashboardWidgetMyJobs = "worker_my_jobs"
+
+	dashboardMyJobsLimit = 10
+)
+
+func (b *Builder) installDashboardWidgets(pb *presets.Builder) {
+	pb.Dashboard().Widget(DashboardWidgetMyJobs).
+		TitleFunc(func(ctx *web.EventContext) string {
+			return i18n.MustGetModuleMessages(ctx.R, I18nWorkerKey, Messages_en_US).(*Messages).DashboardMyJobs
+		}).
+		Icon("mdi-briefcase").
+		Width(4).
+		ComponentFunc(b.myJobsWidget)
+}
+
+// myJobsWidget lists the latest unfinished jobs created by the current user,
+// no jobs are listed if the current user is unknown without GetCurrentUserIDFunc.
+func (b *Builder) myJobsWidget(ctx *web.EventContext) (HTMLComponent, error) {
+	msgr := i18n.MustGetModuleMessages(ctx.R, I18nWorkerKey, Messages_en_US).(*Messages)
+
+	var insts []*QorJobInstance
+	if b.getCurrentUserIDFunc != nil {
+		if err := b.db.WithContext(ctx.R.Context()).
+			Where("status IN ? AND operator = ?", []string{JobStatusNew, JobStatusScheduled, JobStatusRunning}, b.getCurrentUserIDFunc(ctx.R)).
+			Order("created_at DESC").
+			Limit(dashboardMyJobsLimit).
+			Find(&insts).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(insts) == 0 {
+		return Div(Text(msgr.DashboardNoJobs)).Class("text-medium-emphasis"), nil
+	}
+
+	items := lo.Map(insts, func(inst *QorJobInstance, _ int) HTMLComponent {
+		return VListItem(
+			VListItemTitle(Text(getTJob(ctx.R, inst.Job))),
+			VListItemSubtitle(Text(lo.Ternary(inst.ProgressText != "", inst.ProgressText, getTStatus(msgr, inst.Status)))),
+			VProgressLinear().ModelValue(inst.Progress).Color(ColorPrimary).Class("mt-1"),
+		).Href(b.mb.Info().DetailingHref(fmt.Sprint(inst.QorJobID)))
+	})
+	return VList(items...).Density(DensityCompact), nil
+}
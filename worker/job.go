@@ -30,6 +30,7 @@ type JobBuilder struct {
 	h              JobHandler
 	contextHandler func(*web.EventContext) map[string]interface{} // optional
 	global         bool
+	resumable      bool
 }
 
 func newJob(b *Builder, name string) *JobBuilder {
@@ -99,6 +100,17 @@ func (jb *JobBuilder) Handler(h JobHandler) *JobBuilder {
 	return jb
 }
 
+// Resumable allows to rerun the job after it failed or got aborted,
+// the handler continues from the checkpoint it saved with JobCheckpointer.
+func (jb *JobBuilder) Resumable() *JobBuilder {
+	jb.resumable = true
+	return jb
+}
+
+func (jb *JobBuilder) canResume(status string) bool {
+	return jb.resumable && (status == JobStatusException || status == JobStatusKilled)
+}
+
 func (jb *JobBuilder) ContextHandler(handler func(*web.EventContext) map[string]interface{}) *JobBuilder {
 	jb.contextHandler = handler
 	return jb
@@ -238,6 +250,13 @@ type QorJobInterface interface {
 	AddLogf(format string, a ...interface{}) error
 }
 
+// JobCheckpointer is implemented by the jobs passed to handlers,
+// the checkpoint is kept when a resumable job is rerun.
+type JobCheckpointer interface {
+	GetCheckpoint() string
+	SetCheckpoint(string) error
+}
+
 var _ QueJobInterface = (*QorJobInstance)(nil)
 
 func (job *QorJobInstance) GetJobInfo() (ji *JobInfo, err error) {
@@ -335,6 +354,23 @@ func (job *QorJobInstance) SetProgressText(s string) error {
 	return nil
 }
 
+func (job *QorJobInstance) GetCheckpoint() string {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	return job.Checkpoint
+}
+
+// SetCheckpoint is saved immediately instead of with the next refresh,
+// so that a resumed job doesn't redo the finished work.
+func (job *QorJobInstance) SetCheckpoint(s string) error {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	job.Checkpoint = s
+	return job.jb.b.db.Model(&QorJobInstance{}).Where("id = ?", job.ID).Update("checkpoint", s).Error
+}
+
 func (job *QorJobInstance) AddLog(log string) error {
 	return job.jb.b.db.Create(&QorJobLog{
 		QorJobInstanceID: job.ID,
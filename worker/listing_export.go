@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"path"
+
+	"github.com/qor5/web/v3"
+	"github.com/qor5/x/v3/oss"
+
+	"github.com/qor5/admin/v3/presets"
+)
+
+// ListingExportJob registers a job which writes the export of the listing into storage,
+// pass the returned func to presets.ListingExportBuilder.JobHandoff so that large exports
+// don't block the request.
+// The job runs without the original request, so the columns are resolved when it is created,
+// and the rows are written as the operator of the job, see OperatorContextFunc.
+func (b *Builder) ListingExportJob(lb *presets.ListingBuilder, storage oss.StorageInterface) presets.ListingExportJobFunc {
+	mb := lb.GetModelBuilder()
+	name := fmt.Sprintf("Export - %s", mb.Info().Label())
+	jb := b.getJobBuilder(name)
+	if jb == nil {
+		jb = b.NewJob(name).Resource(&presets.ListingExportRequest{})
+		jb.global = false
+	}
+
+	jb.Handler(func(ctx context.Context, job QorJobInterface) error {
+		eb := lb.GetExport()
+		if eb == nil {
+			return fmt.Errorf("export is not enabled on %s", mb.Info().URIName())
+		}
+		info, err := job.GetJobInfo()
+		if err != nil {
+			return err
+		}
+		req, ok := info.Argument.(*presets.ListingExportRequest)
+		if !ok {
+			return fmt.Errorf("unexpected export argument %T", info.Argument)
+		}
+
+		r, err := b.operatorRequest(ctx, http.MethodGet, mb.Info().ListingHref(), info.Operator)
+		if err != nil {
+			return err
+		}
+		evCtx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+
+		_ = job.SetProgressText("exporting")
+		buf := &bytes.Buffer{}
+		if err = eb.Write(evCtx, req, buf); err != nil {
+			return err
+		}
+
+		filePath := path.Join("exports", fmt.Sprintf("%s-%s.%s", mb.Info().URIName(), info.JobID, req.Format))
+		if _, err = storage.Put(ctx, filePath, buf); err != nil {
+			return err
+		}
+		url, err := storage.GetURL(ctx, filePath)
+		if err != nil {
+			return err
+		}
+		_ = job.SetProgress(100)
+		_ = job.SetProgressText(fmt.Sprintf(`<a href="%s" target="_blank">%s</a>`, html.EscapeString(url), html.EscapeString(path.Base(filePath))))
+		return job.AddLogf("exported to %s", url)
+	})
+
+	return func(evCtx *web.EventContext, req *presets.ListingExportRequest, _ *web.EventResponse) error {
+		if err := editIsAllowed(evCtx.R, jb.name); err != nil {
+			return err
+		}
+		if err := b.checkOperatorHandoff(mb); err != nil {
+			return err
+		}
+		_, err := b.addJob(evCtx, jb, req)
+		return err
+	}
+}
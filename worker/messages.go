@@ -26,6 +26,7 @@ type Messages struct {
 	ActionAbortJob           string
 	ActionUpdateJob          string
 	ActionRerunJob           string
+	ActionResumeJob          string
 	DetailTitleStatus        string
 	DetailTitleLog           string
 	NoticeJobCannotBeAborted string
@@ -34,6 +35,8 @@ type Messages struct {
 	DateTimePickerClearText  string
 	DateTimePickerOkText     string
 	PleaseSelectJob          string
+	DashboardMyJobs          string
+	DashboardNoJobs          string
 }
 
 var Messages_en_US = &Messages{
@@ -53,6 +56,7 @@ var Messages_en_US = &Messages{
 	ActionAbortJob:           "Abort Job",
 	ActionUpdateJob:          "Update Job",
 	ActionRerunJob:           "Rerun Job",
+	ActionResumeJob:          "Resume Job",
 	DetailTitleStatus:        "Status",
 	DetailTitleLog:           "Log",
 	NoticeJobCannotBeAborted: "This job cannot be aborted/canceled/updated due to its status change",
@@ -61,6 +65,8 @@ var Messages_en_US = &Messages{
 	DateTimePickerClearText:  "Clear",
 	DateTimePickerOkText:     "OK",
 	PleaseSelectJob:          "Please select job",
+	DashboardMyJobs:          "My Running Jobs",
+	DashboardNoJobs:          "No running jobs",
 }
 
 var Messages_zh_CN = &Messages{
@@ -80,6 +86,7 @@ var Messages_zh_CN = &Messages{
 	ActionAbortJob:           "中止Job",
 	ActionUpdateJob:          "更新Job",
 	ActionRerunJob:           "重跑Job",
+	ActionResumeJob:          "继续Job",
 	DetailTitleStatus:        "状态",
 	DetailTitleLog:           "日志",
 	NoticeJobCannotBeAborted: "Job状态已经改变，不能被中止/取消/更新",
@@ -88,6 +95,8 @@ var Messages_zh_CN = &Messages{
 	DateTimePickerClearText:  "清空",
 	DateTimePickerOkText:     "确定",
 	PleaseSelectJob:          "请选择Job",
+	DashboardMyJobs:          "我的运行中Job",
+	DashboardNoJobs:          "没有运行中的Job",
 }
 
 func getTStatus(msgr *Messages, status string) string {
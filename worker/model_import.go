@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/qor5/web/v3"
+
+	"github.com/qor5/admin/v3/presets"
+	"github.com/qor5/admin/v3/presets/gorm2op"
+)
+
+// ModelImportJob registers a resumable job which saves the rows of the csv,
+// pass the returned func to presets.ImportBuilder.JobHandoff so that large imports
+// don't block the request. The index of the next row is saved as checkpoint after every batch,
+// so a failed or aborted import continues from there when it is resumed.
+// Each batch is saved in a transaction of the db of the worker unless ImportBuilder.Transact is set before,
+// and the rows are saved as the operator of the job, see OperatorContextFunc.
+func (b *Builder) ModelImportJob(mb *presets.ModelBuilder) presets.ImportJobFunc {
+	name := fmt.Sprintf("Import - %s", mb.Info().Label())
+	jb := b.getJobBuilder(name)
+	if jb == nil {
+		jb = b.NewJob(name).Resource(&presets.ImportRequest{}).Resumable()
+		jb.global = false
+	}
+
+	if ib := mb.GetImport(); ib != nil && ib.GetTransact() == nil {
+		ib.Transact(gorm2op.ImportTransact(b.db))
+	}
+
+	jb.Handler(func(ctx context.Context, job QorJobInterface) error {
+		ib := mb.GetImport()
+		if ib == nil {
+			return fmt.Errorf("import is not enabled on %s", mb.Info().URIName())
+		}
+		info, err := job.GetJobInfo()
+		if err != nil {
+			return err
+		}
+		req, ok := info.Argument.(*presets.ImportRequest)
+		if !ok {
+			return fmt.Errorf("unexpected import argument %T", info.Argument)
+		}
+		total := len(req.Records)
+		if total == 0 {
+			return job.SetProgress(100)
+		}
+
+		from := 0
+		cp, _ := job.(JobCheckpointer)
+		if cp != nil && cp.GetCheckpoint() != "" {
+			if from, err = strconv.Atoi(cp.GetCheckpoint()); err != nil {
+				return err
+			}
+			_ = job.AddLogf("resumed from line %d", from+2)
+		}
+
+		r, err := b.operatorRequest(ctx, http.MethodPost, mb.Info().ListingHref(), info.Operator)
+		if err != nil {
+			return err
+		}
+		evCtx := &web.EventContext{R: r, W: httptest.NewRecorder()}
+
+		report, err := ib.Run(evCtx, req, from, func(next int, rows []*presets.ImportRowResult) error {
+			for _, row := range rows {
+				if len(row.Errors) > 0 {
+					_ = job.AddLogf("line %d: %s", row.Line, strings.Join(row.Errors, "; "))
+				}
+			}
+			_ = job.SetProgress(uint(next * 100 / total))
+			if cp == nil {
+				return nil
+			}
+			return cp.SetCheckpoint(strconv.Itoa(next))
+		})
+		if err != nil {
+			return err
+		}
+		return job.SetProgressText(fmt.Sprintf("%d created, %d updated, %d skipped", report.Create, report.Update, len(report.Invalid)))
+	})
+
+	return func(evCtx *web.EventContext, req *presets.ImportRequest, _ *web.EventResponse) error {
+		if err := editIsAllowed(evCtx.R, jb.name); err != nil {
+			return err
+		}
+		if err := b.checkOperatorHandoff(mb); err != nil {
+			return err
+		}
+		_, err := b.addJob(evCtx, jb, req)
+		return err
+	}
+}
@@ -29,6 +29,7 @@ type QorJobInstance struct {
 
 	Progress     uint
 	ProgressText string
+	Checkpoint   string
 
 	jb          *JobBuilder `sql:"-"`
 	mutex       sync.Mutex  `sql:"-"`
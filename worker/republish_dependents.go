@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qor5/web/v3"
+
+	"github.com/qor5/admin/v3/publish"
+)
+
+// RepublishDependentsJob registers a job which republishes the dependents of the published or unpublished records,
+// pass the returned func to publish.Builder.RepublishJobFunc so that they are republished in the background with progress.
+func (b *Builder) RepublishDependentsJob(publisher *publish.Builder) publish.RepublishJobFunc {
+	name := "Republish Dependents"
+	jb := b.getJobBuilder(name)
+	if jb == nil {
+		jb = b.NewJob(name).Resource(&publish.RepublishRequest{})
+		jb.global = false
+	}
+
+	jb.Handler(func(ctx context.Context, job QorJobInterface) error {
+		info, err := job.GetJobInfo()
+		if err != nil {
+			return err
+		}
+		req, ok := info.Argument.(*publish.RepublishRequest)
+		if !ok {
+			return fmt.Errorf("unexpected republish argument %T", info.Argument)
+		}
+
+		total := len(req.Dependents)
+		_ = job.SetProgressText(fmt.Sprintf("0/%d", total))
+		_, err = publisher.RepublishDependents(ctx, req.Dependents, func(dependent *publish.Dependent, done int, skipped bool) {
+			if skipped {
+				_ = job.AddLogf("skipped %s %s %s, the review is not approved", dependent.Model, dependent.Slug, dependent.URL)
+			} else {
+				_ = job.AddLogf("republished %s %s %s", dependent.Model, dependent.Slug, dependent.URL)
+			}
+			_ = job.SetProgress(uint(done * 100 / total))
+			_ = job.SetProgressText(fmt.Sprintf("%d/%d", done, total))
+		})
+		return err
+	})
+
+	// the job is the side effect of the publish, which is permission-checked already
+	return func(evCtx *web.EventContext, req *publish.RepublishRequest) error {
+		_, err := b.addJob(evCtx, jb, req)
+		return err
+	}
+}